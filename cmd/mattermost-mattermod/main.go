@@ -5,6 +5,7 @@ package main
 
 import (
 	"flag"
+	"fmt"
 	"os"
 	"os/signal"
 	"syscall"
@@ -21,6 +22,10 @@ var (
 
 func init() {
 	flag.StringVar(&configFile, "config", "config-mattermod.json", "")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [-config file] [replay [-delivery id | -payload file -event type] [-dry-run]]\n", os.Args[0])
+		flag.PrintDefaults()
+	}
 }
 
 func main() {
@@ -36,6 +41,14 @@ func main() {
 		return
 	}
 
+	if flag.Arg(0) == "replay" {
+		if err = runReplay(config, flag.Args()[1:]); err != nil {
+			mlog.Error("unable to replay delivery", mlog.Err(err))
+			os.Exit(1)
+		}
+		return
+	}
+
 	// Metrics system
 	metricsProvider := metrics.NewPrometheusProvider()
 	metricsServer := metrics.NewServer(config.MetricsServerPort, metricsProvider.Handler(), true)
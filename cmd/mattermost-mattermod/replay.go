@@ -0,0 +1,59 @@
+// Copyright (c) 2017-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/mattermost/mattermost-mattermod/server"
+	"github.com/mattermost/mattermost-mattermod/store"
+)
+
+// runReplay implements the replay subcommand. The delivery is queued in the
+// database and picked up by the workers of the running server.
+func runReplay(config *server.Config, args []string) error {
+	fs := flag.NewFlagSet("replay", flag.ContinueOnError)
+	deliveryID := fs.String("delivery", "", "ID of a stored delivery to process again")
+	payloadFile := fs.String("payload", "", "path to a JSON payload file to process")
+	eventType := fs.String("event", "", "GitHub event type of the payload file, e.g. pull_request")
+	dryRun := fs.Bool("dry-run", false, "only parse and route the delivery")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	req := &server.ReplayRequest{
+		DeliveryID: *deliveryID,
+		EventType:  *eventType,
+		DryRun:     *dryRun,
+	}
+	if *payloadFile != "" {
+		payload, err := os.ReadFile(*payloadFile)
+		if err != nil {
+			return fmt.Errorf("could not read payload file: %w", err)
+		}
+		if !json.Valid(payload) {
+			return errors.New("payload file does not contain valid JSON")
+		}
+		req.Payload = payload
+	}
+
+	st, err := store.NewSQLStore(config.DriverName, config.DataSource)
+	if err != nil {
+		return err
+	}
+	defer st.Close()
+
+	result, err := server.ReplayDelivery(st, req)
+	if err != nil {
+		return err
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(result)
+}
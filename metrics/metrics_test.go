@@ -58,6 +58,59 @@ func TestMetrics(t *testing.T) {
 		require.Equal(t, float64(1), m.Counter.GetValue())
 	})
 
+	t.Run("Should store metrics for webhook signatures", func(t *testing.T) {
+		m := &prometheusModels.Metric{}
+		data, err := provider.webhookSignatures.GetMetricWithLabelValues("test", "sha256")
+		require.NoError(t, err)
+		require.NoError(t, data.Write(m))
+		require.Equal(t, float64(0), m.Counter.GetValue())
+		provider.IncreaseWebhookSignatureValidations("test", "sha256")
+		data, err = provider.webhookSignatures.GetMetricWithLabelValues("test", "sha256")
+		require.NoError(t, err)
+		require.NoError(t, data.Write(m))
+		require.Equal(t, float64(1), m.Counter.GetValue())
+	})
+
+	t.Run("Should store metrics for automations duration", func(t *testing.T) {
+		m := &prometheusModels.Metric{}
+		data, err := provider.automationsDuration.GetMetricWith(prometheus.Labels{"name": "cla", "hook": "comment"})
+		require.NoError(t, err)
+		require.NoError(t, data.(prometheus.Histogram).Write(m))
+		require.Equal(t, uint64(0), m.Histogram.GetSampleCount())
+		provider.ObserveAutomationDuration("cla", "comment", 1)
+		data, err = provider.automationsDuration.GetMetricWith(prometheus.Labels{"name": "cla", "hook": "comment"})
+		require.NoError(t, err)
+		require.NoError(t, data.(prometheus.Histogram).Write(m))
+		require.Equal(t, uint64(1), m.Histogram.GetSampleCount())
+		require.InDelta(t, 1, m.Histogram.GetSampleSum(), 0.001)
+	})
+
+	t.Run("Should store metrics for automations errors", func(t *testing.T) {
+		m := &prometheusModels.Metric{}
+		data, err := provider.automationsErrors.GetMetricWithLabelValues("cla", "comment")
+		require.NoError(t, err)
+		require.NoError(t, data.Write(m))
+		require.Equal(t, float64(0), m.Counter.GetValue())
+		provider.IncreaseAutomationErrors("cla", "comment")
+		data, err = provider.automationsErrors.GetMetricWithLabelValues("cla", "comment")
+		require.NoError(t, err)
+		require.NoError(t, data.Write(m))
+		require.Equal(t, float64(1), m.Counter.GetValue())
+	})
+
+	t.Run("Should store metrics for command denials", func(t *testing.T) {
+		m := &prometheusModels.Metric{}
+		data, err := provider.commandDenials.GetMetricWithLabelValues("cherry-pick")
+		require.NoError(t, err)
+		require.NoError(t, data.Write(m))
+		require.Equal(t, float64(0), m.Counter.GetValue())
+		provider.IncreaseCommandDenials("cherry-pick")
+		data, err = provider.commandDenials.GetMetricWithLabelValues("cherry-pick")
+		require.NoError(t, err)
+		require.NoError(t, data.Write(m))
+		require.Equal(t, float64(1), m.Counter.GetValue())
+	})
+
 	t.Run("Should store metrics for github requests duration", func(t *testing.T) {
 		m := &prometheusModels.Metric{}
 		data, err := provider.githubRequests.GetMetricWith(prometheus.Labels{"handler": "handler", "method": "method", "status_code": "200"})
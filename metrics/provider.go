@@ -12,10 +12,12 @@ import (
 )
 
 const (
-	metricsNamespace = "mattermod"
-	httpNamespace    = "requests"
-	cronNamespace    = "cron"
-	githubNamespace  = "github"
+	metricsNamespace    = "mattermod"
+	httpNamespace       = "requests"
+	cronNamespace       = "cron"
+	githubNamespace     = "github"
+	automationNamespace = "automations"
+	commandNamespace    = "commands"
 
 	defaultPrometheusTimeoutSeconds = 60
 )
@@ -28,6 +30,7 @@ type PrometheusProvider struct {
 	httpRequestsDuration *prometheus.HistogramVec
 	webhookEvents        *prometheus.CounterVec
 	webhookErrors        *prometheus.CounterVec
+	webhookSignatures    *prometheus.CounterVec
 
 	cronTasksDuration *prometheus.HistogramVec
 	cronTasksErrors   *prometheus.CounterVec
@@ -37,6 +40,11 @@ type PrometheusProvider struct {
 	githubCacheMisses *prometheus.CounterVec
 
 	rateLimiterErrors prometheus.Counter
+
+	automationsDuration *prometheus.HistogramVec
+	automationsErrors   *prometheus.CounterVec
+
+	commandDenials *prometheus.CounterVec
 }
 
 // NewPrometheusProvider creates a new prometheus metrics provider
@@ -84,6 +92,17 @@ func NewPrometheusProvider() *PrometheusProvider {
 	)
 	provider.Registry.MustRegister(provider.webhookErrors)
 
+	provider.webhookSignatures = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Subsystem: httpNamespace,
+			Name:      "webhook_signatures",
+			Help:      "Number of validated webhook signatures by secret and algorithm.",
+		},
+		[]string{"secret", "algorithm"},
+	)
+	provider.Registry.MustRegister(provider.webhookSignatures)
+
 	provider.cronTasksDuration = prometheus.NewHistogramVec(
 		prometheus.HistogramOpts{
 			Namespace: metricsNamespace,
@@ -149,6 +168,39 @@ func NewPrometheusProvider() *PrometheusProvider {
 	)
 	provider.Registry.MustRegister(provider.rateLimiterErrors)
 
+	provider.automationsDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: metricsNamespace,
+			Subsystem: automationNamespace,
+			Name:      "runs",
+			Help:      "Duration of the automation hooks by automation and hook.",
+		},
+		[]string{"name", "hook"},
+	)
+	provider.Registry.MustRegister(provider.automationsDuration)
+
+	provider.automationsErrors = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Subsystem: automationNamespace,
+			Name:      "errors",
+			Help:      "Number of failed automation hooks by automation and hook.",
+		},
+		[]string{"name", "hook"},
+	)
+	provider.Registry.MustRegister(provider.automationsErrors)
+
+	provider.commandDenials = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Subsystem: commandNamespace,
+			Name:      "denials",
+			Help:      "Number of commands denied to the commenter by command.",
+		},
+		[]string{"name"},
+	)
+	provider.Registry.MustRegister(provider.commandDenials)
+
 	return provider
 }
 
@@ -172,6 +224,10 @@ func (p *PrometheusProvider) IncreaseWebhookErrors(name string) {
 	p.webhookErrors.WithLabelValues(name).Add(1)
 }
 
+func (p *PrometheusProvider) IncreaseWebhookSignatureValidations(secretID, algorithm string) {
+	p.webhookSignatures.WithLabelValues(secretID, algorithm).Add(1)
+}
+
 func (p *PrometheusProvider) ObserveCronTaskDuration(name string, elapsed float64) {
 	p.cronTasksDuration.With(prometheus.Labels{"name": name}).Observe(elapsed)
 }
@@ -192,6 +248,18 @@ func (p *PrometheusProvider) IncreaseRateLimiterErrors() {
 	p.rateLimiterErrors.Add(1)
 }
 
+func (p *PrometheusProvider) ObserveAutomationDuration(name, hook string, elapsed float64) {
+	p.automationsDuration.With(prometheus.Labels{"name": name, "hook": hook}).Observe(elapsed)
+}
+
+func (p *PrometheusProvider) IncreaseAutomationErrors(name, hook string) {
+	p.automationsErrors.WithLabelValues(name, hook).Add(1)
+}
+
+func (p *PrometheusProvider) IncreaseCommandDenials(name string) {
+	p.commandDenials.WithLabelValues(name).Add(1)
+}
+
 // Handler returns the handler that would be used by the metrics server to expose
 // the metrics.
 func (p *PrometheusProvider) Handler() Handler {
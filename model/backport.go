@@ -0,0 +1,30 @@
+// Copyright (c) 2017-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package model
+
+const (
+	BackportQueued = "queued"
+	BackportFailed = "failed"
+	BackportOpen   = "open"
+	BackportMerged = "merged"
+	BackportClosed = "closed"
+)
+
+// Backport links a PR to its automated cherry pick onto one branch. A PR has
+// at most one backport per branch, which follows the latest cherry pick.
+type Backport struct {
+	RepoOwner string `json:"repo_owner"`
+	RepoName  string `json:"repo_name"`
+	// Number is the number of the original PR.
+	Number int    `json:"number"`
+	Branch string `json:"branch"`
+	// Milestone is the title of the milestone of the original PR.
+	Milestone string `json:"milestone"`
+	// BackportNumber is the number of the cherry pick PR, or 0 while there
+	// is none.
+	BackportNumber int    `json:"backport_number"`
+	State          string `json:"state"`
+	CreatedAt      int64  `json:"created_at"`
+	UpdatedAt      int64  `json:"updated_at"`
+}
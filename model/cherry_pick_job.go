@@ -0,0 +1,56 @@
+// Copyright (c) 2017-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package model
+
+const (
+	CherryPickJobPending   = "pending"
+	CherryPickJobRunning   = "running"
+	CherryPickJobSucceeded = "succeeded"
+	CherryPickJobFailed    = "failed"
+)
+
+const (
+	CherryPickJobKindCherryPick = "cherry-pick"
+	// CherryPickJobKindPreview tries out the cherry pick of an open PR.
+	CherryPickJobKindPreview = "preview"
+	// CherryPickJobKindForwardPort looks for the changes of a PR merged into
+	// a release branch on the default branch.
+	CherryPickJobKindForwardPort = "forward-port"
+)
+
+// CherryPickJob is the cherry pick of a merged PR onto one branch, persisted
+// until a worker has run it. The workers also run the other jobs which need a
+// checkout of the repository, told apart by their Kind.
+type CherryPickJob struct {
+	ID        int64
+	Kind      string
+	RepoOwner string
+	RepoName  string
+	Number    int
+	// Branch is the branch to cherry pick onto. Previews keep the base
+	// branch of the PR, forward port checks the default branch.
+	Branch         string
+	MergeCommitSHA string
+	// Milestone is the number of the milestone given to the new PR, or 0.
+	Milestone int
+	State     string
+	Attempts  int
+	// NextAttemptAt is when a job retried after a transient error is run
+	// again.
+	NextAttemptAt int64
+	// Output is the output of the last attempt which failed.
+	Output string
+	// Conflicts lists the conflicting files of the last attempt, one per
+	// line.
+	Conflicts   string
+	NewPRNumber int
+	CreatedAt   int64
+	UpdatedAt   int64
+}
+
+// IsCherryPick returns true if the job cherry picks the PR, as opposed to the
+// other kinds of jobs.
+func (j *CherryPickJob) IsCherryPick() bool {
+	return j.Kind == "" || j.Kind == CherryPickJobKindCherryPick
+}
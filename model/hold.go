@@ -0,0 +1,15 @@
+// Copyright (c) 2017-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package model
+
+// Hold blocks the merge of a PR until it is lifted. It is placed with the
+// /hold command, and every user has at most one hold per PR.
+type Hold struct {
+	RepoOwner string
+	RepoName  string
+	Number    int
+	Holder    string
+	Reason    string
+	CreatedAt int64
+}
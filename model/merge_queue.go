@@ -0,0 +1,14 @@
+// Copyright (c) 2017-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package model
+
+// MergeQueueEntry is a PR with the auto merge label waiting for its turn in
+// the merge queue of its base branch. PRs are merged in the order they
+// joined the queue.
+type MergeQueueEntry struct {
+	RepoOwner string
+	RepoName  string
+	Number    int
+	CreatedAt int64
+}
@@ -0,0 +1,15 @@
+// Copyright (c) 2017-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package model
+
+// PendingMerge is a merge requested with the /merge command, waiting for the
+// PR to be ready.
+type PendingMerge struct {
+	RepoOwner   string
+	RepoName    string
+	Number      int
+	MergeMethod string
+	RequestedBy string
+	CreatedAt   int64
+}
@@ -0,0 +1,16 @@
+// Copyright (c) 2017-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package model
+
+import "time"
+
+// GetMillis returns the current unix time in milliseconds.
+func GetMillis() int64 {
+	return GetMillisForTime(time.Now())
+}
+
+// GetMillisForTime returns the unix time of t in milliseconds.
+func GetMillisForTime(t time.Time) int64 {
+	return t.UnixNano() / int64(time.Millisecond)
+}
@@ -0,0 +1,25 @@
+// Copyright (c) 2017-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package model
+
+const (
+	DeliveryStatePending   = "pending"
+	DeliveryStateRunning   = "running"
+	DeliveryStateSucceeded = "succeeded"
+	DeliveryStateFailed    = "failed"
+)
+
+// WebhookDelivery is a GitHub webhook delivery persisted before processing,
+// keyed by the X-GitHub-Delivery header.
+type WebhookDelivery struct {
+	DeliveryID    string
+	EventType     string
+	Payload       []byte
+	State         string
+	LastError     string
+	Attempts      int
+	NextAttemptAt int64
+	CreatedAt     int64
+	UpdatedAt     int64
+}
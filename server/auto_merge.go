@@ -6,6 +6,7 @@ package server
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/mattermost/mattermost-mattermod/model"
@@ -14,6 +15,56 @@ import (
 	"github.com/mattermost/mattermost-server/v6/shared/mlog"
 )
 
+// autoMergeAutomation adds PRs to the merge queue when the auto merge label
+// is added, and removes them when it is removed. The merging itself is done
+// by AutoMergePR.
+type autoMergeAutomation struct {
+	baseAutomation
+	s *Server
+}
+
+func (a *autoMergeAutomation) Name() string {
+	return "auto-merge"
+}
+
+func (a *autoMergeAutomation) OnPullRequest(ctx context.Context, event *pullRequestEvent, pr *model.PullRequest) error {
+	if event.Label.GetName() != a.s.Config.AutoPRMergeLabel {
+		return nil
+	}
+
+	switch event.Action {
+	case prEventLabeled:
+		err := a.s.Store.MergeQueue().Add(&model.MergeQueueEntry{RepoOwner: pr.RepoOwner, RepoName: pr.RepoName, Number: pr.Number})
+		if err != nil {
+			return err
+		}
+		msg := "Added this PR to the merge queue. It will be merged once it's its turn and all tests and checks are passing. This might take up to an hour."
+		return a.s.sendGitHubComment(ctx, pr.RepoOwner, pr.RepoName, pr.Number, msg)
+	case prEventUnLabeled:
+		if err := a.s.Store.MergeQueue().Delete(pr.RepoOwner, pr.RepoName, pr.Number); err != nil {
+			return err
+		}
+		return a.s.createRepoStatus(ctx, pr, &github.RepoStatus{
+			Context:     github.String(mergeQueueContext),
+			State:       github.String(stateSuccess),
+			Description: github.String("Not in the merge queue"),
+			TargetURL:   github.String(""),
+		})
+	}
+	return nil
+}
+
+const defaultMergeMethod = "squash"
+
+// mergeMethods are the merge methods which can be requested with /merge.
+var mergeMethods = []string{"squash", "merge", "rebase"}
+
+const (
+	msgUnknownMergeMethod = "Unknown merge method `%s`. Please use one of: squash, merge or rebase."
+	msgMergeClosedPR      = "This PR is closed, there is nothing to merge."
+	msgMergeScheduled     = "Will merge this PR (%s) once it's ready. Right now %s."
+)
+
 func (s *Server) AutoMergePR() error {
 	mlog.Info("Starting the process to auto merge PRs")
 	start := time.Now()
@@ -28,118 +79,342 @@ func (s *Server) AutoMergePR() error {
 		return fmt.Errorf("error while listing open PRs %w", err)
 	}
 
-	for _, pr := range prs {
-		var autoMergePr = s.hasAutoMerge(pr.Labels)
-		if !autoMergePr {
-			continue
-		}
+	if err = s.runMergeQueues(ctx, s.autoMergePRs(prs)); err != nil {
+		return err
+	}
 
-		ghPR, _, err := s.GithubClient.PullRequests.Get(ctx, pr.RepoOwner, pr.RepoName, pr.Number)
-		if err != nil {
-			mlog.Error("Error in getting the PR info",
-				mlog.Int("pr", pr.Number),
-				mlog.String("repo", pr.RepoName),
-				mlog.Err(err))
-			continue
+	// Pending merges are merged on status events. This catches up on events
+	// which were missed.
+	merges, err := s.Store.PendingMerge().List()
+	if err != nil {
+		return fmt.Errorf("error while listing pending merges %w", err)
+	}
+	for _, merge := range merges {
+		if mergeErr := s.tryPendingMerge(ctx, merge); mergeErr != nil {
+			mlog.Error("Error trying a pending merge",
+				mlog.Int("pr", merge.Number),
+				mlog.String("repo", merge.RepoName),
+				mlog.Err(mergeErr))
 		}
+	}
 
-		if ghPR.GetState() == model.StateClosed {
-			continue
-		}
+	mlog.Info("Done with the process to auto merge PRs")
+	return nil
+}
 
-		if ghPR.GetMergeableState() != model.MergeableStateClean {
-			mlog.Debug("PR is not ready to merge; unclean merge state",
-				mlog.Int("pr", pr.Number),
-				mlog.String("repo", pr.RepoName),
-				mlog.String("mergeableState", ghPR.GetMergeableState()))
-			continue
+// checkMergeReadiness checks whether the PR can be merged: its merge state
+// has to be clean, its combined status successful and no reviews may be
+// pending. The returned reason is empty if the PR is ready, and otherwise
+// tells why it isn't.
+func (s *Server) checkMergeReadiness(ctx context.Context, pr *model.PullRequest) (*github.PullRequest, string, error) {
+	ghPR, _, err := s.GithubClient.PullRequests.Get(ctx, pr.RepoOwner, pr.RepoName, pr.Number)
+	if err != nil {
+		return nil, "", fmt.Errorf("error in getting the PR info: %w", err)
+	}
+
+	readiness, err := s.assessMergeReadiness(ctx, pr, ghPR)
+	if err != nil {
+		return ghPR, "", err
+	}
+	return ghPR, readiness.reason, nil
+}
+
+// mergeReadiness tells whether a PR can be merged.
+type mergeReadiness struct {
+	// reason tells why the PR can't be merged yet. It is empty if it can.
+	reason string
+	// waiting is set if the PR can't be merged only until GitHub is done
+	// computing its merge state, its checks are done running or its branch
+	// is updated with the base branch.
+	waiting bool
+}
+
+// Merge states of PRs which may still be merged once their checks are done.
+const (
+	mergeableStateBlocked  = "blocked"
+	mergeableStateBehind   = "behind"
+	mergeableStateUnstable = "unstable"
+	mergeableStateUnknown  = "unknown"
+)
+
+// assessMergeReadiness checks the readiness of the PR as fetched from GitHub.
+func (s *Server) assessMergeReadiness(ctx context.Context, pr *model.PullRequest, ghPR *github.PullRequest) (mergeReadiness, error) {
+	if ghPR.GetState() == model.StateClosed {
+		return mergeReadiness{reason: "the PR is closed"}, nil
+	}
+
+	// Blocked, behind and unstable PRs may only wait for their checks, which
+	// the combined status tells.
+	mergeableState := ghPR.GetMergeableState()
+	switch mergeableState {
+	case model.MergeableStateClean, mergeableStateBlocked, mergeableStateBehind, mergeableStateUnstable:
+	default:
+		mlog.Info("PR is not ready to merge; unclean merge state",
+			mlog.Int("pr", pr.Number),
+			mlog.String("repo", pr.RepoName),
+			mlog.String("mergeableState", mergeableState))
+		return mergeReadiness{
+			reason:  fmt.Sprintf("the merge state is `%s`", mergeableState),
+			waiting: mergeableState == mergeableStateUnknown,
+		}, nil
+	}
+
+	// Get the Statuses
+	prStatus, _, err := s.GithubClient.Repositories.GetCombinedStatus(ctx, pr.RepoOwner, pr.RepoName, ghPR.Head.GetSHA(), nil)
+	if err != nil {
+		return mergeReadiness{}, fmt.Errorf("error in getting the PR status: %w", err)
+	}
+
+	if ghPR.Head.GetSHA() != prStatus.GetSHA() {
+		mlog.Error("PR is not ready to merge; mismatch in SHA",
+			mlog.Int("pr", pr.Number),
+			mlog.String("repo", pr.RepoName),
+			mlog.String("SHAFromPR", ghPR.Head.GetSHA()),
+			mlog.String("SHAFromStatus", prStatus.GetSHA()))
+		return mergeReadiness{reason: "the status doesn't match the head of the PR", waiting: true}, nil
+	}
+
+	for _, status := range prStatus.Statuses {
+		// Holds and blocking labels keep the merge/blocked status pending,
+		// which must not be taken for a running check.
+		if status.GetContext() == mergeBlockedContext && status.GetState() != stateSuccess {
+			return mergeReadiness{reason: "the merge is blocked"}, nil
 		}
+	}
 
-		// Get the Statuses
-		prStatus, _, err := s.GithubClient.Repositories.GetCombinedStatus(ctx, pr.RepoOwner, pr.RepoName, ghPR.Head.GetSHA(), nil)
-		if err != nil {
-			mlog.Error("Error in getting the PR Status",
+	protection, err := s.getBranchProtection(ctx, pr.RepoOwner, pr.RepoName, ghPR.GetBase().GetRef())
+	if err != nil {
+		return mergeReadiness{}, err
+	}
+	if contexts := requiredContexts(protection); len(contexts) > 0 {
+		// Check runs, e.g. of GitHub Actions, aren't part of the combined
+		// status.
+		runs, runsErr := s.getCheckRuns(ctx, pr.RepoOwner, pr.RepoName, ghPR.Head.GetSHA())
+		if runsErr != nil {
+			return mergeReadiness{}, fmt.Errorf("error in getting the PR check runs: %w", runsErr)
+		}
+		if readiness := requiredChecksReadiness(contexts, prStatus.Statuses, runs); readiness.reason != "" {
+			mlog.Info("PR is not ready to merge; required check not successful",
 				mlog.Int("pr", pr.Number),
 				mlog.String("repo", pr.RepoName),
-				mlog.Err(err))
-			continue
+				mlog.String("reason", readiness.reason))
+			return readiness, nil
 		}
+	}
 
-		if ghPR.Head.GetSHA() != prStatus.GetSHA() {
-			mlog.Error("PR is not ready to merge; mismatch in SHA",
+	// The combined status of a commit without statuses is pending, although
+	// there is nothing to wait for.
+	if len(prStatus.Statuses) > 0 && prStatus.GetState() != stateSuccess {
+		for _, status := range prStatus.Statuses {
+			mlog.Debug("status",
 				mlog.Int("pr", pr.Number),
 				mlog.String("repo", pr.RepoName),
-				mlog.String("SHAFromPR", ghPR.Head.GetSHA()),
-				mlog.String("SHAFromStatus", prStatus.GetSHA()))
-			continue
+				mlog.String("state", status.GetState()),
+				mlog.String("description", status.GetDescription()),
+				mlog.String("context", status.GetContext()),
+				mlog.String("target_url", status.GetTargetURL()),
+			)
 		}
 
-		if prStatus.GetState() != stateSuccess {
-			for _, status := range prStatus.Statuses {
-				mlog.Debug("status",
-					mlog.Int("pr", pr.Number),
-					mlog.String("repo", pr.RepoName),
-					mlog.String("state", status.GetState()),
-					mlog.String("description", status.GetDescription()),
-					mlog.String("context", status.GetContext()),
-					mlog.String("target_url", status.GetTargetURL()),
-				)
-			}
+		mlog.Error("PR is not ready to merge; combined status state is not success",
+			mlog.Int("pr", pr.Number),
+			mlog.String("repo", pr.RepoName),
+			mlog.String("state", prStatus.GetState()))
+		return mergeReadiness{
+			reason:  fmt.Sprintf("the combined status is `%s`", prStatus.GetState()),
+			waiting: prStatus.GetState() == statePending,
+		}, nil
+	}
 
-			mlog.Error("PR is not ready to merge; combined status state is not success",
-				mlog.Int("pr", pr.Number),
-				mlog.String("repo", pr.RepoName),
-				mlog.String("state", prStatus.GetState()))
-			continue
-		}
+	reviews, err := s.getReviews(ctx, pr.RepoOwner, pr.RepoName, pr.Number)
+	if err != nil {
+		return mergeReadiness{}, fmt.Errorf("error in getting the PR reviews: %w", err)
+	}
+	if readiness := reviewsReadiness(protection, reviews, ghPR.GetUser().GetLogin()); readiness.reason != "" {
+		mlog.Info("PR is not ready to merge; reviews missing",
+			mlog.Int("pr", pr.Number),
+			mlog.String("repo", pr.RepoName),
+			mlog.String("reason", readiness.reason))
+		return readiness, nil
+	}
 
-		// Check if all reviewers did the review
-		prReviewers, _, err := s.GithubClient.PullRequests.ListReviewers(ctx, pr.RepoOwner, pr.RepoName, pr.Number, nil)
-		if err != nil {
-			mlog.Error("Error to get the reviewers for the PR",
-				mlog.Int("pr", pr.Number),
-				mlog.String("repo", pr.RepoName),
-				mlog.Err(err))
-			continue
-		}
+	if mergeableState != model.MergeableStateClean {
+		return mergeReadiness{
+			reason:  fmt.Sprintf("the merge state is `%s`", mergeableState),
+			waiting: mergeableState == mergeableStateBehind,
+		}, nil
+	}
 
-		if len(prReviewers.Users) != 0 || len(prReviewers.Teams) != 0 {
-			mlog.Debug("PR is not ready to merge; pending reviewers",
-				mlog.Int("pr", pr.Number),
-				mlog.String("repo", pr.RepoName))
-			continue
-		}
+	// Check if all reviewers did the review
+	prReviewers, _, err := s.GithubClient.PullRequests.ListReviewers(ctx, pr.RepoOwner, pr.RepoName, pr.Number, nil)
+	if err != nil {
+		return mergeReadiness{}, fmt.Errorf("error to get the reviewers for the PR: %w", err)
+	}
+
+	if len(prReviewers.Users) != 0 || len(prReviewers.Teams) != 0 {
+		mlog.Info("PR is not ready to merge; pending reviewers",
+			mlog.Int("pr", pr.Number),
+			mlog.String("repo", pr.RepoName))
+		return mergeReadiness{reason: "reviews are pending"}, nil
+	}
+
+	return mergeReadiness{}, nil
+}
 
-		msg := "Trying to auto merge this PR."
-		if err = s.sendGitHubComment(ctx, pr.RepoOwner, pr.RepoName, pr.Number, msg); err != nil {
-			mlog.Warn("Error while commenting", mlog.Err(err))
+// mergePR merges the PR at the head checked by checkMergeReadiness, and
+// comments the result. Errors are commented on the PR as well.
+func (s *Server) mergePR(ctx context.Context, pr *model.PullRequest, ghPR *github.PullRequest, method string) error {
+	title, body, err := s.mergeCommitMessage(ctx, pr, ghPR, s.mergePolicy(pr.RepoOwner, pr.RepoName))
+	if err != nil {
+		errMsg := fmt.Sprintf("Error while trying to automerge the PR\nErr %s", err.Error())
+		if cErr := s.sendGitHubComment(ctx, pr.RepoOwner, pr.RepoName, pr.Number, errMsg); cErr != nil {
+			mlog.Warn("Error while commenting", mlog.Err(cErr))
 		}
+		return err
+	}
 
-		// All good to merge
-		opt := &github.PullRequestOptions{
-			SHA:         ghPR.Head.GetSHA(),
-			MergeMethod: "squash",
+	// All good to merge
+	opt := &github.PullRequestOptions{
+		CommitTitle:        title,
+		SHA:                ghPR.Head.GetSHA(),
+		MergeMethod:        method,
+		DontDefaultIfBlank: true,
+	}
+
+	merged, _, err := s.GithubClient.PullRequests.Merge(ctx, pr.RepoOwner, pr.RepoName, pr.Number, body, opt)
+	if err != nil {
+		errMsg := fmt.Sprintf("Error while trying to automerge the PR\nErr %s", err.Error())
+		if cErr := s.sendGitHubComment(ctx, pr.RepoOwner, pr.RepoName, pr.Number, errMsg); cErr != nil {
+			mlog.Warn("Error while commenting", mlog.Err(cErr))
 		}
+		return fmt.Errorf("could not merge the PR: %w", err)
+	}
 
-		merged, _, err := s.GithubClient.PullRequests.Merge(ctx, pr.RepoOwner, pr.RepoName, pr.Number, "Automatic Merge", opt)
-		if err != nil {
-			errMsg := fmt.Sprintf("Error while trying to automerge the PR\nErr %s", err.Error())
-			if err = s.sendGitHubComment(ctx, pr.RepoOwner, pr.RepoName, pr.Number, errMsg); err != nil {
+	msg := fmt.Sprintf("%s\nSHA: %s", merged.GetMessage(), merged.GetSHA())
+	if err = s.sendGitHubComment(ctx, pr.RepoOwner, pr.RepoName, pr.Number, msg); err != nil {
+		mlog.Warn("Error while commenting", mlog.Err(err))
+	}
+	return nil
+}
+
+// handleMerge merges the PR if it is ready. Otherwise the merge is stored and
+// done once a status event makes the PR ready.
+func (s *Server) handleMerge(ctx context.Context, commenter string, args []string, pr *model.PullRequest) error {
+	method := ""
+	if len(args) > 0 {
+		method = strings.ToLower(args[0])
+		if !isMergeMethod(method) {
+			if err := s.sendGitHubComment(ctx, pr.RepoOwner, pr.RepoName, pr.Number, fmt.Sprintf(msgUnknownMergeMethod, method)); err != nil {
 				mlog.Warn("Error while commenting", mlog.Err(err))
 			}
-			continue
+			return fmt.Errorf("unknown merge method %q", method)
 		}
+	}
 
-		msg = fmt.Sprintf("%s\nSHA: %s", merged.GetMessage(), merged.GetSHA())
-		if err = s.sendGitHubComment(ctx, pr.RepoOwner, pr.RepoName, pr.Number, msg); err != nil {
-			mlog.Warn("Error while commenting", mlog.Err(err))
+	ghPR, reason, err := s.checkMergeReadiness(ctx, pr)
+	if err != nil {
+		return err
+	}
+	if ghPR.GetState() == model.StateClosed {
+		if err = s.sendGitHubComment(ctx, pr.RepoOwner, pr.RepoName, pr.Number, msgMergeClosedPR); err != nil {
+			return err
 		}
+		return fmt.Errorf("%w: the PR is closed", errNothingToDo)
+	}
+	if method == "" {
+		method = mergeMethod(s.mergePolicy(pr.RepoOwner, pr.RepoName), ghPR.Labels)
+	}
+	if reason == "" {
+		return s.mergePR(ctx, pr, ghPR, method)
 	}
 
-	mlog.Info("Done with the process to auto merge PRs")
+	err = s.Store.PendingMerge().Save(&model.PendingMerge{
+		RepoOwner:   pr.RepoOwner,
+		RepoName:    pr.RepoName,
+		Number:      pr.Number,
+		MergeMethod: method,
+		RequestedBy: commenter,
+	})
+	if err != nil {
+		return err
+	}
+	return s.sendCommandComment(ctx, pr.RepoOwner, pr.RepoName, pr.Number, fmt.Sprintf(msgMergeScheduled, method, reason))
+}
+
+// mergePendingPRs tries the pending merges of the PRs whose head is at the
+// given commit.
+func (s *Server) mergePendingPRs(ctx context.Context, owner, name, sha string) error {
+	prs, err := s.Store.PullRequest().ListBySha(owner, name, sha)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, pr := range prs {
+		merge, getErr := s.Store.PendingMerge().Get(pr.RepoOwner, pr.RepoName, pr.Number)
+		if getErr != nil {
+			errs = append(errs, getErr)
+			continue
+		}
+		if merge == nil {
+			continue
+		}
+		errs = append(errs, s.tryPendingMerge(ctx, merge))
+	}
+	return joinErrors(errs...)
+}
+
+// tryPendingMerge merges the PR of a pending merge if it is ready. The pending
+// merge is removed once the PR has been merged, the merge failed or the PR
+// was closed.
+func (s *Server) tryPendingMerge(ctx context.Context, merge *model.PendingMerge) error {
+	pr := &model.PullRequest{
+		RepoOwner: merge.RepoOwner,
+		RepoName:  merge.RepoName,
+		Number:    merge.Number,
+	}
+	ghPR, reason, err := s.checkMergeReadiness(ctx, pr)
+	if err != nil {
+		return err
+	}
+	if ghPR.GetState() != model.StateClosed && reason != "" {
+		return nil
+	}
+
+	// Events for the same commit are handled concurrently, only the one
+	// claiming the pending merge goes on with it.
+	claimed, err := s.Store.PendingMerge().Claim(merge)
+	if err != nil || !claimed {
+		return err
+	}
+	if ghPR.GetState() != model.StateClosed {
+		// The error has been commented on the PR already, trying again
+		// wouldn't help.
+		_ = s.mergePR(ctx, pr, ghPR, merge.MergeMethod)
+	}
 	return nil
 }
 
+func isMergeMethod(method string) bool {
+	for _, m := range mergeMethods {
+		if m == method {
+			return true
+		}
+	}
+	return false
+}
+
+// autoMergePRs returns the PRs with the auto merge label.
+func (s *Server) autoMergePRs(prs []*model.PullRequest) []*model.PullRequest {
+	var result []*model.PullRequest
+	for _, pr := range prs {
+		if s.hasAutoMerge(pr.Labels) {
+			result = append(result, pr)
+		}
+	}
+	return result
+}
+
 func (s *Server) hasAutoMerge(labels []string) bool {
 	for _, label := range labels {
 		if label == s.Config.AutoPRMergeLabel {
@@ -5,6 +5,7 @@ package server
 
 import (
 	"context"
+	"net/http"
 	"reflect"
 	"testing"
 
@@ -41,6 +42,31 @@ func TestAutoMergePR(t *testing.T) {
 		PullRequest().
 		Return(prStoreMock).
 		AnyTimes()
+	pmStoreMock := stmock.NewMockPendingMergeStore(ctrl)
+	pmStoreMock.EXPECT().
+		List().
+		Return(nil, nil).
+		AnyTimes()
+	ss.EXPECT().
+		PendingMerge().
+		Return(pmStoreMock).
+		AnyTimes()
+	mqStoreMock := stmock.NewMockMergeQueueStore(ctrl)
+	mqStoreMock.EXPECT().
+		List().
+		Return(nil, nil).
+		AnyTimes()
+	mqStoreMock.EXPECT().
+		Add(&model.MergeQueueEntry{RepoOwner: "admin", RepoName: "mattermod", Number: 42}).
+		Return(nil).
+		AnyTimes()
+	ss.EXPECT().
+		MergeQueue().
+		Return(mqStoreMock).
+		AnyTimes()
+
+	ok := &github.Response{Response: &http.Response{StatusCode: http.StatusOK}}
+	notProtected := &github.Response{Response: &http.Response{StatusCode: http.StatusNotFound}}
 
 	metricsMock := srmock.NewMockMetricsProvider(ctrl)
 	metricsMock.EXPECT().ObserveCronTaskDuration(gomock.Any(), gomock.Any()).AnyTimes()
@@ -48,11 +74,15 @@ func TestAutoMergePR(t *testing.T) {
 
 	t.Run("Basic", func(t *testing.T) {
 		ghPR := &github.PullRequest{
+			Title:          github.String("Fix the thing"),
 			State:          github.String("open"),
 			MergeableState: github.String("clean"),
 			Head: &github.PullRequestBranch{
 				SHA: github.String("sha"),
 			},
+			Base: &github.PullRequestBranch{
+				Ref: github.String("master"),
+			},
 		}
 
 		ghStatus := &github.CombinedStatus{
@@ -78,6 +108,23 @@ func TestAutoMergePR(t *testing.T) {
 				gomock.Eq(ghPR.Head.GetSHA()),
 				nil).
 			Return(ghStatus, &github.Response{}, nil)
+		repoMock.EXPECT().
+			GetBranchProtection(gomock.AssignableToTypeOf(ctxInterface),
+				gomock.Eq(prs[0].RepoOwner),
+				gomock.Eq(prs[0].RepoName),
+				gomock.Eq("master")).
+			Return(nil, notProtected, errBranchNotProtected)
+		repoMock.EXPECT().
+			CompareCommits(gomock.AssignableToTypeOf(ctxInterface),
+				gomock.Eq(prs[0].RepoOwner),
+				gomock.Eq(prs[0].RepoName),
+				gomock.Eq("master"),
+				gomock.Eq(ghPR.Head.GetSHA()),
+				nil).
+			Return(&github.CommitsComparison{BehindBy: github.Int(0)}, &github.Response{}, nil)
+		mqStoreMock.EXPECT().
+			Delete(prs[0].RepoOwner, prs[0].RepoName, prs[0].Number).
+			Return(nil)
 
 		prMock := srmock.NewMockPullRequestsService(ctrl)
 		prMock.EXPECT().
@@ -86,6 +133,13 @@ func TestAutoMergePR(t *testing.T) {
 				gomock.Eq(prs[0].RepoName),
 				gomock.Eq(prs[0].Number)).
 			Return(ghPR, &github.Response{}, nil)
+		prMock.EXPECT().
+			ListReviews(gomock.AssignableToTypeOf(ctxInterface),
+				gomock.Eq(prs[0].RepoOwner),
+				gomock.Eq(prs[0].RepoName),
+				gomock.Eq(prs[0].Number),
+				gomock.Any()).
+			Return(nil, ok, nil)
 		prMock.EXPECT().
 			ListReviewers(gomock.AssignableToTypeOf(ctxInterface),
 				gomock.Eq(prs[0].RepoOwner),
@@ -93,14 +147,26 @@ func TestAutoMergePR(t *testing.T) {
 				gomock.Eq(prs[0].Number),
 				nil).
 			Return(ghReviewers, &github.Response{}, nil)
-		prOpts := &github.PullRequestOptions{}
+		prMock.EXPECT().
+			ListCommits(gomock.AssignableToTypeOf(ctxInterface),
+				gomock.Eq(prs[0].RepoOwner),
+				gomock.Eq(prs[0].RepoName),
+				gomock.Eq(prs[0].Number),
+				gomock.Any()).
+			Return(nil, ok, nil)
+		prOpts := &github.PullRequestOptions{
+			CommitTitle:        "Fix the thing (#42)",
+			SHA:                "sha",
+			MergeMethod:        "squash",
+			DontDefaultIfBlank: true,
+		}
 		prMock.EXPECT().
 			Merge(gomock.AssignableToTypeOf(ctxInterface),
 				gomock.Eq(prs[0].RepoOwner),
 				gomock.Eq(prs[0].RepoName),
 				gomock.Eq(prs[0].Number),
-				gomock.Eq("Automatic Merge"),
-				gomock.AssignableToTypeOf(prOpts)).
+				gomock.Eq(""),
+				gomock.Eq(prOpts)).
 			Return(prMergeResult, &github.Response{}, nil)
 
 		issueMock := srmock.NewMockIssuesService(ctrl)
@@ -140,6 +206,9 @@ func TestAutoMergePR(t *testing.T) {
 			Head: &github.PullRequestBranch{
 				SHA: github.String("sha"),
 			},
+			Base: &github.PullRequestBranch{
+				Ref: github.String("master"),
+			},
 		}
 
 		prMock := srmock.NewMockPullRequestsService(ctrl)
@@ -176,6 +245,9 @@ func TestAutoMergePR(t *testing.T) {
 			Head: &github.PullRequestBranch{
 				SHA: github.String("sha"),
 			},
+			Base: &github.PullRequestBranch{
+				Ref: github.String("master"),
+			},
 		}
 
 		prMock := srmock.NewMockPullRequestsService(ctrl)
@@ -186,8 +258,19 @@ func TestAutoMergePR(t *testing.T) {
 				gomock.Eq(prs[0].Number)).
 			Return(ghPR, &github.Response{}, nil)
 
+		repoMock := srmock.NewMockRepositoriesService(ctrl)
+		repoMock.EXPECT().
+			CreateStatus(gomock.AssignableToTypeOf(ctxInterface), "admin", "mattermod", "sha", &github.RepoStatus{
+				Context:     github.String(mergeQueueContext),
+				State:       github.String(stateSuccess),
+				Description: github.String("Skipped in the merge queue of master: the merge state is `unclean`"),
+				TargetURL:   github.String(""),
+			}).
+			Return(nil, nil, nil)
+
 		client := &GithubClient{
 			PullRequests: prMock,
+			Repositories: repoMock,
 		}
 
 		cfg := &Config{
@@ -212,6 +295,9 @@ func TestAutoMergePR(t *testing.T) {
 			Head: &github.PullRequestBranch{
 				SHA: github.String("sha"),
 			},
+			Base: &github.PullRequestBranch{
+				Ref: github.String("master"),
+			},
 		}
 
 		ghStatus := &github.CombinedStatus{
@@ -232,6 +318,12 @@ func TestAutoMergePR(t *testing.T) {
 				gomock.Eq(ghPR.Head.GetSHA()),
 				nil).
 			Return(ghStatus, &github.Response{}, nil)
+		repoMock.EXPECT().
+			GetBranchProtection(gomock.AssignableToTypeOf(ctxInterface),
+				gomock.Eq(prs[0].RepoOwner),
+				gomock.Eq(prs[0].RepoName),
+				gomock.Eq("master")).
+			Return(nil, notProtected, errBranchNotProtected)
 
 		prMock := srmock.NewMockPullRequestsService(ctrl)
 		prMock.EXPECT().
@@ -240,6 +332,13 @@ func TestAutoMergePR(t *testing.T) {
 				gomock.Eq(prs[0].RepoName),
 				gomock.Eq(prs[0].Number)).
 			Return(ghPR, &github.Response{}, nil)
+		prMock.EXPECT().
+			ListReviews(gomock.AssignableToTypeOf(ctxInterface),
+				gomock.Eq(prs[0].RepoOwner),
+				gomock.Eq(prs[0].RepoName),
+				gomock.Eq(prs[0].Number),
+				gomock.Any()).
+			Return(nil, ok, nil)
 		prMock.EXPECT().
 			ListReviewers(gomock.AssignableToTypeOf(ctxInterface),
 				gomock.Eq(prs[0].RepoOwner),
@@ -247,6 +346,14 @@ func TestAutoMergePR(t *testing.T) {
 				gomock.Eq(prs[0].Number),
 				nil).
 			Return(ghReviewers, &github.Response{}, nil)
+		repoMock.EXPECT().
+			CreateStatus(gomock.AssignableToTypeOf(ctxInterface), "admin", "mattermod", "sha", &github.RepoStatus{
+				Context:     github.String(mergeQueueContext),
+				State:       github.String(stateSuccess),
+				Description: github.String("Skipped in the merge queue of master: reviews are pending"),
+				TargetURL:   github.String(""),
+			}).
+			Return(nil, nil, nil)
 
 		client := &GithubClient{
 			PullRequests: prMock,
@@ -299,3 +406,152 @@ func TestHasAutoMerge(t *testing.T) {
 		assert.False(t, s.hasAutoMerge([]string{"badlabel"}))
 	})
 }
+
+func TestHandleMerge(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctxInterface := reflect.TypeOf((*context.Context)(nil)).Elem()
+
+	prMock := srmock.NewMockPullRequestsService(ctrl)
+	repoMock := srmock.NewMockRepositoriesService(ctrl)
+	issueMock := srmock.NewMockIssuesService(ctrl)
+	pmStoreMock := stmock.NewMockPendingMergeStore(ctrl)
+	prStoreMock := stmock.NewMockPullRequestStore(ctrl)
+	ss := stmock.NewMockStore(ctrl)
+	ss.EXPECT().PendingMerge().Return(pmStoreMock).AnyTimes()
+	ss.EXPECT().PullRequest().Return(prStoreMock).AnyTimes()
+
+	s := &Server{
+		Config: &Config{},
+		Store:  ss,
+		GithubClient: &GithubClient{
+			PullRequests: prMock,
+			Repositories: repoMock,
+			Issues:       issueMock,
+		},
+	}
+	pr := &model.PullRequest{RepoOwner: "mattertest", RepoName: "mattermod", Number: 1}
+	ok := &github.Response{Response: &http.Response{StatusCode: http.StatusOK}}
+	notProtected := &github.Response{Response: &http.Response{StatusCode: http.StatusNotFound}}
+
+	expectPR := func(state, mergeableState, combinedState string) {
+		ghPR := &github.PullRequest{
+			Title:          github.String("Fix the thing"),
+			Body:           github.String("Fixes #7"),
+			State:          github.String(state),
+			MergeableState: github.String(mergeableState),
+			Head:           &github.PullRequestBranch{SHA: github.String("sha")},
+		}
+		prMock.EXPECT().Get(gomock.AssignableToTypeOf(ctxInterface), "mattertest", "mattermod", 1).Return(ghPR, nil, nil)
+		if state == model.StateClosed || (mergeableState != model.MergeableStateClean && mergeableState != mergeableStateBlocked) {
+			return
+		}
+		repoMock.EXPECT().GetCombinedStatus(gomock.AssignableToTypeOf(ctxInterface), "mattertest", "mattermod", "sha", nil).
+			Return(&github.CombinedStatus{
+				SHA:      github.String("sha"),
+				State:    github.String(combinedState),
+				Statuses: []*github.RepoStatus{{Context: github.String("ci"), State: github.String(combinedState)}},
+			}, nil, nil)
+		repoMock.EXPECT().GetBranchProtection(gomock.AssignableToTypeOf(ctxInterface), "mattertest", "mattermod", "").
+			Return(nil, notProtected, errBranchNotProtected)
+		if combinedState != stateSuccess {
+			return
+		}
+		prMock.EXPECT().ListReviews(gomock.AssignableToTypeOf(ctxInterface), "mattertest", "mattermod", 1, gomock.Any()).
+			Return(nil, ok, nil)
+		prMock.EXPECT().ListReviewers(gomock.AssignableToTypeOf(ctxInterface), "mattertest", "mattermod", 1, nil).
+			Return(&github.Reviewers{}, nil, nil)
+	}
+	expectComment := func(body string) {
+		issueMock.EXPECT().
+			CreateComment(gomock.AssignableToTypeOf(ctxInterface), "mattertest", "mattermod", 1, &github.IssueComment{Body: github.String(body)}).
+			Return(nil, nil, nil)
+	}
+	expectMerge := func(method string) {
+		prMock.EXPECT().ListCommits(gomock.AssignableToTypeOf(ctxInterface), "mattertest", "mattermod", 1, gomock.Any()).
+			Return(nil, ok, nil)
+		prMock.EXPECT().
+			Merge(gomock.AssignableToTypeOf(ctxInterface), "mattertest", "mattermod", 1, "Fixes #7", &github.PullRequestOptions{
+				CommitTitle:        "Fix the thing (#1)",
+				SHA:                "sha",
+				MergeMethod:        method,
+				DontDefaultIfBlank: true,
+			}).
+			Return(&github.PullRequestMergeResult{Message: github.String("merged"), SHA: github.String("merge-sha")}, nil, nil)
+		expectComment("merged\nSHA: merge-sha")
+	}
+
+	t.Run("Ready PRs are merged immediately", func(t *testing.T) {
+		expectPR("open", model.MergeableStateClean, stateSuccess)
+		expectMerge("rebase")
+
+		require.NoError(t, s.handleMerge(context.Background(), "someone", []string{"Rebase"}, pr))
+	})
+
+	t.Run("PRs which aren't ready are merged later", func(t *testing.T) {
+		expectPR("open", model.MergeableStateClean, statePending)
+		pmStoreMock.EXPECT().Save(&model.PendingMerge{
+			RepoOwner:   "mattertest",
+			RepoName:    "mattermod",
+			Number:      1,
+			MergeMethod: "squash",
+			RequestedBy: "someone",
+		}).Return(nil)
+		expectComment("Will merge this PR (squash) once it's ready. Right now the combined status is `pending`.")
+
+		require.NoError(t, s.handleMerge(context.Background(), "someone", nil, pr))
+	})
+
+	t.Run("Closed PRs are not merged", func(t *testing.T) {
+		expectPR(model.StateClosed, model.MergeableStateClean, stateSuccess)
+		expectComment(msgMergeClosedPR)
+
+		require.ErrorIs(t, s.handleMerge(context.Background(), "someone", nil, pr), errNothingToDo)
+	})
+
+	t.Run("Unknown merge methods are rejected", func(t *testing.T) {
+		expectComment("Unknown merge method `fast-forward`. Please use one of: squash, merge or rebase.")
+
+		require.Error(t, s.handleMerge(context.Background(), "someone", []string{"fast-forward"}, pr))
+	})
+
+	t.Run("Successful statuses merge pending PRs once ready", func(t *testing.T) {
+		prStoreMock.EXPECT().ListBySha("mattertest", "mattermod", "sha").Return([]*model.PullRequest{pr}, nil).Times(2)
+		pmStoreMock.EXPECT().Get("mattertest", "mattermod", 1).
+			Return(&model.PendingMerge{RepoOwner: "mattertest", RepoName: "mattermod", Number: 1, MergeMethod: "merge"}, nil)
+		expectPR("open", model.MergeableStateClean, stateSuccess)
+		pmStoreMock.EXPECT().Claim(&model.PendingMerge{RepoOwner: "mattertest", RepoName: "mattermod", Number: 1, MergeMethod: "merge"}).Return(true, nil)
+		expectMerge("merge")
+
+		require.NoError(t, s.statusEventHandler(context.Background(), &github.StatusEvent{
+			Repo: &github.Repository{
+				Name:  github.String("mattermod"),
+				Owner: &github.User{Login: github.String("mattertest")},
+			},
+			SHA:     github.String("sha"),
+			Context: github.String("ci/lint"),
+			State:   github.String(stateSuccess),
+		}))
+	})
+
+	t.Run("Pending merges wait while the PR isn't ready", func(t *testing.T) {
+		expectPR("open", "blocked", "")
+
+		require.NoError(t, s.tryPendingMerge(context.Background(), &model.PendingMerge{RepoOwner: "mattertest", RepoName: "mattermod", Number: 1}))
+	})
+
+	t.Run("Pending merges of closed PRs are removed", func(t *testing.T) {
+		expectPR(model.StateClosed, "", "")
+		pmStoreMock.EXPECT().Claim(&model.PendingMerge{RepoOwner: "mattertest", RepoName: "mattermod", Number: 1}).Return(true, nil)
+
+		require.NoError(t, s.tryPendingMerge(context.Background(), &model.PendingMerge{RepoOwner: "mattertest", RepoName: "mattermod", Number: 1}))
+	})
+
+	t.Run("Pending merges claimed by another event are not merged", func(t *testing.T) {
+		expectPR("open", model.MergeableStateClean, stateSuccess)
+		pmStoreMock.EXPECT().Claim(&model.PendingMerge{RepoOwner: "mattertest", RepoName: "mattermod", Number: 1}).Return(false, nil)
+
+		require.NoError(t, s.tryPendingMerge(context.Background(), &model.PendingMerge{RepoOwner: "mattertest", RepoName: "mattermod", Number: 1}))
+	})
+}
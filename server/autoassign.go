@@ -21,7 +21,8 @@ func (s *Server) handleAutoAssign(ctx context.Context, url string, pr *model.Pul
 	var teams []*github.Team
 	teams, _, err = s.GithubClient.Repositories.ListTeams(ctx, pr.RepoOwner, pr.RepoName, nil)
 	if err != nil {
-		return err
+		// Failures are commented, so the command isn't retried.
+		return newPermanentError(err)
 	}
 
 	repoConfigured := false
@@ -45,11 +46,11 @@ func (s *Server) handleAutoAssign(ctx context.Context, url string, pr *model.Pul
 	}
 	_, _, err = s.GithubClient.PullRequests.RequestReviewers(ctx, pr.RepoOwner, pr.RepoName, pr.Number, reviewReq)
 	if err != nil {
-		return err
+		return newPermanentError(err)
 	}
 
 	msg := fmt.Sprintf("In response to [this](%s)\n\n I'm requesting the Pull Panda autoassigner to add reviewers to this PR.", url)
-	if err = s.sendGitHubComment(ctx, pr.RepoOwner, pr.RepoName, pr.Number, msg); err != nil {
+	if err = s.sendCommandComment(ctx, pr.RepoOwner, pr.RepoName, pr.Number, msg); err != nil {
 		mlog.Warn("Error while commenting", mlog.Err(err))
 	}
 
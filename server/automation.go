@@ -0,0 +1,192 @@
+// Copyright (c) 2017-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package server
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mattermost/mattermost-mattermod/model"
+	"github.com/mattermost/mattermost-server/v6/shared/mlog"
+)
+
+const (
+	automationHookPullRequest = "pull_request"
+	automationHookIssue       = "issue"
+	automationHookComment     = "comment"
+	automationHookSchedule    = "schedule"
+)
+
+// Automation is a feature reacting to GitHub events, like checking the CLA
+// of a PR author. Automations can be enabled or disabled per repository by
+// name. Embed baseAutomation to only implement the hooks that are needed.
+type Automation interface {
+	// Name identifies the automation in the config and in metrics.
+	Name() string
+	// OnPullRequest is called for pull_request events, after the PR has been
+	// updated from GitHub.
+	OnPullRequest(ctx context.Context, event *pullRequestEvent, pr *model.PullRequest) error
+	// OnIssue is called for issues events which aren't about a PR.
+	OnIssue(ctx context.Context, event *issueEvent, issue *model.Issue) error
+	// OnComment is called for comments created or edited on a PR.
+	OnComment(ctx context.Context, event *issueCommentEvent, pr *model.PullRequest) error
+	// OnSchedule is called periodically for each configured repository.
+	OnSchedule(ctx context.Context, repo *Repository) error
+}
+
+// baseAutomation implements all hooks of Automation as no-ops.
+type baseAutomation struct{}
+
+func (baseAutomation) OnPullRequest(context.Context, *pullRequestEvent, *model.PullRequest) error {
+	return nil
+}
+
+func (baseAutomation) OnIssue(context.Context, *issueEvent, *model.Issue) error {
+	return nil
+}
+
+func (baseAutomation) OnComment(context.Context, *issueCommentEvent, *model.PullRequest) error {
+	return nil
+}
+
+func (baseAutomation) OnSchedule(context.Context, *Repository) error {
+	return nil
+}
+
+// registerAutomations adds automations to the server. Automations run in the
+// order they are registered. Names must be unique.
+func (s *Server) registerAutomations(automations ...Automation) {
+	for _, a := range automations {
+		for _, registered := range s.automations {
+			if registered.Name() == a.Name() {
+				panic(fmt.Sprintf("automation %q is registered twice", a.Name()))
+			}
+		}
+		s.automations = append(s.automations, a)
+	}
+}
+
+// registerDefaultAutomations registers all automations shipped with mattermod.
+func (s *Server) registerDefaultAutomations() {
+	s.registerAutomations(
+		&claAutomation{s: s},
+		&greetingAutomation{s: s},
+		&hacktoberfestAutomation{s: s},
+		&translationsAutomation{s: s},
+		&mlogReviewAutomation{s: s},
+		&blockMergeAutomation{s: s},
+		&autoMergeAutomation{s: s},
+		&cherryPickAutomation{s: s},
+		&cherryPickPreviewAutomation{s: s},
+		&forwardPortAutomation{s: s},
+		&labelCleanupAutomation{s: s},
+	)
+}
+
+// isAutomationEnabled reports whether the named automation runs for the
+// given repository. Automations are enabled unless the repository disables
+// them in its config.
+func (s *Server) isAutomationEnabled(owner, repoName, name string) bool {
+	repo, ok := GetRepository(s.Config.Repositories, owner, repoName)
+	if !ok {
+		return true
+	}
+	enabled, ok := repo.Automations[name]
+	return !ok || enabled
+}
+
+// runAutomations calls hook for every automation enabled for the repository.
+// Errors are logged and counted per automation, and returned so the caller
+// can decide whether they fail the event.
+func (s *Server) runAutomations(owner, repoName, hookName string, hook func(a Automation) error) []error {
+	var errs []error
+	for _, a := range s.automations {
+		if !s.isAutomationEnabled(owner, repoName, a.Name()) {
+			continue
+		}
+
+		start := time.Now()
+		err := hook(a)
+		s.Metrics.ObserveAutomationDuration(a.Name(), hookName, time.Since(start).Seconds())
+		if err != nil {
+			s.Metrics.IncreaseAutomationErrors(a.Name(), hookName)
+			mlog.Error("Automation failed",
+				mlog.String("automation", a.Name()),
+				mlog.String("hook", hookName),
+				mlog.String("repo", owner+"/"+repoName),
+				mlog.Err(err))
+			errs = append(errs, fmt.Errorf("automation %s failed: %w", a.Name(), err))
+		}
+	}
+	return errs
+}
+
+func (s *Server) runPullRequestAutomations(ctx context.Context, event *pullRequestEvent, pr *model.PullRequest) []error {
+	return s.runAutomations(pr.RepoOwner, pr.RepoName, automationHookPullRequest, func(a Automation) error {
+		return a.OnPullRequest(ctx, event, pr)
+	})
+}
+
+func (s *Server) runIssueAutomations(ctx context.Context, event *issueEvent, issue *model.Issue) []error {
+	return s.runAutomations(issue.RepoOwner, issue.RepoName, automationHookIssue, func(a Automation) error {
+		return a.OnIssue(ctx, event, issue)
+	})
+}
+
+func (s *Server) runCommentAutomations(ctx context.Context, event *issueCommentEvent, pr *model.PullRequest) []error {
+	return s.runAutomations(pr.RepoOwner, pr.RepoName, automationHookComment, func(a Automation) error {
+		return a.OnComment(ctx, event, pr)
+	})
+}
+
+func (s *Server) runScheduledAutomations(ctx context.Context) {
+	for _, repo := range s.Config.Repositories {
+		s.runAutomations(repo.Owner, repo.Name, automationHookSchedule, func(a Automation) error {
+			return a.OnSchedule(ctx, repo)
+		})
+	}
+}
+
+// automationsError returns the errors of the automations of an event as one
+// error. The delivery of the event is retried only if all of them are
+// transient. Automations make the errors of steps which can't be repeated,
+// like posting a comment, permanent themselves.
+func automationsError(errs []error) error {
+	err := joinErrors(errs...)
+	if err != nil && !isTransientError(err) {
+		return newPermanentError(err)
+	}
+	return err
+}
+
+// multiError is a combination of errors, see joinErrors.
+type multiError []error
+
+func (e multiError) Error() string {
+	msgs := make([]string, 0, len(e))
+	for _, err := range e {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// joinErrors combines the errors of independent steps of an automation, so
+// that one failing step doesn't keep the others from running.
+func joinErrors(errs ...error) error {
+	var result multiError
+	for _, err := range errs {
+		if err != nil {
+			result = append(result, err)
+		}
+	}
+	switch len(result) {
+	case 0:
+		return nil
+	case 1:
+		return result[0]
+	}
+	return result
+}
@@ -0,0 +1,152 @@
+// Copyright (c) 2017-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/go-github/v39/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-mattermod/model"
+	srmock "github.com/mattermost/mattermost-mattermod/server/mocks"
+)
+
+type testAutomation struct {
+	baseAutomation
+	name  string
+	err   error
+	calls *[]string
+}
+
+func (a *testAutomation) Name() string {
+	return a.name
+}
+
+func (a *testAutomation) OnPullRequest(_ context.Context, event *pullRequestEvent, _ *model.PullRequest) error {
+	*a.calls = append(*a.calls, a.name+":"+event.Action)
+	return a.err
+}
+
+func (a *testAutomation) OnSchedule(_ context.Context, repo *Repository) error {
+	*a.calls = append(*a.calls, a.name+":"+repo.Name)
+	return a.err
+}
+
+func TestAutomations(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	metricsMock := srmock.NewMockMetricsProvider(ctrl)
+
+	var calls []string
+	s := &Server{
+		Config: &Config{
+			Repositories: []*Repository{
+				{
+					Owner:       "mattertest",
+					Name:        "mattermod",
+					Automations: map[string]bool{"second": false, "third": true},
+				},
+				{
+					Owner: "mattertest",
+					Name:  "mattermost-server",
+				},
+			},
+		},
+		Metrics: metricsMock,
+	}
+	s.registerAutomations(
+		&testAutomation{name: "first", calls: &calls},
+		&testAutomation{name: "second", calls: &calls},
+		&testAutomation{name: "third", err: errors.New("some-error"), calls: &calls},
+	)
+
+	t.Run("Enabled automations run in order", func(t *testing.T) {
+		calls = nil
+		metricsMock.EXPECT().ObserveAutomationDuration("first", automationHookPullRequest, gomock.Any())
+		metricsMock.EXPECT().ObserveAutomationDuration("third", automationHookPullRequest, gomock.Any())
+		metricsMock.EXPECT().IncreaseAutomationErrors("third", automationHookPullRequest)
+
+		errs := s.runPullRequestAutomations(context.Background(), &pullRequestEvent{Action: prEventOpened}, &model.PullRequest{
+			RepoOwner: "mattertest",
+			RepoName:  "mattermod",
+		})
+		require.Len(t, errs, 1)
+		assert.Equal(t, []string{"first:opened", "third:opened"}, calls)
+	})
+
+	t.Run("Unconfigured repositories run all automations", func(t *testing.T) {
+		calls = nil
+		metricsMock.EXPECT().ObserveAutomationDuration(gomock.Any(), automationHookPullRequest, gomock.Any()).Times(3)
+		metricsMock.EXPECT().IncreaseAutomationErrors("third", automationHookPullRequest)
+
+		errs := s.runPullRequestAutomations(context.Background(), &pullRequestEvent{Action: prEventClosed}, &model.PullRequest{
+			RepoOwner: "someone",
+			RepoName:  "something",
+		})
+		require.Len(t, errs, 1)
+		assert.Equal(t, []string{"first:closed", "second:closed", "third:closed"}, calls)
+	})
+
+	t.Run("Scheduled automations run per repository", func(t *testing.T) {
+		calls = nil
+		metricsMock.EXPECT().ObserveAutomationDuration(gomock.Any(), automationHookSchedule, gomock.Any()).Times(5)
+		metricsMock.EXPECT().IncreaseAutomationErrors("third", automationHookSchedule).Times(2)
+
+		s.runScheduledAutomations(context.Background())
+		assert.Equal(t, []string{
+			"first:mattermod",
+			"third:mattermod",
+			"first:mattermost-server",
+			"second:mattermost-server",
+			"third:mattermost-server",
+		}, calls)
+	})
+
+	t.Run("Names must be unique", func(t *testing.T) {
+		require.Panics(t, func() {
+			s.registerAutomations(&testAutomation{name: "first", calls: &calls})
+		})
+	})
+}
+
+func TestDefaultAutomations(t *testing.T) {
+	s := &Server{Config: &Config{}}
+	require.NotPanics(t, s.registerDefaultAutomations)
+
+	names := make([]string, 0, len(s.automations))
+	for _, a := range s.automations {
+		names = append(names, a.Name())
+	}
+	assert.Subset(t, names, []string{"cla", "greeting", "hacktoberfest", "translations", "block-merge", "mlog-review"})
+}
+
+func TestJoinErrors(t *testing.T) {
+	assert.NoError(t, joinErrors(nil, nil))
+	assert.EqualError(t, joinErrors(errors.New("a"), nil, errors.New("b")), "a; b")
+}
+
+func TestAutomationsError(t *testing.T) {
+	transient := &github.ErrorResponse{Response: &http.Response{StatusCode: http.StatusBadGateway}}
+	var perr *permanentError
+
+	assert.NoError(t, automationsError(nil))
+
+	err := automationsError([]error{fmt.Errorf("automation a failed: %w", transient), transient})
+	require.Error(t, err)
+	assert.False(t, errors.As(err, &perr))
+
+	err = automationsError([]error{transient, errors.New("some-error")})
+	assert.True(t, errors.As(err, &perr))
+
+	err = automationsError([]error{newPermanentError(transient)})
+	assert.True(t, errors.As(err, &perr))
+}
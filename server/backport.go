@@ -0,0 +1,82 @@
+// Copyright (c) 2017-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/mattermost/mattermost-mattermod/model"
+	"github.com/mattermost/mattermost-server/v6/shared/mlog"
+)
+
+// saveBackport records the state of the cherry pick of the PR onto branch.
+// The milestone of the PR is only known when the cherry pick is queued, so
+// later updates keep the stored one.
+func (s *Server) saveBackport(pr *model.PullRequest, branch, milestone, state string, backportNumber int) error {
+	return s.Store.Backport().Save(&model.Backport{
+		RepoOwner:      pr.RepoOwner,
+		RepoName:       pr.RepoName,
+		Number:         pr.Number,
+		Branch:         branch,
+		Milestone:      milestone,
+		BackportNumber: backportNumber,
+		State:          state,
+	})
+}
+
+// updateBackportState follows a cherry pick PR being merged, closed or
+// reopened, and updates the result comment of the original PR accordingly.
+// PRs which aren't cherry picks are ignored.
+func (s *Server) updateBackportState(ctx context.Context, pr *model.PullRequest) error {
+	backport, err := s.Store.Backport().GetByBackport(pr.RepoOwner, pr.RepoName, pr.Number)
+	if err != nil || backport == nil {
+		return err
+	}
+
+	switch {
+	case pr.GetMerged():
+		backport.State = model.BackportMerged
+	case pr.State == model.StateClosed:
+		backport.State = model.BackportClosed
+	default:
+		backport.State = model.BackportOpen
+	}
+	if err = s.Store.Backport().Save(backport); err != nil {
+		return err
+	}
+
+	s.updateCherryPickReport(ctx, &model.PullRequest{
+		RepoOwner: backport.RepoOwner,
+		RepoName:  backport.RepoName,
+		Number:    backport.Number,
+	})
+	return nil
+}
+
+// listBackports returns the backports of the PRs in the milestone given by
+// the milestone query parameter, e.g. /admin/backports?milestone=v7.2.
+func (s *Server) listBackports(w http.ResponseWriter, r *http.Request) {
+	milestone := r.URL.Query().Get("milestone")
+	if milestone == "" {
+		http.Error(w, "the milestone parameter is required", http.StatusBadRequest)
+		return
+	}
+
+	backports, err := s.Store.Backport().ListByMilestone(milestone)
+	if err != nil {
+		mlog.Error("Failed to list backports", mlog.String("milestone", milestone), mlog.Err(err))
+		http.Error(w, "could not list backports", http.StatusInternalServerError)
+		return
+	}
+	if backports == nil {
+		backports = []*model.Backport{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err = json.NewEncoder(w).Encode(backports); err != nil {
+		mlog.Error("Failed to write backports", mlog.Err(err))
+	}
+}
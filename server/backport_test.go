@@ -0,0 +1,162 @@
+// Copyright (c) 2017-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/go-github/v39/github"
+	"github.com/mattermost/mattermost-mattermod/model"
+	"github.com/mattermost/mattermost-mattermod/server/mocks"
+	stmock "github.com/mattermost/mattermost-mattermod/store/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpdateBackportState(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctxInterface := reflect.TypeOf((*context.Context)(nil)).Elem()
+
+	jobStore := stmock.NewMockCherryPickJobStore(ctrl)
+	backportStore := stmock.NewMockBackportStore(ctrl)
+	ss := stmock.NewMockStore(ctrl)
+	ss.EXPECT().CherryPickJob().Return(jobStore).AnyTimes()
+	ss.EXPECT().Backport().Return(backportStore).AnyTimes()
+	is := mocks.NewMockIssuesService(ctrl)
+
+	s := &Server{
+		Config:         &Config{},
+		Store:          ss,
+		GithubClient:   &GithubClient{Issues: is},
+		stickyComments: map[string]*stickyComment{"owner/repo#1 " + cherryPickReportMarker: {id: 7}},
+	}
+	backport := func(state string) *model.Backport {
+		return &model.Backport{RepoOwner: "owner", RepoName: "repo", Number: 1, Branch: "release-7.1", Milestone: "v7.1", BackportNumber: 2, State: state}
+	}
+
+	t.Run("PRs which aren't cherry picks are ignored", func(t *testing.T) {
+		backportStore.EXPECT().GetByBackport("owner", "repo", 3).Return(nil, nil)
+
+		require.NoError(t, s.updateBackportState(context.Background(), &model.PullRequest{RepoOwner: "owner", RepoName: "repo", Number: 3, State: model.StateClosed}))
+	})
+
+	for name, tc := range map[string]struct {
+		pr       *model.PullRequest
+		state    string
+		expected string
+	}{
+		"Merged cherry picks": {
+			pr:       &model.PullRequest{State: model.StateClosed, Merged: NewBool(true)},
+			state:    model.BackportOpen,
+			expected: "| `release-7.1` | Merged: #2 |",
+		},
+		"Closed cherry picks": {
+			pr:       &model.PullRequest{State: model.StateClosed, Merged: NewBool(false)},
+			state:    model.BackportOpen,
+			expected: "| `release-7.1` | Closed without merging: #2 |",
+		},
+		"Reopened cherry picks": {
+			pr:       &model.PullRequest{State: model.StateOpen, Merged: NewBool(false)},
+			state:    model.BackportClosed,
+			expected: "| `release-7.1` | Done: #2 |",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			tc.pr.RepoOwner, tc.pr.RepoName, tc.pr.Number = "owner", "repo", 2
+			saved := backport(tc.state)
+			backportStore.EXPECT().GetByBackport("owner", "repo", 2).Return(saved, nil)
+			backportStore.EXPECT().Save(saved).Return(nil)
+			jobStore.EXPECT().ListByPR("owner", "repo", 1).Return([]*model.CherryPickJob{
+				{Branch: "release-7.1", State: model.CherryPickJobSucceeded, NewPRNumber: 2},
+			}, nil)
+			backportStore.EXPECT().ListByPR("owner", "repo", 1).DoAndReturn(func(_, _ string, _ int) ([]*model.Backport, error) {
+				return []*model.Backport{saved}, nil
+			})
+			is.EXPECT().
+				EditComment(gomock.AssignableToTypeOf(ctxInterface), "owner", "repo", int64(7), gomock.AssignableToTypeOf(&github.IssueComment{})).
+				DoAndReturn(func(_ context.Context, _, _ string, _ int64, comment *github.IssueComment) (*github.IssueComment, *github.Response, error) {
+					assert.Contains(t, comment.GetBody(), tc.expected)
+					return nil, nil, nil
+				})
+
+			require.NoError(t, s.updateBackportState(context.Background(), tc.pr))
+		})
+	}
+}
+
+func TestListBackports(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	backportStore := stmock.NewMockBackportStore(ctrl)
+	ss := stmock.NewMockStore(ctrl)
+	ss.EXPECT().Backport().Return(backportStore).AnyTimes()
+
+	s := &Server{
+		Config: &Config{AdminToken: "secret"},
+		Store:  ss,
+	}
+	ts := httptest.NewServer(s.withAdminAuth(s.listBackports))
+	defer ts.Close()
+
+	get := func(t *testing.T, query string) *http.Response {
+		r, err := http.NewRequest(http.MethodGet, ts.URL+query, nil)
+		require.NoError(t, err)
+		r.Header.Set("Authorization", "Bearer secret")
+		resp, err := http.DefaultClient.Do(r)
+		require.NoError(t, err)
+		return resp
+	}
+
+	t.Run("The milestone is required", func(t *testing.T) {
+		resp := get(t, "")
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("Backports of the milestone are listed", func(t *testing.T) {
+		backportStore.EXPECT().ListByMilestone("v7.1").Return([]*model.Backport{
+			{RepoOwner: "owner", RepoName: "repo", Number: 1, Branch: "release-7.1", Milestone: "v7.1", BackportNumber: 2, State: model.BackportMerged},
+		}, nil)
+
+		resp := get(t, "?milestone=v7.1")
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var backports []map[string]interface{}
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&backports))
+		require.Len(t, backports, 1)
+		assert.Equal(t, "release-7.1", backports[0]["branch"])
+		assert.Equal(t, float64(2), backports[0]["backport_number"])
+		assert.Equal(t, model.BackportMerged, backports[0]["state"])
+	})
+
+	t.Run("Empty milestones list nothing", func(t *testing.T) {
+		backportStore.EXPECT().ListByMilestone("v7.2").Return(nil, nil)
+
+		resp := get(t, "?milestone=v7.2")
+		defer resp.Body.Close()
+		var backports []*model.Backport
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&backports))
+		assert.NotNil(t, backports)
+		assert.Empty(t, backports)
+	})
+
+	t.Run("Store errors fail the request", func(t *testing.T) {
+		backportStore.EXPECT().ListByMilestone("v7.3").Return(nil, errors.New("some-error"))
+
+		resp := get(t, "?milestone=v7.3")
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+	})
+}
@@ -6,21 +6,70 @@ package server
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"github.com/google/go-github/v39/github"
 	"github.com/mattermost/mattermost-mattermod/model"
 	"github.com/mattermost/mattermost-server/v6/shared/mlog"
 )
 
-func (s *Server) blockPRMerge(ctx context.Context, pr *model.PullRequest) error {
+const (
+	blockMergeAutomationName = "block-merge"
+	mergeBlockedContext      = "merge/blocked"
+	// maxStatusDescriptionLength is the longest status description accepted
+	// by GitHub.
+	maxStatusDescriptionLength = 140
+)
+
+// blockMergeAutomation keeps the merge/blocked status of PRs in line with
+// their labels and holds.
+type blockMergeAutomation struct {
+	baseAutomation
+	s *Server
+}
+
+func (a *blockMergeAutomation) Name() string {
+	return blockMergeAutomationName
+}
+
+func (a *blockMergeAutomation) OnPullRequest(ctx context.Context, event *pullRequestEvent, pr *model.PullRequest) error {
+	switch event.Action {
+	case prEventOpened, prEventReOpened, prEventSynchronize:
+		a.s.setBlockStatusForPR(ctx, pr)
+	case prEventLabeled, prEventUnLabeled:
+		if a.s.isBlockPRMerge(event.Label.GetName()) {
+			return a.s.updateMergeBlockedStatus(ctx, pr)
+		}
+	}
+	return nil
+}
+
+// updateMergeBlockedStatus blocks the merge of the PR while it has holds or
+// blocking labels, and unblocks it otherwise.
+func (s *Server) updateMergeBlockedStatus(ctx context.Context, pr *model.PullRequest) error {
+	if pr.State == model.StateClosed {
+		return nil
+	}
+
+	holds, err := s.Store.Hold().List(pr.RepoOwner, pr.RepoName, pr.Number)
+	if err != nil {
+		return err
+	}
+	if len(holds) > 0 || s.isBlockPRMergeInLabels(pr.Labels) {
+		return s.blockPRMerge(ctx, pr, holds)
+	}
+	return s.unblockPRMerge(ctx, pr)
+}
+
+func (s *Server) blockPRMerge(ctx context.Context, pr *model.PullRequest, holds []*model.Hold) error {
 	if pr.State == model.StateClosed {
 		return nil
 	}
 
 	mergeStatus := &github.RepoStatus{
-		Context:     github.String("merge/blocked"),
+		Context:     github.String(mergeBlockedContext),
 		State:       github.String(statePending),
-		Description: github.String(fmt.Sprintf("Merge blocked due %s label", s.getBlockLabelFromPR(pr.Labels))),
+		Description: github.String(s.mergeBlockedDescription(holds, pr.Labels)),
 		TargetURL:   github.String(""),
 	}
 
@@ -29,15 +78,48 @@ func (s *Server) blockPRMerge(ctx context.Context, pr *model.PullRequest) error
 	return err
 }
 
-func (s *Server) getBlockLabelFromPR(prLabels []string) string {
+// mergeBlockedDescription lists the holds and blocking labels of a PR, e.g.
+// "Merge blocked due hold by @someone (waiting for QA), Do Not Merge label".
+func (s *Server) mergeBlockedDescription(holds []*model.Hold, prLabels []string) string {
+	var reasons []string
+	for _, hold := range holds {
+		reason := "hold by @" + hold.Holder
+		if hold.Reason != "" {
+			reason += " (" + hold.Reason + ")"
+		}
+		reasons = append(reasons, reason)
+	}
+
+	switch labels := s.getBlockLabelsFromPR(prLabels); len(labels) {
+	case 0:
+	case 1:
+		reasons = append(reasons, labels[0]+" label")
+	default:
+		reasons = append(reasons, strings.Join(labels, ", ")+" labels")
+	}
+
+	return truncateStatusDescription(fmt.Sprintf("Merge blocked due %s", strings.Join(reasons, ", ")))
+}
+
+// truncateStatusDescription shortens the description of a status to the
+// length accepted by GitHub.
+func truncateStatusDescription(description string) string {
+	if runes := []rune(description); len(runes) > maxStatusDescriptionLength {
+		return string(runes[:maxStatusDescriptionLength-3]) + "..."
+	}
+	return description
+}
+
+func (s *Server) getBlockLabelsFromPR(prLabels []string) []string {
+	var labels []string
 	for _, blockLabel := range s.Config.BlockPRMergeLabels {
 		for _, prLabel := range prLabels {
 			if prLabel == blockLabel {
-				return prLabel
+				labels = append(labels, prLabel)
 			}
 		}
 	}
-	return ""
+	return labels
 }
 
 func (s *Server) unblockPRMerge(ctx context.Context, pr *model.PullRequest) error {
@@ -46,7 +128,7 @@ func (s *Server) unblockPRMerge(ctx context.Context, pr *model.PullRequest) erro
 	}
 
 	mergeStatus := &github.RepoStatus{
-		Context:     github.String("merge/blocked"),
+		Context:     github.String(mergeBlockedContext),
 		State:       github.String(stateSuccess),
 		Description: github.String("Merged allowed"),
 		TargetURL:   github.String(""),
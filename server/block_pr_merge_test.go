@@ -8,6 +8,7 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/google/go-github/v39/github"
@@ -43,7 +44,7 @@ func TestBlockPRMerge(t *testing.T) {
 			gomock.Eq("testsha"),
 			gomock.AssignableToTypeOf(repoStatusType),
 		).Times(0)
-		err := s.blockPRMerge(context.TODO(), pr)
+		err := s.blockPRMerge(context.TODO(), pr, nil)
 		require.NoError(t, err)
 	})
 
@@ -58,7 +59,7 @@ func TestBlockPRMerge(t *testing.T) {
 				statePending,
 			)),
 		).Times(1).Return(&github.RepoStatus{}, &github.Response{}, nil)
-		err := s.blockPRMerge(context.TODO(), pr)
+		err := s.blockPRMerge(context.TODO(), pr, nil)
 		require.NoError(t, err)
 	})
 
@@ -73,7 +74,7 @@ func TestBlockPRMerge(t *testing.T) {
 				statePending,
 			)),
 		).Times(1).Return(&github.RepoStatus{}, &github.Response{}, errors.New("error setting status"))
-		err := s.blockPRMerge(context.TODO(), pr)
+		err := s.blockPRMerge(context.TODO(), pr, nil)
 		require.Error(t, err)
 	})
 }
@@ -138,6 +139,24 @@ func TestUnblockPRMerge(t *testing.T) {
 	})
 }
 
+func TestMergeBlockedDescription(t *testing.T) {
+	s := Server{
+		Config: &Config{
+			BlockPRMergeLabels: []string{"Do Not Merge", "Work In Progress"},
+		},
+	}
+
+	require.Equal(t, "Merge blocked due Do Not Merge label", s.mergeBlockedDescription(nil, []string{"Do Not Merge", "Docs"}))
+	require.Equal(t, "Merge blocked due Do Not Merge, Work In Progress labels", s.mergeBlockedDescription(nil, []string{"Work In Progress", "Do Not Merge"}))
+	require.Equal(t,
+		"Merge blocked due hold by @alice (waiting for QA), hold by @bob, Work In Progress label",
+		s.mergeBlockedDescription([]*model.Hold{{Holder: "alice", Reason: "waiting for QA"}, {Holder: "bob"}}, []string{"Work In Progress"}))
+
+	description := s.mergeBlockedDescription([]*model.Hold{{Holder: "alice", Reason: strings.Repeat("a", 200)}}, nil)
+	require.Len(t, description, maxStatusDescriptionLength)
+	require.True(t, strings.HasSuffix(description, "..."))
+}
+
 func createExamplePR(state string, labels []string) *model.PullRequest {
 	return &model.PullRequest{
 		RepoOwner: "testuser",
@@ -0,0 +1,114 @@
+// Copyright (c) 2017-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/google/go-github/v39/github"
+)
+
+// requiredContexts returns the contexts the protection requires to succeed.
+func requiredContexts(protection *github.Protection) []string {
+	if checks := protection.GetRequiredStatusChecks(); checks != nil {
+		return checks.Contexts
+	}
+	return nil
+}
+
+// branchNotProtectedMessage is the message of the 404 GitHub answers with for
+// branches without protection. It also answers 404 when the Administration
+// permission is missing, which must not pass for an unprotected branch.
+const branchNotProtectedMessage = "Branch not protected"
+
+// getBranchProtection returns the protection rules of a branch, or nil if the
+// branch isn't protected.
+func (s *Server) getBranchProtection(ctx context.Context, owner, name, branch string) (*github.Protection, error) {
+	protection, _, err := s.GithubClient.Repositories.GetBranchProtection(ctx, owner, name, branch)
+	var respErr *github.ErrorResponse
+	if errors.As(err, &respErr) && respErr.Response != nil && respErr.Response.StatusCode == http.StatusNotFound && respErr.Message == branchNotProtectedMessage {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not get the protection of %s/%s:%s: %w", owner, name, branch, err)
+	}
+	return protection, nil
+}
+
+// requiredChecksReadiness checks the contexts the protection requires. A
+// context is reported either as a commit status or as a check run, e.g. by
+// GitHub Actions. Contexts which haven't been reported yet are waited for.
+func requiredChecksReadiness(contexts []string, statuses []*github.RepoStatus, runs []*github.CheckRun) mergeReadiness {
+	for _, check := range contexts {
+		state := requiredCheckState(check, statuses, runs)
+		switch state {
+		case stateSuccess:
+			continue
+		case statePending:
+			return mergeReadiness{reason: fmt.Sprintf("the required check `%s` is pending", check), waiting: true}
+		default:
+			return mergeReadiness{reason: fmt.Sprintf("the required check `%s` is `%s`", check, state)}
+		}
+	}
+	return mergeReadiness{}
+}
+
+// requiredCheckState returns the state of the check, as a status state.
+// Failed check runs keep their conclusion, e.g. timed_out. A success of
+// either the status or the check run is enough.
+func requiredCheckState(check string, statuses []*github.RepoStatus, runs []*github.CheckRun) string {
+	state := ""
+	for _, status := range statuses {
+		if status.GetContext() != check {
+			continue
+		}
+		if status.GetState() == stateSuccess {
+			return stateSuccess
+		}
+		state = status.GetState()
+		break
+	}
+	for _, run := range runs {
+		if run.GetName() != check {
+			continue
+		}
+		if run.GetStatus() != "completed" {
+			return statePending
+		}
+		switch run.GetConclusion() {
+		case "success", "neutral", "skipped":
+			return stateSuccess
+		}
+		if state == "" || state == statePending {
+			state = run.GetConclusion()
+		}
+		break
+	}
+	if state == "" {
+		return statePending
+	}
+	return state
+}
+
+// reviewsReadiness checks that nobody requested changes to the PR, and that
+// it has the number of approvals the protection requires. Approvals of the
+// author don't count.
+func reviewsReadiness(protection *github.Protection, reviews []*github.PullRequestReview, author string) mergeReadiness {
+	if requesters := changesRequesters(reviews); len(requesters) > 0 {
+		return mergeReadiness{reason: fmt.Sprintf("changes were requested by @%s", strings.Join(requesters, ", @"))}
+	}
+
+	required := 0
+	if enforcement := protection.GetRequiredPullRequestReviews(); enforcement != nil {
+		required = enforcement.RequiredApprovingReviewCount
+	}
+	if approved := len(approvers(reviews, author)); approved < required {
+		return mergeReadiness{reason: fmt.Sprintf("%d of the %d required approving reviews were given", approved, required)}
+	}
+	return mergeReadiness{}
+}
@@ -0,0 +1,185 @@
+// Copyright (c) 2017-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package server
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/go-github/v39/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-mattermod/model"
+	"github.com/mattermost/mattermost-mattermod/server/mocks"
+)
+
+// errBranchNotProtected is what GitHub answers for the protection of a branch
+// without protection.
+var errBranchNotProtected = &github.ErrorResponse{
+	Response: &http.Response{StatusCode: http.StatusNotFound},
+	Message:  branchNotProtectedMessage,
+}
+
+func TestRequiredChecksReadiness(t *testing.T) {
+	status := func(name, state string) *github.RepoStatus {
+		return &github.RepoStatus{Context: github.String(name), State: github.String(state)}
+	}
+	run := func(name, status, conclusion string) *github.CheckRun {
+		return &github.CheckRun{Name: github.String(name), Status: github.String(status), Conclusion: github.String(conclusion)}
+	}
+	statuses := []*github.RepoStatus{
+		status("ci/build", stateSuccess),
+		status("ci/lint", stateFailure),
+		status("ci/e2e", statePending),
+	}
+	runs := []*github.CheckRun{
+		run("test", "completed", "success"),
+		run("docs", "completed", "skipped"),
+		run("ci/lint", "completed", "success"),
+		run("release", "in_progress", ""),
+		run("security", "completed", "timed_out"),
+	}
+
+	for name, tc := range map[string]struct {
+		contexts []string
+		expected mergeReadiness
+	}{
+		"Successful statuses and check runs": {
+			contexts: []string{"ci/build", "test", "docs"},
+			expected: mergeReadiness{},
+		},
+		"A successful check run makes up for a failed status": {
+			contexts: []string{"ci/lint"},
+			expected: mergeReadiness{},
+		},
+		"Pending statuses are waited for": {
+			contexts: []string{"ci/build", "ci/e2e"},
+			expected: mergeReadiness{reason: "the required check `ci/e2e` is pending", waiting: true},
+		},
+		"Running check runs are waited for": {
+			contexts: []string{"release"},
+			expected: mergeReadiness{reason: "the required check `release` is pending", waiting: true},
+		},
+		"Missing checks are waited for": {
+			contexts: []string{"coverage"},
+			expected: mergeReadiness{reason: "the required check `coverage` is pending", waiting: true},
+		},
+		"Failed check runs block": {
+			contexts: []string{"test", "security"},
+			expected: mergeReadiness{reason: "the required check `security` is `timed_out`"},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, requiredChecksReadiness(tc.contexts, statuses, runs))
+		})
+	}
+}
+
+func TestReviewsReadiness(t *testing.T) {
+	protection := &github.Protection{
+		RequiredPullRequestReviews: &github.PullRequestReviewsEnforcement{RequiredApprovingReviewCount: 2},
+	}
+
+	assert.Equal(t, mergeReadiness{}, reviewsReadiness(nil, nil, "author"))
+	assert.Equal(t, mergeReadiness{}, reviewsReadiness(protection, []*github.PullRequestReview{
+		review("first", "APPROVED"),
+		review("second", "CHANGES_REQUESTED"),
+		review("second", "APPROVED"),
+	}, "author"))
+	assert.Equal(t, mergeReadiness{reason: "1 of the 2 required approving reviews were given"}, reviewsReadiness(protection, []*github.PullRequestReview{
+		review("author", "APPROVED"),
+		review("first", "APPROVED"),
+	}, "author"))
+	assert.Equal(t, mergeReadiness{reason: "changes were requested by @first, @third"}, reviewsReadiness(nil, []*github.PullRequestReview{
+		review("first", "CHANGES_REQUESTED"),
+		review("second", "APPROVED"),
+		review("third", "CHANGES_REQUESTED"),
+		review("third", "COMMENTED"),
+	}, "author"))
+}
+
+func TestAssessMergeReadinessWithBranchProtection(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctxInterface := reflect.TypeOf((*context.Context)(nil)).Elem()
+	repos := mocks.NewMockRepositoriesService(ctrl)
+	prs := mocks.NewMockPullRequestsService(ctrl)
+	checks := mocks.NewMockChecksService(ctrl)
+	s := &Server{
+		GithubClient: &GithubClient{Repositories: repos, PullRequests: prs, Checks: checks},
+	}
+	ok := &github.Response{Response: &http.Response{StatusCode: http.StatusOK}}
+
+	pr := &model.PullRequest{RepoOwner: "mattermost", RepoName: "server", Number: 12}
+	ghPR := &github.PullRequest{
+		State:          github.String("open"),
+		MergeableState: github.String(mergeableStateBlocked),
+		User:           &github.User{Login: github.String("author")},
+		Head:           &github.PullRequestBranch{SHA: github.String("sha")},
+		Base:           &github.PullRequestBranch{Ref: github.String("master")},
+	}
+	protection := &github.Protection{
+		RequiredStatusChecks:       &github.RequiredStatusChecks{Contexts: []string{"build"}},
+		RequiredPullRequestReviews: &github.PullRequestReviewsEnforcement{RequiredApprovingReviewCount: 1},
+	}
+	expectProtection := func(buildConclusion string) {
+		// Repositories using only GitHub Actions have no statuses, which
+		// leaves their combined status pending.
+		repos.EXPECT().GetCombinedStatus(gomock.AssignableToTypeOf(ctxInterface), "mattermost", "server", "sha", nil).
+			Return(&github.CombinedStatus{SHA: github.String("sha"), State: github.String(statePending)}, nil, nil)
+		repos.EXPECT().GetBranchProtection(gomock.AssignableToTypeOf(ctxInterface), "mattermost", "server", "master").
+			Return(protection, ok, nil)
+		checks.EXPECT().ListCheckRunsForRef(gomock.AssignableToTypeOf(ctxInterface), "mattermost", "server", "sha", gomock.Any()).
+			Return(&github.ListCheckRunsResults{CheckRuns: []*github.CheckRun{{
+				Name:       github.String("build"),
+				Status:     github.String("completed"),
+				Conclusion: github.String(buildConclusion),
+			}}}, ok, nil)
+	}
+
+	t.Run("Failed required check runs block the merge", func(t *testing.T) {
+		expectProtection("failure")
+
+		readiness, err := s.assessMergeReadiness(context.Background(), pr, ghPR)
+		require.NoError(t, err)
+		assert.Equal(t, mergeReadiness{reason: "the required check `build` is `failure`"}, readiness)
+	})
+
+	t.Run("Missing approvals block the merge", func(t *testing.T) {
+		expectProtection("success")
+		prs.EXPECT().ListReviews(gomock.AssignableToTypeOf(ctxInterface), "mattermost", "server", 12, gomock.Any()).
+			Return([]*github.PullRequestReview{review("author", "APPROVED")}, ok, nil)
+
+		readiness, err := s.assessMergeReadiness(context.Background(), pr, ghPR)
+		require.NoError(t, err)
+		assert.Equal(t, mergeReadiness{reason: "0 of the 1 required approving reviews were given"}, readiness)
+	})
+
+	t.Run("Missing permissions are not mistaken for unprotected branches", func(t *testing.T) {
+		notFound := &github.Response{Response: &http.Response{StatusCode: http.StatusNotFound}}
+		repos.EXPECT().GetCombinedStatus(gomock.AssignableToTypeOf(ctxInterface), "mattermost", "server", "sha", nil).
+			Return(&github.CombinedStatus{SHA: github.String("sha"), State: github.String(stateSuccess)}, nil, nil)
+		repos.EXPECT().GetBranchProtection(gomock.AssignableToTypeOf(ctxInterface), "mattermost", "server", "master").
+			Return(nil, notFound, &github.ErrorResponse{Response: notFound.Response, Message: "Not Found"})
+
+		_, err := s.assessMergeReadiness(context.Background(), pr, ghPR)
+		require.Error(t, err)
+	})
+
+	t.Run("Errors reading the protection are returned", func(t *testing.T) {
+		repos.EXPECT().GetCombinedStatus(gomock.AssignableToTypeOf(ctxInterface), "mattermost", "server", "sha", nil).
+			Return(&github.CombinedStatus{SHA: github.String("sha"), State: github.String(stateSuccess)}, nil, nil)
+		repos.EXPECT().GetBranchProtection(gomock.AssignableToTypeOf(ctxInterface), "mattermost", "server", "master").
+			Return(nil, &github.Response{Response: &http.Response{StatusCode: http.StatusForbidden}}, errors.New("forbidden"))
+
+		_, err := s.assessMergeReadiness(context.Background(), pr, ghPR)
+		require.Error(t, err)
+	})
+}
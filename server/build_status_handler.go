@@ -0,0 +1,238 @@
+// Copyright (c) 2017-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package server
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/go-github/v39/github"
+	"github.com/mattermost/mattermost-mattermod/model"
+	"github.com/mattermost/mattermost-server/v6/shared/mlog"
+)
+
+const (
+	checkRunStatusCompleted   = "completed"
+	checkSuiteActionCompleted = "completed"
+)
+
+// fetchBuildStatus sets the build status of the PR from the commit status
+// or check run matching the BuildStatusContext of its repository. Check runs
+// take precedence over commit statuses.
+func (s *Server) fetchBuildStatus(ctx context.Context, pr *model.PullRequest) error {
+	repo, ok := GetRepository(s.Config.Repositories, pr.RepoOwner, pr.RepoName)
+	if !ok || repo.BuildStatusContext == "" {
+		return nil
+	}
+
+	combined, _, err := s.GithubClient.Repositories.GetCombinedStatus(ctx, pr.RepoOwner, pr.RepoName, pr.Sha, nil)
+	if err != nil {
+		return err
+	}
+
+	for _, status := range combined.Statuses {
+		if status.GetContext() == repo.BuildStatusContext {
+			pr.BuildStatus = status.GetState()
+			pr.BuildLink = status.GetTargetURL()
+			break
+		}
+	}
+
+	// for the repos using circleci we have the checks now
+	checks, _, err := s.GithubClient.Checks.ListCheckRunsForRef(ctx, pr.RepoOwner, pr.RepoName, pr.Sha, nil)
+	if err != nil {
+		return err
+	}
+
+	for _, run := range checks.CheckRuns {
+		if run.GetName() == repo.BuildStatusContext {
+			setBuildStatusFromCheckRun(pr, run)
+			break
+		}
+	}
+
+	return nil
+}
+
+func setBuildStatusFromCheckRun(pr *model.PullRequest, run *github.CheckRun) {
+	pr.BuildStatus = run.GetStatus()
+	pr.BuildConclusion = run.GetConclusion()
+	pr.BuildLink = run.GetHTMLURL()
+}
+
+func (s *Server) statusEventHandler(ctx context.Context, event *github.StatusEvent) error {
+	owner, name := event.GetRepo().GetOwner().GetLogin(), event.GetRepo().GetName()
+
+	var errs []error
+	repo, ok := GetRepository(s.Config.Repositories, owner, name)
+	if ok && repo.BuildStatusContext != "" && repo.BuildStatusContext == event.GetContext() {
+		errs = append(errs, s.updateBuildStatus(owner, name, event.GetSHA(), func(pr *model.PullRequest) {
+			pr.BuildStatus = event.GetState()
+			pr.BuildLink = event.GetTargetURL()
+		}))
+	}
+
+	// Any successful status can be the last one a pending merge waits for.
+	if event.GetState() == stateSuccess {
+		errs = append(errs, s.mergePendingPRs(ctx, owner, name, event.GetSHA()))
+	}
+	// The merge queue reports on itself, its own statuses must not move it.
+	if event.GetState() != statePending && event.GetContext() != mergeQueueContext {
+		errs = append(errs, s.advanceMergeQueuesOf(ctx, owner, name, event.GetSHA()))
+	}
+
+	return joinErrors(errs...)
+}
+
+func (s *Server) checkRunEventHandler(ctx context.Context, event *github.CheckRunEvent) error {
+	owner, name := event.GetRepo().GetOwner().GetLogin(), event.GetRepo().GetName()
+	run := event.GetCheckRun()
+
+	var errs []error
+	repo, ok := GetRepository(s.Config.Repositories, owner, name)
+	if ok && repo.BuildStatusContext != "" && repo.BuildStatusContext == run.GetName() {
+		errs = append(errs, s.updateBuildStatus(owner, name, run.GetHeadSHA(), func(pr *model.PullRequest) {
+			// Events may arrive out of order: a late event of a run which
+			// completed already must not make it look running again. Runs
+			// requested again get a new link, so they still show up.
+			if run.GetStatus() != checkRunStatusCompleted && pr.BuildStatus == checkRunStatusCompleted && pr.BuildLink == run.GetHTMLURL() {
+				return
+			}
+			setBuildStatusFromCheckRun(pr, run)
+		}))
+	}
+
+	// Any completed check run can be the last one a pending merge waits for.
+	if run.GetStatus() == checkRunStatusCompleted {
+		errs = append(errs,
+			s.mergePendingPRs(ctx, owner, name, run.GetHeadSHA()),
+			s.advanceMergeQueuesOf(ctx, owner, name, run.GetHeadSHA()))
+	}
+
+	return joinErrors(errs...)
+}
+
+// checkSuiteEventHandler tries the pending merges and the merge queues of the
+// head of a completed suite, and catches up on its check runs in case the check_run event
+// completing the build was missed.
+func (s *Server) checkSuiteEventHandler(ctx context.Context, event *github.CheckSuiteEvent) error {
+	if event.GetAction() != checkSuiteActionCompleted {
+		return nil
+	}
+
+	owner, name := event.GetRepo().GetOwner().GetLogin(), event.GetRepo().GetName()
+	sha := event.GetCheckSuite().GetHeadSHA()
+	return joinErrors(
+		s.catchUpBuildStatus(ctx, owner, name, sha),
+		s.mergePendingPRs(ctx, owner, name, sha),
+		s.advanceMergeQueuesOf(ctx, owner, name, sha),
+	)
+}
+
+// catchUpBuildStatus sets the build status of the PRs whose head is at the
+// given commit from its check run, if they don't have a completed one yet.
+func (s *Server) catchUpBuildStatus(ctx context.Context, owner, name, sha string) error {
+	repo, ok := GetRepository(s.Config.Repositories, owner, name)
+	if !ok || repo.BuildStatusContext == "" {
+		return nil
+	}
+
+	prs, err := s.Store.PullRequest().ListBySha(owner, name, sha)
+	if err != nil {
+		return err
+	}
+
+	outdated := false
+	for _, pr := range prs {
+		if pr.BuildStatus != checkRunStatusCompleted {
+			outdated = true
+			break
+		}
+	}
+	if !outdated {
+		return nil
+	}
+
+	checks, _, err := s.GithubClient.Checks.ListCheckRunsForRef(ctx, owner, name, sha, &github.ListCheckRunsOptions{
+		CheckName: github.String(repo.BuildStatusContext),
+	})
+	if err != nil {
+		return err
+	}
+	if len(checks.CheckRuns) == 0 {
+		return nil
+	}
+
+	return s.updateBuildStatus(owner, name, sha, func(pr *model.PullRequest) {
+		setBuildStatusFromCheckRun(pr, checks.CheckRuns[0])
+	})
+}
+
+// buildFinished reports whether the stored build status of the PR is final,
+// so that it only changes with a new head.
+func buildFinished(pr *model.PullRequest) bool {
+	switch pr.BuildStatus {
+	case checkRunStatusCompleted, stateSuccess, stateFailure, stateError:
+		return true
+	default:
+		return false
+	}
+}
+
+// refreshBuildStatuses fetches the build status of the open PRs whose build
+// hasn't finished, in case the events finishing it were missed. PRs with a
+// finished build are left to the webhooks.
+func (s *Server) refreshBuildStatuses(ctx context.Context) error {
+	prs, err := s.Store.PullRequest().ListOpen()
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, pr := range prs {
+		if buildFinished(pr) {
+			continue
+		}
+
+		fetched := *pr
+		if err = s.fetchBuildStatus(ctx, &fetched); err != nil {
+			errs = append(errs, fmt.Errorf("could not fetch build status of PR %d: %w", pr.Number, err))
+			continue
+		}
+		errs = append(errs, s.updateBuildStatus(pr.RepoOwner, pr.RepoName, pr.Sha, func(pr *model.PullRequest) {
+			pr.BuildStatus = fetched.BuildStatus
+			pr.BuildConclusion = fetched.BuildConclusion
+			pr.BuildLink = fetched.BuildLink
+		}))
+	}
+	return joinErrors(errs...)
+}
+
+// updateBuildStatus applies update to the stored PRs whose head is at the
+// given commit. Commits which don't belong to a PR are ignored.
+func (s *Server) updateBuildStatus(owner, name, sha string, update func(pr *model.PullRequest)) error {
+	prs, err := s.Store.PullRequest().ListBySha(owner, name, sha)
+	if err != nil {
+		return err
+	}
+
+	for _, pr := range prs {
+		oldStatus, oldConclusion, oldLink := pr.BuildStatus, pr.BuildConclusion, pr.BuildLink
+		update(pr)
+		if pr.BuildStatus == oldStatus && pr.BuildConclusion == oldConclusion && pr.BuildLink == oldLink {
+			continue
+		}
+
+		mlog.Info("Updating build status",
+			mlog.String("repo", name),
+			mlog.Int("pr", pr.Number),
+			mlog.String("status", pr.BuildStatus),
+			mlog.String("conclusion", pr.BuildConclusion))
+		if _, err = s.Store.PullRequest().Save(pr); err != nil {
+			return fmt.Errorf("could not save build status of PR %d: %w", pr.Number, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,313 @@
+// Copyright (c) 2017-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package server
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/go-github/v39/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-mattermod/model"
+	"github.com/mattermost/mattermost-mattermod/server/mocks"
+	stmock "github.com/mattermost/mattermost-mattermod/store/mocks"
+)
+
+func TestBuildStatusEventHandlers(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctxInterface := reflect.TypeOf((*context.Context)(nil)).Elem()
+
+	prStoreMock := stmock.NewMockPullRequestStore(ctrl)
+	ss := stmock.NewMockStore(ctrl)
+	ss.EXPECT().
+		PullRequest().
+		Return(prStoreMock).
+		AnyTimes()
+	pmStoreMock := stmock.NewMockPendingMergeStore(ctrl)
+	ss.EXPECT().
+		PendingMerge().
+		Return(pmStoreMock).
+		AnyTimes()
+
+	cs := mocks.NewMockChecksService(ctrl)
+	rs := mocks.NewMockRepositoriesService(ctrl)
+
+	s := &Server{
+		Config: &Config{
+			Repositories: []*Repository{
+				{
+					Name:               "mattermod",
+					Owner:              "mattertest",
+					BuildStatusContext: "ci/build",
+				},
+			},
+		},
+		Store:        ss,
+		GithubClient: &GithubClient{Checks: cs, Repositories: rs},
+	}
+
+	repo := &github.Repository{
+		Name:     github.String("mattermod"),
+		FullName: github.String("mattertest/mattermod"),
+		Owner:    &github.User{Login: github.String("mattertest")},
+	}
+
+	storedPR := func() *model.PullRequest {
+		return &model.PullRequest{
+			RepoOwner:   "mattertest",
+			RepoName:    "mattermod",
+			Number:      1,
+			Sha:         "sha",
+			BuildStatus: statePending,
+		}
+	}
+
+	t.Run("Status event updates the PR", func(t *testing.T) {
+		prStoreMock.EXPECT().ListBySha("mattertest", "mattermod", "sha").Return([]*model.PullRequest{storedPR()}, nil).Times(3)
+		pmStoreMock.EXPECT().Get("mattertest", "mattermod", 1).Return(nil, nil)
+		prStoreMock.EXPECT().
+			Save(gomock.AssignableToTypeOf(&model.PullRequest{})).
+			DoAndReturn(func(pr *model.PullRequest) (*model.PullRequest, error) {
+				assert.Equal(t, stateSuccess, pr.BuildStatus)
+				assert.Equal(t, "https://ci/1", pr.BuildLink)
+				return pr, nil
+			})
+
+		err := s.statusEventHandler(context.Background(), &github.StatusEvent{
+			Repo:      repo,
+			SHA:       github.String("sha"),
+			Context:   github.String("ci/build"),
+			State:     github.String(stateSuccess),
+			TargetURL: github.String("https://ci/1"),
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("Status event of another context doesn't update the PR", func(t *testing.T) {
+		prStoreMock.EXPECT().ListBySha("mattertest", "mattermod", "sha").Return([]*model.PullRequest{storedPR()}, nil)
+
+		err := s.statusEventHandler(context.Background(), &github.StatusEvent{
+			Repo:    repo,
+			SHA:     github.String("sha"),
+			Context: github.String("ci/lint"),
+			State:   github.String(stateError),
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("Unchanged status is not saved", func(t *testing.T) {
+		prStoreMock.EXPECT().ListBySha("mattertest", "mattermod", "sha").Return([]*model.PullRequest{storedPR()}, nil)
+
+		err := s.statusEventHandler(context.Background(), &github.StatusEvent{
+			Repo:    repo,
+			SHA:     github.String("sha"),
+			Context: github.String("ci/build"),
+			State:   github.String(statePending),
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("Store error is returned", func(t *testing.T) {
+		prStoreMock.EXPECT().ListBySha("mattertest", "mattermod", "sha").Return(nil, errors.New("some-error")).Times(3)
+
+		err := s.statusEventHandler(context.Background(), &github.StatusEvent{
+			Repo:    repo,
+			SHA:     github.String("sha"),
+			Context: github.String("ci/build"),
+			State:   github.String(stateSuccess),
+		})
+		require.Error(t, err)
+	})
+
+	t.Run("Check run event updates the PR", func(t *testing.T) {
+		prStoreMock.EXPECT().ListBySha("mattertest", "mattermod", "sha").Return([]*model.PullRequest{storedPR()}, nil).Times(3)
+		pmStoreMock.EXPECT().Get("mattertest", "mattermod", 1).Return(nil, nil)
+		prStoreMock.EXPECT().
+			Save(gomock.AssignableToTypeOf(&model.PullRequest{})).
+			DoAndReturn(func(pr *model.PullRequest) (*model.PullRequest, error) {
+				assert.Equal(t, "completed", pr.BuildStatus)
+				assert.Equal(t, "failure", pr.BuildConclusion)
+				assert.Equal(t, "https://checks/1", pr.BuildLink)
+				return pr, nil
+			})
+
+		err := s.checkRunEventHandler(context.Background(), &github.CheckRunEvent{
+			Repo: repo,
+			CheckRun: &github.CheckRun{
+				Name:       github.String("ci/build"),
+				HeadSHA:    github.String("sha"),
+				Status:     github.String("completed"),
+				Conclusion: github.String("failure"),
+				HTMLURL:    github.String("https://checks/1"),
+			},
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("Late check run events don't overwrite a completed build", func(t *testing.T) {
+		pr := storedPR()
+		pr.BuildStatus = "completed"
+		pr.BuildConclusion = "success"
+		pr.BuildLink = "https://checks/1"
+		prStoreMock.EXPECT().ListBySha("mattertest", "mattermod", "sha").Return([]*model.PullRequest{pr}, nil)
+
+		err := s.checkRunEventHandler(context.Background(), &github.CheckRunEvent{
+			Repo: repo,
+			CheckRun: &github.CheckRun{
+				Name:    github.String("ci/build"),
+				HeadSHA: github.String("sha"),
+				Status:  github.String("in_progress"),
+				HTMLURL: github.String("https://checks/1"),
+			},
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("Check runs requested again update a completed build", func(t *testing.T) {
+		pr := storedPR()
+		pr.BuildStatus = "completed"
+		pr.BuildConclusion = "failure"
+		pr.BuildLink = "https://checks/1"
+		prStoreMock.EXPECT().ListBySha("mattertest", "mattermod", "sha").Return([]*model.PullRequest{pr}, nil)
+		prStoreMock.EXPECT().
+			Save(gomock.AssignableToTypeOf(&model.PullRequest{})).
+			DoAndReturn(func(pr *model.PullRequest) (*model.PullRequest, error) {
+				assert.Equal(t, "queued", pr.BuildStatus)
+				assert.Equal(t, "https://checks/2", pr.BuildLink)
+				return pr, nil
+			})
+
+		err := s.checkRunEventHandler(context.Background(), &github.CheckRunEvent{
+			Repo: repo,
+			CheckRun: &github.CheckRun{
+				Name:    github.String("ci/build"),
+				HeadSHA: github.String("sha"),
+				Status:  github.String("queued"),
+				HTMLURL: github.String("https://checks/2"),
+			},
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("Check run event of another check tries pending merges once completed", func(t *testing.T) {
+		prStoreMock.EXPECT().ListBySha("mattertest", "mattermod", "sha").Return([]*model.PullRequest{storedPR()}, nil).Times(2)
+		pmStoreMock.EXPECT().Get("mattertest", "mattermod", 1).Return(nil, nil)
+
+		require.NoError(t, s.checkRunEventHandler(context.Background(), &github.CheckRunEvent{
+			Repo:     repo,
+			CheckRun: &github.CheckRun{Name: github.String("ci/lint"), HeadSHA: github.String("sha"), Status: github.String("in_progress")},
+		}))
+		require.NoError(t, s.checkRunEventHandler(context.Background(), &github.CheckRunEvent{
+			Repo:     repo,
+			CheckRun: &github.CheckRun{Name: github.String("ci/lint"), HeadSHA: github.String("sha"), Status: github.String("completed")},
+		}))
+	})
+
+	t.Run("Completed check suite catches up on a missed check run", func(t *testing.T) {
+		prStoreMock.EXPECT().ListBySha("mattertest", "mattermod", "sha").Return([]*model.PullRequest{storedPR()}, nil).Times(4)
+		pmStoreMock.EXPECT().Get("mattertest", "mattermod", 1).Return(nil, nil)
+		cs.EXPECT().
+			ListCheckRunsForRef(gomock.AssignableToTypeOf(ctxInterface), "mattertest", "mattermod", "sha", &github.ListCheckRunsOptions{CheckName: github.String("ci/build")}).
+			Return(&github.ListCheckRunsResults{
+				CheckRuns: []*github.CheckRun{{
+					Name:       github.String("ci/build"),
+					Status:     github.String("completed"),
+					Conclusion: github.String("success"),
+				}},
+			}, nil, nil)
+		prStoreMock.EXPECT().
+			Save(gomock.AssignableToTypeOf(&model.PullRequest{})).
+			DoAndReturn(func(pr *model.PullRequest) (*model.PullRequest, error) {
+				assert.Equal(t, "success", pr.BuildConclusion)
+				return pr, nil
+			})
+
+		err := s.checkSuiteEventHandler(context.Background(), &github.CheckSuiteEvent{
+			Action:     github.String("completed"),
+			Repo:       repo,
+			CheckSuite: &github.CheckSuite{HeadSHA: github.String("sha")},
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("Completed check suite of an up to date PR is not caught up on", func(t *testing.T) {
+		pr := storedPR()
+		pr.BuildStatus = "completed"
+		prStoreMock.EXPECT().ListBySha("mattertest", "mattermod", "sha").Return([]*model.PullRequest{pr}, nil).Times(3)
+		pmStoreMock.EXPECT().Get("mattertest", "mattermod", 1).Return(nil, nil)
+
+		err := s.checkSuiteEventHandler(context.Background(), &github.CheckSuiteEvent{
+			Action:     github.String("completed"),
+			Repo:       repo,
+			CheckSuite: &github.CheckSuite{HeadSHA: github.String("sha")},
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("Requested check suite is ignored", func(t *testing.T) {
+		err := s.checkSuiteEventHandler(context.Background(), &github.CheckSuiteEvent{
+			Action:     github.String("requested"),
+			Repo:       repo,
+			CheckSuite: &github.CheckSuite{HeadSHA: github.String("sha")},
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("Tick refreshes the PRs whose build hasn't finished", func(t *testing.T) {
+		finished := storedPR()
+		finished.Number = 2
+		finished.Sha = "finished-sha"
+		finished.BuildStatus = stateFailure
+		completed := storedPR()
+		completed.Number = 3
+		completed.Sha = "completed-sha"
+		completed.BuildStatus = "completed"
+		prStoreMock.EXPECT().ListOpen().Return([]*model.PullRequest{storedPR(), finished, completed}, nil)
+		rs.EXPECT().
+			GetCombinedStatus(gomock.AssignableToTypeOf(ctxInterface), "mattertest", "mattermod", "sha", nil).
+			Return(&github.CombinedStatus{
+				Statuses: []*github.RepoStatus{{
+					Context:   github.String("ci/build"),
+					State:     github.String(stateSuccess),
+					TargetURL: github.String("https://ci/1"),
+				}},
+			}, nil, nil)
+		cs.EXPECT().
+			ListCheckRunsForRef(gomock.AssignableToTypeOf(ctxInterface), "mattertest", "mattermod", "sha", nil).
+			Return(&github.ListCheckRunsResults{}, nil, nil)
+		prStoreMock.EXPECT().ListBySha("mattertest", "mattermod", "sha").Return([]*model.PullRequest{storedPR()}, nil)
+		prStoreMock.EXPECT().
+			Save(gomock.AssignableToTypeOf(&model.PullRequest{})).
+			DoAndReturn(func(pr *model.PullRequest) (*model.PullRequest, error) {
+				assert.Equal(t, 1, pr.Number)
+				assert.Equal(t, stateSuccess, pr.BuildStatus)
+				assert.Equal(t, "https://ci/1", pr.BuildLink)
+				return pr, nil
+			})
+
+		require.NoError(t, s.refreshBuildStatuses(context.Background()))
+	})
+}
+
+func TestRouteBuildStatusEvents(t *testing.T) {
+	for eventType, payload := range map[string]string{
+		"status":      `{"sha": "sha", "context": "ci/build", "state": "success", "repository": {"full_name": "mattertest/mattermod"}}`,
+		"check_run":   `{"action": "completed", "check_run": {"name": "ci/build", "head_sha": "sha"}, "repository": {"full_name": "mattertest/mattermod"}}`,
+		"check_suite": `{"action": "completed", "check_suite": {"head_sha": "sha"}, "repository": {"full_name": "mattertest/mattermod"}}`,
+	} {
+		require.True(t, isQueuedEvent(eventType), eventType)
+
+		event, err := routeEvent(eventType, []byte(payload))
+		require.NoError(t, err, eventType)
+		assert.Equal(t, eventType, event.route)
+		assert.Contains(t, event.summary, "mattertest/mattermod@sha")
+	}
+}
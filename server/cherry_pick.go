@@ -6,12 +6,10 @@ package server
 import (
 	"context"
 	"fmt"
-	"math/rand"
+	"net/http"
+	"net/url"
 	"os"
-	"os/exec"
 	"path/filepath"
-	"regexp"
-	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -24,115 +22,391 @@ import (
 )
 
 const (
-	cherryPickScheduledMsg = "Cherry pick is scheduled."
-	tooManyCherryPickMsg   = "There are too many cherry picking requests. Please do this manually or try again later."
-	milestoneCloud         = "cloud"
+	milestoneCloud = "cloud"
+
+	defaultCherryPickWorkers     = 2
+	defaultCherryPickMaxAttempts = 3
+	cherryPickRetryBaseDelay     = time.Minute
+	cherryPickPollInterval       = time.Minute
+	cherryPickShutdownTimeout    = 5 * time.Second
 )
 
-type cherryPickRequest struct {
-	pr        *model.PullRequest
-	milestone *int
-	version   string
+func (s *Server) cherryPickWorkers() int {
+	if s.Config.CherryPickWorkers > 0 {
+		return s.Config.CherryPickWorkers
+	}
+	return defaultCherryPickWorkers
 }
 
-func (s *Server) listenCherryPickRequests() {
-	defer func() {
-		close(s.cherryPickStoppedChan)
-	}()
+// startCherryPickWorkers starts the workers running the stored cherry pick
+// jobs. Jobs left running by a previous process are run again, in fresh
+// worktrees.
+func (s *Server) startCherryPickWorkers() {
+	s.cleanupCherryPickWorktrees()
+	if err := s.Store.CherryPickJob().ResetRunning(); err != nil {
+		mlog.Error("Failed to reset running cherry pick jobs", mlog.Err(err))
+	}
 
-	for job := range s.cherryPickRequests {
-		func() {
-			ctx, cancel := context.WithTimeout(context.Background(), defaultRequestTimeout*10*time.Second)
-			defer cancel()
-			pr := job.pr
-			cmdOut, err := s.doCherryPick(ctx, strings.TrimSpace(job.version), job.milestone, pr)
-			if err != nil {
-				msg := fmt.Sprintf("Error trying doing the automated Cherry picking. Please do this manually\n\n```\n%s\n```\n", cmdOut)
-				if cErr := s.sendGitHubComment(ctx, pr.RepoOwner, pr.RepoName, pr.Number, msg); cErr != nil {
-					mlog.Warn("Error while commenting", mlog.Err(cErr))
-				}
-				mlog.Error("Error while cherry picking", mlog.Err(err))
-			}
-		}()
+	for i := 0; i < s.cherryPickWorkers(); i++ {
+		s.cherryPickWorkersWG.Add(1)
+		go s.listenCherryPickJobs()
 	}
 }
 
-func (s *Server) finishCherryPickRequests() {
+// stopCherryPickWorkers gives the current jobs some time to finish. Jobs
+// which are still running stay so in the store and are resumed on the next
+// start.
+func (s *Server) stopCherryPickWorkers() {
 	close(s.cherryPickStopChan)
-	close(s.cherryPickRequests)
+
+	stopped := make(chan struct{})
+	go func() {
+		s.cherryPickWorkersWG.Wait()
+		close(stopped)
+	}()
 	select {
-	case <-time.After(5 * time.Second):
-		ctx, cancel := context.WithTimeout(context.Background(), defaultRequestTimeout*time.Second)
-		defer cancel()
-		// While consuming requests here, listenCherryPickRequests routine will continue
-		// to listen as well. We're just trying to cancel requests as much as we can.
-		msg := "Cherry picking is canceled due to server shutdown."
-		for job := range s.cherryPickRequests {
-			if err := s.sendGitHubComment(ctx, job.pr.RepoOwner, job.pr.RepoName, job.pr.Number, msg); err != nil {
-				mlog.Warn("Error while commenting", mlog.Err(err))
-			}
+	case <-stopped:
+	case <-time.After(cherryPickShutdownTimeout):
+		mlog.Warn("Cherry picks are still running, they will be resumed after the restart")
+	}
+}
+
+// wakeCherryPickWorkers tells idle workers about new jobs, so that they don't
+// wait for the next poll.
+func (s *Server) wakeCherryPickWorkers() {
+	for i := 0; i < cap(s.cherryPickWakeChan); i++ {
+		select {
+		case s.cherryPickWakeChan <- struct{}{}:
+		default:
+			return
 		}
-	case <-s.cherryPickStoppedChan:
 	}
 }
 
-func (s *Server) handleCherryPick(ctx context.Context, commenter, body string, pr *model.PullRequest) error {
-	var msg string
-	defer func() {
-		if msg != "" {
-			if err := s.sendGitHubComment(ctx, pr.RepoOwner, pr.RepoName, pr.Number, msg); err != nil {
-				mlog.Warn("Error while commenting", mlog.Err(err))
-			}
+func (s *Server) listenCherryPickJobs() {
+	defer s.cherryPickWorkersWG.Done()
+
+	ticker := time.NewTicker(cherryPickPollInterval)
+	defer ticker.Stop()
+
+	for {
+		s.runPendingCherryPickJobs()
+		select {
+		case <-s.cherryPickStopChan:
+			return
+		case <-s.cherryPickWakeChan:
+		case <-ticker.C:
 		}
-	}()
+	}
+}
 
-	if !s.IsOrgMember(commenter) || s.IsInBotBlockList(commenter) {
-		msg = msgCommenterPermission
-		return nil
+// runPendingCherryPickJobs runs the pending jobs, oldest first, until there
+// are none left.
+func (s *Server) runPendingCherryPickJobs() {
+	for {
+		select {
+		case <-s.cherryPickStopChan:
+			return
+		default:
+		}
+
+		jobs, err := s.Store.CherryPickJob().ListPending(time.Now(), 1)
+		if err != nil {
+			mlog.Error("Failed to list pending cherry pick jobs", mlog.Err(err))
+			return
+		}
+		if len(jobs) == 0 {
+			return
+		}
+		if err = s.processCherryPickJob(jobs[0]); err != nil {
+			mlog.Error("Failed to process cherry pick job", mlog.Int64("job", jobs[0].ID), mlog.Err(err))
+			return
+		}
 	}
-	command := getCommand(body)
-	args := strings.Split(command, " ")
-	mlog.Info("Args", mlog.String("Args", body))
-	if !pr.GetMerged() {
+}
+
+func (s *Server) processCherryPickJob(job *model.CherryPickJob) error {
+	claimed, err := s.Store.CherryPickJob().Claim(job.ID)
+	if err != nil {
+		return err
+	}
+	if !claimed {
+		// Another worker got it first.
 		return nil
 	}
+	job.State = model.CherryPickJobRunning
+	job.Attempts++
+
+	ctx, cancel := context.WithTimeout(context.Background(), defaultRequestTimeout*10*time.Second)
+	defer cancel()
+
+	pr, err := s.Store.PullRequest().Get(job.RepoOwner, job.RepoName, job.Number)
+	if err != nil {
+		if job.Attempts < defaultCherryPickMaxAttempts {
+			return s.retryCherryPickJob(job, err)
+		}
+		job.State = model.CherryPickJobFailed
+		job.Output = err.Error()
+		return s.Store.CherryPickJob().Update(job)
+	}
+	if pr == nil {
+		job.State = model.CherryPickJobFailed
+		job.Output = "The PR to cherry pick could not be found."
+		return s.Store.CherryPickJob().Update(job)
+	}
 
-	if len(args) < 2 {
+	if job.Attempts > defaultCherryPickMaxAttempts {
+		job.State = model.CherryPickJobFailed
+		job.Output = fmt.Sprintf("Gave up after %d attempts which didn't finish.", defaultCherryPickMaxAttempts)
+		if err = s.Store.CherryPickJob().Update(job); err != nil {
+			return err
+		}
+		if !job.IsCherryPick() {
+			return nil
+		}
+		if err = s.saveBackport(pr, job.Branch, "", model.BackportFailed, 0); err != nil {
+			return err
+		}
+		s.updateCherryPickReport(ctx, pr)
 		return nil
 	}
 
-	select {
-	case <-s.cherryPickStopChan:
-		return errors.New("server is closing")
-	default:
+	switch job.Kind {
+	case model.CherryPickJobKindPreview:
+		return s.finishCherryPickJob(job, s.runCherryPickPreview(ctx, pr, job.Branch))
+	case model.CherryPickJobKindForwardPort:
+		pr.MergeCommitSHA = job.MergeCommitSHA
+		return s.finishCherryPickJob(job, s.runForwardPort(ctx, pr, job.Branch))
 	}
+	s.updateCherryPickReport(ctx, pr)
 
-	select {
-	case s.cherryPickRequests <- &cherryPickRequest{
-		pr:      pr,
-		version: strings.TrimSpace(args[1]),
-	}:
-		msg = cherryPickScheduledMsg
-	default:
-		msg = tooManyCherryPickMsg
-		return errors.New("too many requests")
+	pr.MergeCommitSHA = job.MergeCommitSHA
+	var milestone *int
+	if job.Milestone != 0 {
+		milestone = &job.Milestone
+	}
+	newPRNumber, err := s.doCherryPick(ctx, job.Branch, milestone, pr)
+	if err != nil {
+		mlog.Error("Error while cherry picking",
+			mlog.String("repo", job.RepoOwner+"/"+job.RepoName),
+			mlog.Int("pr", job.Number),
+			mlog.String("branch", job.Branch),
+			mlog.Err(err))
+		if isTransientError(err) && job.Attempts < defaultCherryPickMaxAttempts {
+			return s.retryCherryPickJob(job, err)
+		}
+		job.State = model.CherryPickJobFailed
+		job.Output, job.Conflicts = cherryPickFailure(err)
+	} else {
+		job.State = model.CherryPickJobSucceeded
+		job.Output, job.Conflicts = "", ""
+		job.NewPRNumber = newPRNumber
 	}
 
+	if err = s.Store.CherryPickJob().Update(job); err != nil {
+		return err
+	}
+	backportState := model.BackportOpen
+	if job.State == model.CherryPickJobFailed {
+		backportState = model.BackportFailed
+	}
+	if err = s.saveBackport(pr, job.Branch, "", backportState, job.NewPRNumber); err != nil {
+		return err
+	}
+	s.updateCherryPickReport(ctx, pr)
 	return nil
 }
 
-func getCommand(command string) string {
-	index := strings.Index(command, "/cherry-pick")
-	return command[index:]
+// finishCherryPickJob stores the outcome of a job which isn't a cherry pick.
+func (s *Server) finishCherryPickJob(job *model.CherryPickJob, err error) error {
+	job.State = model.CherryPickJobSucceeded
+	job.Output = ""
+	if err != nil {
+		mlog.Error("Error while running the job",
+			mlog.String("kind", job.Kind),
+			mlog.String("repo", job.RepoOwner+"/"+job.RepoName),
+			mlog.Int("pr", job.Number),
+			mlog.Err(err))
+		if isTransientError(err) && job.Attempts < defaultCherryPickMaxAttempts {
+			return s.retryCherryPickJob(job, err)
+		}
+		job.State = model.CherryPickJobFailed
+		job.Output = err.Error()
+	}
+	return s.Store.CherryPickJob().Update(job)
 }
 
-func (s *Server) checkIfNeedCherryPick(pr *model.PullRequest) {
-	// We create a new context here instead of using the parent one because this is being called from a goroutine.
-	// Ideally, the entire request needs to be handled asynchronously.
-	// See: https://github.com/mattermost/mattermost-mattermod/pull/166.
-	ctx, cancel := context.WithTimeout(context.Background(), defaultRequestTimeout*time.Second)
-	defer cancel()
+// retryCherryPickJob puts the job back in the queue after an attempt which
+// failed with a transient error. The delay doubles with every attempt.
+func (s *Server) retryCherryPickJob(job *model.CherryPickJob, err error) error {
+	delay := cherryPickRetryBaseDelay << (job.Attempts - 1)
+	job.State = model.CherryPickJobPending
+	job.Output = err.Error()
+	job.NextAttemptAt = model.GetMillisForTime(time.Now().Add(delay))
+	return s.Store.CherryPickJob().Update(job)
+}
+
+// isTransientError tells whether an attempt which failed with err may
+// succeed later: git commands, which mostly fail on network errors, and
+// GitHub errors other than rejected requests. Conflicts and permanent errors
+// don't go away. Joined errors are transient if all of them are.
+func isTransientError(err error) bool {
+	var conflictErr *cherryPickConflictError
+	var permErr *permanentError
+	if errors.As(err, &conflictErr) || errors.As(err, &permErr) {
+		return false
+	}
+	var multiErr multiError
+	if errors.As(err, &multiErr) {
+		for _, e := range multiErr {
+			if !isTransientError(e) {
+				return false
+			}
+		}
+		return true
+	}
+	var gitErr *gitError
+	var rateLimitErr *github.RateLimitError
+	var abuseErr *github.AbuseRateLimitError
+	var urlErr *url.Error
+	if errors.As(err, &gitErr) || errors.As(err, &rateLimitErr) || errors.As(err, &abuseErr) || errors.As(err, &urlErr) {
+		return true
+	}
+	var respErr *github.ErrorResponse
+	if errors.As(err, &respErr) {
+		return respErr.Response != nil && respErr.Response.StatusCode >= http.StatusInternalServerError
+	}
+	return errors.Is(err, context.DeadlineExceeded)
+}
+
+// cherryPickFailure returns what is stored about a failed cherry pick: the
+// output of a failed git command, or the files which conflicted.
+func cherryPickFailure(err error) (output, conflicts string) {
+	var conflictErr *cherryPickConflictError
+	if errors.As(err, &conflictErr) {
+		return "", strings.Join(conflictErr.files, "\n")
+	}
+	var gitErr *gitError
+	if errors.As(err, &gitErr) {
+		return err.Error() + "\n\n" + strings.TrimSpace(gitErr.output), ""
+	}
+	return err.Error(), ""
+}
 
+// handleCherryPick queues a cherry pick of the merged PR onto every branch in
+// args. Branches which are already queued or running are skipped.
+func (s *Server) handleCherryPick(ctx context.Context, args []string, pr *model.PullRequest) error {
+	if !pr.GetMerged() {
+		return fmt.Errorf("%w: the PR isn't merged", errNothingToDo)
+	}
+
+	if len(args) < 1 {
+		return fmt.Errorf("%w: no branches were given", errNothingToDo)
+	}
+
+	return s.queueCherryPicks(ctx, pr, args, 0)
+}
+
+// queueCherryPicks stores a job for each branch which doesn't have a pending
+// or running one yet, and updates the result comment on the PR.
+func (s *Server) queueCherryPicks(ctx context.Context, pr *model.PullRequest, branches []string, milestone int) error {
+	jobs, err := s.Store.CherryPickJob().ListByPR(pr.RepoOwner, pr.RepoName, pr.Number)
+	if err != nil {
+		return err
+	}
+	queued := map[string]bool{}
+	for _, job := range jobs {
+		if job.IsCherryPick() && (job.State == model.CherryPickJobPending || job.State == model.CherryPickJobRunning) {
+			queued[job.Branch] = true
+		}
+	}
+
+	created := false
+	for _, branch := range branches {
+		branch = strings.TrimSpace(branch)
+		if branch == "" || queued[branch] {
+			continue
+		}
+		queued[branch] = true
+
+		if err = s.Store.CherryPickJob().Create(&model.CherryPickJob{
+			RepoOwner:      pr.RepoOwner,
+			RepoName:       pr.RepoName,
+			Number:         pr.Number,
+			Branch:         branch,
+			MergeCommitSHA: pr.MergeCommitSHA,
+			Milestone:      milestone,
+			State:          model.CherryPickJobPending,
+		}); err != nil {
+			return err
+		}
+		if err = s.saveBackport(pr, branch, pr.GetMilestoneTitle(), model.BackportQueued, 0); err != nil {
+			return err
+		}
+		created = true
+	}
+
+	if created {
+		s.updateCherryPickReport(ctx, pr)
+		s.wakeCherryPickWorkers()
+	}
+	return nil
+}
+
+// queueCherryPickJob stores a job of another kind than cherry pick for the
+// PR, unless the same job is pending already.
+func (s *Server) queueCherryPickJob(pr *model.PullRequest, kind, branch string) error {
+	jobs, err := s.Store.CherryPickJob().ListByPR(pr.RepoOwner, pr.RepoName, pr.Number)
+	if err != nil {
+		return err
+	}
+	for _, job := range jobs {
+		if job.Kind == kind && job.Branch == branch && job.State == model.CherryPickJobPending {
+			return nil
+		}
+	}
+
+	if err = s.Store.CherryPickJob().Create(&model.CherryPickJob{
+		Kind:           kind,
+		RepoOwner:      pr.RepoOwner,
+		RepoName:       pr.RepoName,
+		Number:         pr.Number,
+		Branch:         branch,
+		MergeCommitSHA: pr.MergeCommitSHA,
+		State:          model.CherryPickJobPending,
+	}); err != nil {
+		return err
+	}
+	s.wakeCherryPickWorkers()
+	return nil
+}
+
+// cherryPickAutomation schedules the cherry picks of approved PRs once they
+// are merged, and follows what becomes of the cherry pick PRs.
+type cherryPickAutomation struct {
+	baseAutomation
+	s *Server
+}
+
+func (a *cherryPickAutomation) Name() string {
+	return "cherry-pick"
+}
+
+func (a *cherryPickAutomation) OnPullRequest(ctx context.Context, event *pullRequestEvent, pr *model.PullRequest) error {
+	switch event.Action {
+	case prEventClosed:
+		a.s.checkIfNeedCherryPick(ctx, pr, event.PullRequest.GetBase().GetRef())
+		return a.s.updateBackportState(ctx, pr)
+	case prEventReOpened:
+		return a.s.updateBackportState(ctx, pr)
+	}
+	return nil
+}
+
+// checkIfNeedCherryPick queues the cherry pick of a merged PR with a trigger
+// label onto the branch of its milestone, unless it was merged into that
+// branch.
+func (s *Server) checkIfNeedCherryPick(ctx context.Context, pr *model.PullRequest, base string) {
 	if !pr.GetMerged() {
 		mlog.Info("PR not merged, not cherry picking", mlog.Int("PR Number", pr.Number), mlog.String("Repo", pr.RepoName))
 		return
@@ -148,158 +422,246 @@ func (s *Server) checkIfNeedCherryPick(pr *model.PullRequest) {
 		mlog.Error("Error listing the labels for PR", mlog.Err(err))
 		return
 	}
-	prLabels := labelsToStringArray(labels)
-	for _, prLabel := range prLabels {
-		if prLabel == "CherryPick/Approved" {
-			milestoneNumber := int(pr.GetMilestoneNumber())
-			milestone := getMilestone(pr.GetMilestoneTitle())
-
-			select {
-			case <-s.cherryPickStopChan:
-				return
-			default:
-			}
-
-			var msg string
-			select {
-			case s.cherryPickRequests <- &cherryPickRequest{
-				pr:        pr,
-				version:   strings.TrimSpace(milestone),
-				milestone: &milestoneNumber,
-			}:
-				msg = cherryPickScheduledMsg
-			default:
-				msg = tooManyCherryPickMsg
-			}
-			if err := s.sendGitHubComment(ctx, pr.RepoOwner, pr.RepoName, pr.Number, msg); err != nil {
-				mlog.Warn("Error while commenting", mlog.Err(err))
-			}
-		}
+	if !s.hasCherryPickTrigger(pr.RepoOwner, pr.RepoName, labelsToStringArray(labels)) {
+		return
 	}
-}
 
-func getMilestone(title string) string {
-	milestone := strings.TrimSpace(title)
-	milestone = strings.Trim(milestone, "v")
-	milestone = strings.TrimSuffix(milestone, ".0")
-	if title != milestoneCloud {
-		milestone = fmt.Sprintf("release-%s", milestone)
+	target, err := s.cherryPickTarget(pr.RepoOwner, pr.RepoName, pr.GetMilestoneTitle())
+	if err != nil {
+		mlog.Error("Error getting the cherry pick branch", mlog.Err(err))
+		return
+	}
+	if target == base {
+		mlog.Info("PR was merged into the branch of its milestone, not cherry picking", mlog.Int("PR Number", pr.Number), mlog.String("Repo", pr.RepoName))
+		return
+	}
+	if err := s.queueCherryPicks(ctx, pr, []string{target}, int(pr.GetMilestoneNumber())); err != nil {
+		mlog.Error("Error queueing the cherry pick", mlog.Err(err))
 	}
-	return milestone
 }
 
-func (s *Server) doCherryPick(ctx context.Context, version string, milestoneNumber *int, pr *model.PullRequest) (cmdOutput string, err error) {
+// doCherryPick cherry picks the PR onto the release branch and returns the
+// number of the new PR. Conflicts are returned as cherryPickConflictError.
+func (s *Server) doCherryPick(ctx context.Context, version string, milestoneNumber *int, pr *model.PullRequest) (int, error) {
 	if pr.MergeCommitSHA == "" {
-		return "", errors.Errorf("can't get merge commit SHA for PR: %d", pr.Number)
+		return 0, errors.Errorf("can't get merge commit SHA for PR: %d", pr.Number)
+	}
+	if pr.Ref == "" {
+		return 0, errors.Errorf("can't get source branch for PR: %d", pr.Number)
 	}
 
 	if s.Config.RepoFolder == "" {
-		return "", errors.Errorf("path to folder containing local checkout of repositories is not set in the config")
+		return 0, errors.Errorf("path to folder containing local checkout of repositories is not set in the config")
 	}
-	repoFolder := filepath.Join(s.Config.RepoFolder, pr.RepoName)
 
-	if _, err = os.Stat(repoFolder); os.IsNotExist(err) {
-		err = cloneRepo(ctx, s.Config, pr.RepoName)
-		if err != nil {
-			return "", fmt.Errorf("error while cloning repo: %s, %v", s.Config.Org+"/"+pr.RepoName, err)
-		}
+	head := pullHeadRef(pr.Number)
+	mirror, worktree, err := s.addCherryPickWorktree(ctx, pr, version, "+"+head+":"+head)
+	if err != nil {
+		return 0, err
 	}
+	defer s.removeCherryPickWorktree(pr, mirror, worktree)
 
-	if s.Config.ScriptsFolder == "" {
-		return "", errors.Errorf("path to folder containing the cherry-pick.sh script is not set in the config")
+	commits, mainline, err := mergedCommits(ctx, worktree, pr.MergeCommitSHA, head)
+	if err != nil {
+		return 0, err
+	}
+	newBranch := cherryPickBranchName(pr.Ref, version)
+	gitCtx, err := s.gitContext(ctx, pr.RepoOwner)
+	if err != nil {
+		return 0, err
+	}
+	if err = cherryPickCommits(gitCtx, worktree, version, commits, mainline, newBranch); err != nil {
+		return 0, err
 	}
-	cherryPickScript := filepath.Join(s.Config.ScriptsFolder, "cherry-pick.sh")
 
-	releaseBranch := fmt.Sprintf("upstream/%s", version)
-	cmd := exec.Command(cherryPickScript, releaseBranch, strconv.Itoa(pr.Number), pr.MergeCommitSHA, pr.Ref)
-	cmd.Dir = repoFolder
-	cmd.Env = append(
-		os.Environ(),
-		os.Getenv("PATH"),
-		fmt.Sprintf("ORIGINAL_AUTHOR=%s", pr.Username),
-		fmt.Sprintf("GITHUB_USER=%s", s.Config.GithubUsername),
-		fmt.Sprintf("GITHUB_TOKEN=%s", s.Config.GithubAccessTokenCherryPick),
-	)
-	out, err := cmd.CombinedOutput()
+	policy := s.cherryPickPolicy(pr.RepoOwner, pr.RepoName)
+	reviewers := s.chooseCherryPickReviewers(ctx, pr, policy)
+	body := fmt.Sprintf("Cherry pick of #%d on %s.\n\n/cc @%s\n\n", pr.Number, version, pr.Username)
+	if len(reviewers.reasons) > 0 {
+		body += strings.Join(reviewers.reasons, "\n") + "\n\n"
+	}
+	// A retried job may have created the PR before it failed.
+	newPRNumber, err := s.findCherryPickPR(ctx, pr, newBranch, version)
 	if err != nil {
-		mlog.Error("cmd.Run() failed",
-			mlog.Err(err),
-			mlog.String("cmdOut", string(out)),
-			mlog.String("repo", pr.RepoName),
-			mlog.Int("PR", pr.Number),
-		)
-		err2 := returnToMaster(ctx, repoFolder)
-		if err2 != nil {
-			mlog.Error("Failed to return to master", mlog.Err(err2))
+		return 0, err
+	}
+	if newPRNumber == 0 {
+		newPR, _, createErr := s.GithubClient.PullRequests.Create(ctx, pr.RepoOwner, pr.RepoName, &github.NewPullRequest{
+			Title: github.String(fmt.Sprintf("Automated cherry pick of #%d", pr.Number)),
+			Head:  github.String(newBranch),
+			Base:  github.String(version),
+			Body:  github.String(body + "```release-note\nNONE\n```\n"),
+		})
+		if createErr != nil {
+			return 0, fmt.Errorf("could not create the cherry pick PR from %s: %w", newBranch, createErr)
 		}
-		return string(out), err
+		newPRNumber = newPR.GetNumber()
 	}
-	gitHubPR := regexp.MustCompile(`https://github.com/mattermost/.*/pull/[0-9]+`)
-	newPRURL := gitHubPR.FindString(string(out))
-	newPR := strings.Split(newPRURL, "/")
-	newPRNumber, _ := strconv.Atoi(newPR[len(newPR)-1])
-	assignee := s.getAssignee(ctx, newPRNumber, pr)
 
 	if milestoneNumber != nil {
 		s.addMilestone(ctx, newPRNumber, pr, milestoneNumber)
 	}
-	s.updateCherryPickLabels(ctx, newPRNumber, pr)
-	s.addReviewers(ctx, newPRNumber, pr, []string{assignee})
-	s.addAssignee(ctx, newPRNumber, pr, []string{assignee})
-	err = returnToMaster(ctx, repoFolder)
+	s.updateCherryPickLabels(ctx, newPRNumber, pr, policy)
+	s.requestCherryPickReviews(ctx, newPRNumber, pr, reviewers)
+	return newPRNumber, nil
+}
+
+// findCherryPickPR returns the number of the open PR from the cherry pick
+// branch onto the release branch, or 0 if there is none.
+func (s *Server) findCherryPickPR(ctx context.Context, pr *model.PullRequest, newBranch, version string) (int, error) {
+	prs, _, err := s.GithubClient.PullRequests.List(ctx, pr.RepoOwner, pr.RepoName, &github.PullRequestListOptions{
+		State: model.StateOpen,
+		Head:  pr.RepoOwner + ":" + newBranch,
+		Base:  version,
+	})
 	if err != nil {
-		mlog.Error("Failed to return to master", mlog.Err(err))
+		return 0, fmt.Errorf("could not look for the cherry pick PR from %s: %w", newBranch, err)
+	}
+	if len(prs) == 0 {
+		return 0, nil
 	}
-	return "", nil
+	return prs[0].GetNumber(), nil
 }
 
-func (s *Server) getAssignee(ctx context.Context, newPRNumber int, pr *model.PullRequest) string {
-	var once sync.Once
-	once.Do(func() {
-		rand.Seed(time.Now().Unix())
-	})
+// gitContext returns a context in which git commands authenticate to the
+// repositories of the owner, if there is a token for them.
+func (s *Server) gitContext(ctx context.Context, owner string) (context.Context, error) {
+	token, err := s.githubToken(ctx, owner)
+	if err != nil {
+		return nil, fmt.Errorf("could not get a token for %s: %w", owner, err)
+	}
+	if token == "" {
+		return ctx, nil
+	}
+	return withGitToken(ctx, token), nil
+}
 
-	var assignee string
-	if s.IsOrgMember(pr.Username) {
-		// He/She can review the PR herself/himself
-		assignee = pr.Username
-	} else {
-		// We have to get a random reviewer from the original PR
-		// Get the reviewers from the cherry pick PR
-		mlog.Debug("not org member", mlog.String("user", pr.Username))
-		reviewersFromPR, _, err := s.GithubClient.PullRequests.ListReviews(ctx, pr.RepoOwner, pr.RepoName, pr.Number, nil)
-		if err != nil {
-			mlog.Error("Error getting the reviewers from the original PR", mlog.Err(err), mlog.Int("PR", pr.Number), mlog.String("Repo", pr.RepoName))
-			return ""
+// mirrorLock returns the lock guarding the mirror of a repository. Fetching
+// and adding or removing worktrees must not run concurrently on a mirror.
+func (s *Server) mirrorLock(repoOwner, repoName string) *sync.Mutex {
+	s.cherryPickMirrorsLock.Lock()
+	defer s.cherryPickMirrorsLock.Unlock()
+
+	if s.cherryPickMirrors == nil {
+		s.cherryPickMirrors = map[string]*sync.Mutex{}
+	}
+	key := repoOwner + "/" + repoName
+	if _, ok := s.cherryPickMirrors[key]; !ok {
+		s.cherryPickMirrors[key] = &sync.Mutex{}
+	}
+	return s.cherryPickMirrors[key]
+}
+
+// addCherryPickWorktree fetches the mirror of the repository and checks out
+// the target branch in a new worktree for the cherry pick. Refspecs to fetch
+// on top of the branches, like the head of a PR, can be given.
+func (s *Server) addCherryPickWorktree(ctx context.Context, pr *model.PullRequest, target string, refspecs ...string) (mirror, worktree string, err error) {
+	mirror = filepath.Join(s.Config.RepoFolder, mirrorsFolder, pr.RepoOwner, pr.RepoName+".git")
+	worktrees := filepath.Join(s.Config.RepoFolder, worktreesFolder)
+	for _, dir := range []string{filepath.Dir(mirror), worktrees} {
+		if err = os.MkdirAll(dir, 0750); err != nil {
+			return "", "", err
 		}
+	}
+
+	lock := s.mirrorLock(pr.RepoOwner, pr.RepoName)
+	lock.Lock()
+	defer lock.Unlock()
+
+	ctx, err = s.gitContext(ctx, pr.RepoOwner)
+	if err != nil {
+		return "", "", err
+	}
+	remote := githubRemoteURL(pr.RepoOwner, pr.RepoName)
+	if err = ensureMirror(ctx, s.Config, mirror, remote); err != nil {
+		return "", "", fmt.Errorf("could not set up the mirror of %s/%s: %w", pr.RepoOwner, pr.RepoName, err)
+	}
+	// Mirrors set up before tokens were passed per command have one in
+	// their remote. Without a token the remote is left as it was set up.
+	if _, hasToken := ctx.Value(gitTokenKey{}).(string); hasToken {
+		if _, err = runGit(ctx, mirror, "remote", "set-url", "upstream", remote); err != nil {
+			return "", "", err
+		}
+	}
+	// Refspecs given on the command line replace the configured one.
+	fetchArgs := append([]string{"fetch", "--prune", "upstream", "+refs/heads/*:refs/remotes/upstream/*"}, refspecs...)
+	if _, err = runGit(ctx, mirror, fetchArgs...); err != nil {
+		return "", "", err
+	}
+
+	worktree, err = os.MkdirTemp(worktrees, fmt.Sprintf("%s-%s-%d-", pr.RepoOwner, pr.RepoName, pr.Number))
+	if err != nil {
+		return "", "", err
+	}
+	if err = addWorktree(ctx, mirror, worktree, target); err != nil {
+		if rmErr := os.RemoveAll(worktree); rmErr != nil {
+			mlog.Warn("Failed to remove the cherry pick worktree", mlog.String("worktree", worktree), mlog.Err(rmErr))
+		}
+		return "", "", err
+	}
+	return mirror, worktree, nil
+}
 
-		randomReviewer := rand.Intn(len(reviewersFromPR) - 1) // nolint
-		assignee = reviewersFromPR[randomReviewer].User.GetLogin()
+func (s *Server) removeCherryPickWorktree(pr *model.PullRequest, mirror, worktree string) {
+	lock := s.mirrorLock(pr.RepoOwner, pr.RepoName)
+	lock.Lock()
+	defer lock.Unlock()
+
+	// The job's context may be done already, cleaning up must not depend on it.
+	ctx, cancel := context.WithTimeout(context.Background(), defaultRequestTimeout*time.Second)
+	defer cancel()
+	if err := removeWorktree(ctx, mirror, worktree); err != nil {
+		mlog.Warn("Failed to remove the cherry pick worktree", mlog.String("worktree", worktree), mlog.Err(err))
+	}
+}
+
+// cleanupCherryPickWorktrees removes the worktrees left behind by cherry
+// picks which were interrupted. It must only run while no job is running.
+func (s *Server) cleanupCherryPickWorktrees() {
+	if s.Config.RepoFolder == "" {
+		return
+	}
+	if err := os.RemoveAll(filepath.Join(s.Config.RepoFolder, worktreesFolder)); err != nil {
+		mlog.Error("Failed to remove stale cherry pick worktrees", mlog.Err(err))
 	}
 
-	return assignee
+	mirrors, err := filepath.Glob(filepath.Join(s.Config.RepoFolder, mirrorsFolder, "*", "*.git"))
+	if err != nil {
+		mlog.Error("Failed to list the mirrors", mlog.Err(err))
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), defaultRequestTimeout*time.Second)
+	defer cancel()
+	for _, mirror := range mirrors {
+		if _, err = runGit(ctx, mirror, "worktree", "prune"); err != nil {
+			mlog.Warn("Failed to prune the worktrees of the mirror", mlog.String("mirror", mirror), mlog.Err(err))
+		}
+	}
 }
 
-func (s *Server) updateCherryPickLabels(ctx context.Context, newPRNumber int, pr *model.PullRequest) {
-	// Add the AutomatedCherryPick/Done in the new pr
-	labelsNewPR := []string{"AutomatedCherryPick", "Changelog/Not Needed", "Docs/Not Needed"}
-	_, _, err := s.GithubClient.Issues.AddLabelsToIssue(ctx, pr.RepoOwner, pr.RepoName, newPRNumber, labelsNewPR)
+func (s *Server) updateCherryPickLabels(ctx context.Context, newPRNumber int, pr *model.PullRequest, policy *CherryPickPolicy) {
+	_, _, err := s.GithubClient.Issues.AddLabelsToIssue(ctx, pr.RepoOwner, pr.RepoName, newPRNumber, policy.ResultLabels)
 	if err != nil {
 		mlog.Error("Error applying the automated label in the new pr ", mlog.Err(err), mlog.Int("PR", newPRNumber), mlog.String("Repo", pr.RepoName))
 		return
 	}
 
-	// remove the CherryPick/Approved and add the CherryPick/Done
-	_, _, err = s.GithubClient.Issues.AddLabelsToIssue(ctx, pr.RepoOwner, pr.RepoName, pr.Number, []string{"CherryPick/Done"})
+	// Replace the trigger labels with the done label
+	_, _, err = s.GithubClient.Issues.AddLabelsToIssue(ctx, pr.RepoOwner, pr.RepoName, pr.Number, []string{policy.DoneLabel})
 	if err != nil {
 		mlog.Error("Error applying the automated label in the cherry pick pr ", mlog.Err(err), mlog.Int("PR", pr.Number), mlog.String("Repo", pr.RepoName))
 		return
 	}
 
-	_, err = s.GithubClient.Issues.RemoveLabelForIssue(ctx, pr.RepoOwner, pr.RepoName, pr.Number, "CherryPick/Approved")
-	if err != nil {
-		mlog.Error("Error removing the automated label in the cherry pick pr ", mlog.Err(err), mlog.Int("PR", pr.Number), mlog.String("Repo", pr.RepoName))
-		return
+	for _, label := range policy.TriggerLabels {
+		// The labels of PRs which were never stored are unknown.
+		if len(pr.Labels) > 0 && !contains(pr.Labels, label) {
+			continue
+		}
+		_, err = s.GithubClient.Issues.RemoveLabelForIssue(ctx, pr.RepoOwner, pr.RepoName, pr.Number, label)
+		if err != nil {
+			mlog.Error("Error removing the automated label in the cherry pick pr ", mlog.Err(err), mlog.Int("PR", pr.Number), mlog.String("Repo", pr.RepoName))
+		}
 	}
 }
 
@@ -314,10 +676,7 @@ func (s *Server) addMilestone(ctx context.Context, newPRNumber int, pr *model.Pu
 	}
 }
 
-func (s *Server) addReviewers(ctx context.Context, newPRNumber int, pr *model.PullRequest, reviewers []string) {
-	reviewReq := github.ReviewersRequest{
-		Reviewers: reviewers,
-	}
+func (s *Server) addReviewers(ctx context.Context, newPRNumber int, pr *model.PullRequest, reviewReq github.ReviewersRequest) {
 	_, _, err := s.GithubClient.PullRequests.RequestReviewers(ctx, pr.RepoOwner, pr.RepoName, newPRNumber, reviewReq)
 	if err != nil {
 		mlog.Error("Error setting the reviewers ", mlog.Err(err), mlog.Int("PR", newPRNumber), mlog.String("Repo", pr.RepoName))
@@ -332,80 +691,3 @@ func (s *Server) addAssignee(ctx context.Context, newPRNumber int, pr *model.Pul
 		return
 	}
 }
-
-func returnToMaster(ctx context.Context, dir string) error {
-	cmd := exec.CommandContext(ctx, "git", "checkout", "master")
-	if err := runCommand(cmd, dir); err != nil {
-		return err
-	}
-	return nil
-}
-
-func cloneRepo(ctx context.Context, cfg *Config, repoName string) error {
-	originSlug := cfg.GithubUsername + "/" + repoName
-	upstreamSlug := cfg.Org + "/" + repoName
-
-	// Clone repo
-	cmd := exec.CommandContext(ctx, "git", "clone", "git@github.com:"+originSlug+".git")
-	if err := runCommand(cmd, cfg.RepoFolder); err != nil {
-		return err
-	}
-
-	// Set username and email.
-	cmd = exec.CommandContext(ctx, "git", "config", "user.name")
-	if out, err := runCommandWithOutput(cmd, filepath.Join(cfg.RepoFolder, repoName)); err != nil {
-		return err
-	} else if out == "" { // this means username is not set
-		cmd = exec.CommandContext(ctx, "git", "config", "user.name", cfg.GithubUsername)
-		if err = runCommand(cmd, filepath.Join(cfg.RepoFolder, repoName)); err != nil {
-			return err
-		}
-	}
-
-	cmd = exec.CommandContext(ctx, "git", "config", "user.email")
-	if out, err := runCommandWithOutput(cmd, filepath.Join(cfg.RepoFolder, repoName)); err != nil {
-		return err
-	} else if out == "" { // this means email is not set
-		cmd = exec.CommandContext(ctx, "git", "config", "user.email", cfg.GithubEmail)
-		if err = runCommand(cmd, filepath.Join(cfg.RepoFolder, repoName)); err != nil {
-			return err
-		}
-	}
-
-	// Set git config merge.renameLimit
-	cmd = exec.CommandContext(ctx, "git", "config", "merge.renameLimit")
-	if out, err := runCommandWithOutput(cmd, filepath.Join(cfg.RepoFolder, repoName)); err != nil {
-		return err
-	} else if out == "" { // this means merge.renameLimit is not set
-		cmd = exec.CommandContext(ctx, "git", "config", "merge.renameLimit", cfg.GitConfigMergeRenameLimit)
-		if err = runCommand(cmd, filepath.Join(cfg.RepoFolder, repoName)); err != nil {
-			return err
-		}
-	}
-
-	// Set upstream
-	cmd = exec.CommandContext(ctx, "git", "remote", "add", "upstream", "git@github.com:"+upstreamSlug+".git")
-	if err := runCommand(cmd, filepath.Join(cfg.RepoFolder, repoName)); err != nil {
-		return err
-	}
-	return nil
-}
-
-func runCommand(cmd *exec.Cmd, dir string) error {
-	cmd.Dir = dir
-	cmd.Env = append(
-		os.Environ(),
-		os.Getenv("PATH"),
-	)
-	return cmd.Run()
-}
-
-func runCommandWithOutput(cmd *exec.Cmd, dir string) (string, error) {
-	cmd.Dir = dir
-	cmd.Env = append(
-		os.Environ(),
-		os.Getenv("PATH"),
-	)
-	out, err := cmd.CombinedOutput()
-	return string(out), err
-}
@@ -0,0 +1,356 @@
+// Copyright (c) 2017-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package server
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// gitError is a git command which failed, along with its output.
+type gitError struct {
+	args   []string
+	output string
+	err    error
+}
+
+func (e *gitError) Error() string {
+	return fmt.Sprintf("git %s failed: %v", strings.Join(e.args, " "), e.err)
+}
+
+func (e *gitError) Unwrap() error {
+	return e.err
+}
+
+// cherryPickConflictError is returned when the commit doesn't apply cleanly
+// onto the target branch.
+type cherryPickConflictError struct {
+	branch string
+	files  []string
+}
+
+func (e *cherryPickConflictError) Error() string {
+	return fmt.Sprintf("cherry pick onto %s conflicts in %s", e.branch, strings.Join(e.files, ", "))
+}
+
+type gitTokenKey struct{}
+
+// withGitToken returns a context in which git commands authenticate to
+// GitHub with the token. runGit hands the token to every command through its
+// environment, so that it is never written to the config of a mirror.
+func withGitToken(ctx context.Context, token string) context.Context {
+	return context.WithValue(ctx, gitTokenKey{}, token)
+}
+
+// gitAuthEnv returns the environment which configures the token as the
+// authorization header of the requests of git to GitHub.
+func gitAuthEnv(token string) []string {
+	credentials := base64.StdEncoding.EncodeToString([]byte("x-access-token:" + token))
+	return []string{
+		"GIT_CONFIG_COUNT=1",
+		"GIT_CONFIG_KEY_0=http.https://github.com/.extraheader",
+		"GIT_CONFIG_VALUE_0=AUTHORIZATION: basic " + credentials,
+	}
+}
+
+// runGit runs git in dir and returns its combined output. The token of the
+// context, if any, authenticates the command to GitHub.
+func runGit(ctx context.Context, dir string, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	if token, _ := ctx.Value(gitTokenKey{}).(string); token != "" {
+		cmd.Env = append(cmd.Env, gitAuthEnv(token)...)
+	}
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		redacted := make([]string, len(args))
+		for i, arg := range args {
+			redacted[i] = redactGitCredentials(arg)
+		}
+		return string(out), &gitError{args: redacted, output: redactGitCredentials(string(out)), err: err}
+	}
+	return string(out), nil
+}
+
+// gitCredentialsRegex matches the credentials of the remote URLs.
+var gitCredentialsRegex = regexp.MustCompile(`(https?://)[^/@\s]+@`)
+
+// redactGitCredentials removes the tokens of the remote URLs from the text,
+// like those of mirrors set up before tokens were passed per command, since
+// the errors of git commands end up in logs and comments.
+func redactGitCredentials(text string) string {
+	return gitCredentialsRegex.ReplaceAllString(text, "${1}")
+}
+
+// cherryPickBranchName names the branch of a cherry pick after the source
+// and the target branch, e.g. automated-cherry-pick-of-my-branch-release-7.1.
+func cherryPickBranchName(source, target string) string {
+	return strings.ReplaceAll(fmt.Sprintf("automated-cherry-pick-of-%s-%s", source, target), "/", "-")
+}
+
+// Folders in RepoFolder holding the bare mirrors of the repositories and
+// the worktrees of the running cherry picks.
+const (
+	mirrorsFolder   = "mirrors"
+	worktreesFolder = "worktrees"
+)
+
+// githubRemoteURL returns the URL git fetches from and pushes to the
+// repository with. It never holds credentials, see withGitToken.
+func githubRemoteURL(owner, name string) string {
+	return fmt.Sprintf("https://github.com/%s/%s.git", owner, name)
+}
+
+// ensureMirror creates a bare repository in dir with the upstream remote,
+// unless it exists already. It is only fetched by the cherry picks, so its
+// objects are reused and only new ones are downloaded.
+func ensureMirror(ctx context.Context, cfg *Config, dir, url string) error {
+	if _, err := os.Stat(dir); err == nil {
+		return nil
+	}
+
+	tmp := dir + ".tmp"
+	if err := os.RemoveAll(tmp); err != nil {
+		return err
+	}
+	if _, err := runGit(ctx, filepath.Dir(dir), "init", "--quiet", "--bare", tmp); err != nil {
+		return err
+	}
+
+	settings := [][]string{
+		{"remote", "add", "upstream", url},
+		{"config", "user.name", cfg.GithubUsername},
+		{"config", "user.email", cfg.GithubEmail},
+	}
+	if cfg.GitConfigMergeRenameLimit != "" {
+		settings = append(settings, []string{"config", "merge.renameLimit", cfg.GitConfigMergeRenameLimit})
+	}
+	for _, args := range settings {
+		if _, err := runGit(ctx, tmp, args...); err != nil {
+			return err
+		}
+	}
+
+	// The mirror only appears once it's set up, so that a crash doesn't leave
+	// a half configured one behind.
+	return os.Rename(tmp, dir)
+}
+
+// addWorktree checks out the target branch of upstream, detached, in a new
+// worktree of the mirror at path.
+func addWorktree(ctx context.Context, mirror, path, target string) error {
+	_, err := runGit(ctx, mirror, "worktree", "add", "--detach", path, "upstream/"+target)
+	return err
+}
+
+// removeWorktree deletes the worktree, along with any cherry pick left in
+// progress in it.
+func removeWorktree(ctx context.Context, mirror, path string) error {
+	if _, err := runGit(ctx, mirror, "worktree", "remove", "--force", path); err != nil {
+		if rmErr := os.RemoveAll(path); rmErr != nil {
+			return rmErr
+		}
+		_, err = runGit(ctx, mirror, "worktree", "prune")
+		return err
+	}
+	return nil
+}
+
+// pullHeadRef is the ref GitHub keeps at the head of a PR, also for PRs from
+// forks.
+func pullHeadRef(number int) string {
+	return fmt.Sprintf("refs/pull/%d/head", number)
+}
+
+// mergedCommits returns the commits which landed on the base branch when the
+// PR was merged, oldest first, given its merge commit and the head of the
+// PR. Merge commits are returned alone with mainline set, so that they are
+// cherry picked against their first parent. Rebase merges are recognized by
+// the commits before the merge commit matching the commits of the PR, which
+// keep their author, author date and subject when rebased. Anything else is
+// a squash merge.
+func mergedCommits(ctx context.Context, dir, sha, head string) (commits []string, mainline bool, err error) {
+	out, err := runGit(ctx, dir, "rev-list", "--parents", "-n", "1", sha)
+	if err != nil {
+		return nil, false, err
+	}
+	if len(strings.Fields(out)) > 2 {
+		return []string{sha}, true, nil
+	}
+
+	forkPoint, err := runGit(ctx, dir, "merge-base", head, sha+"^")
+	if err != nil {
+		return nil, false, err
+	}
+	prCommits, err := logCommits(ctx, dir, "--no-merges", strings.TrimSpace(forkPoint)+".."+head)
+	if err != nil {
+		return nil, false, err
+	}
+	if len(prCommits) <= 1 {
+		return []string{sha}, false, nil
+	}
+
+	landed, err := logCommits(ctx, dir, "--first-parent", "-n", strconv.Itoa(len(prCommits)), sha)
+	if err != nil {
+		return nil, false, err
+	}
+	if len(landed) != len(prCommits) {
+		return []string{sha}, false, nil
+	}
+	for i := range landed {
+		if landed[i].identity != prCommits[i].identity {
+			return []string{sha}, false, nil
+		}
+	}
+	for _, commit := range landed {
+		commits = append(commits, commit.sha)
+	}
+	return commits, false, nil
+}
+
+type loggedCommit struct {
+	sha string
+	// identity is what a commit keeps when it's rebased.
+	identity string
+}
+
+// logCommits lists the commits selected by args, oldest first.
+func logCommits(ctx context.Context, dir string, args ...string) ([]loggedCommit, error) {
+	out, err := runGit(ctx, dir, append([]string{"log", "--reverse", "--format=%H %at %ae %s"}, args...)...)
+	if err != nil {
+		return nil, err
+	}
+	var commits []loggedCommit
+	for _, line := range strings.Split(strings.TrimSpace(out), "\n") {
+		if fields := strings.SplitN(line, " ", 2); len(fields) == 2 {
+			commits = append(commits, loggedCommit{sha: fields[0], identity: fields[1]})
+		}
+	}
+	return commits, nil
+}
+
+// cherryPickCommits cherry picks the commits onto the checkout of the
+// worktree and pushes the result to upstream as newBranch. Every commit gets
+// a trailer naming the commit it was cherry picked from.
+func cherryPickCommits(ctx context.Context, worktree, target string, commits []string, mainline bool, newBranch string) error {
+	args := []string{"cherry-pick", "-x"}
+	if mainline {
+		args = append(args, "-m", "1")
+	}
+	if _, err := runGit(ctx, worktree, append(args, commits...)...); err != nil {
+		files, diffErr := conflictingFiles(ctx, worktree)
+		if diffErr == nil && len(files) > 0 {
+			return &cherryPickConflictError{branch: target, files: files}
+		}
+		return err
+	}
+
+	_, err := runGit(ctx, worktree, "push", "--force", "upstream", "HEAD:refs/heads/"+newBranch)
+	return err
+}
+
+// previewCherryPick checks out the target branch in the worktree and cherry
+// picks the changes of a PR onto it without committing them. The commits of the PR are squashed first,
+// like the merge commit which will be cherry picked once the PR is merged, so
+// that conflicts solved within the PR aren't reported.
+func previewCherryPick(ctx context.Context, worktree, target, base, head string) error {
+	if _, err := runGit(ctx, worktree, "checkout", "--quiet", "--detach", "upstream/"+target); err != nil {
+		return err
+	}
+	mergeBase, err := runGit(ctx, worktree, "merge-base", "upstream/"+base, head)
+	if err != nil {
+		return err
+	}
+	squashed, err := runGit(ctx, worktree, "commit-tree", head+"^{tree}", "-p", strings.TrimSpace(mergeBase), "-m", "Cherry pick preview")
+	if err != nil {
+		return err
+	}
+
+	if _, err = runGit(ctx, worktree, "cherry-pick", "--no-commit", strings.TrimSpace(squashed)); err != nil {
+		files, diffErr := conflictingFiles(ctx, worktree)
+		if diffErr == nil && len(files) > 0 {
+			return &cherryPickConflictError{branch: target, files: files}
+		}
+		return err
+	}
+	return nil
+}
+
+// hasUpstreamBranch reports whether the branch was fetched from upstream
+// into the repository at dir.
+func hasUpstreamBranch(ctx context.Context, dir, branch string) bool {
+	_, err := runGit(ctx, dir, "rev-parse", "--verify", "--quiet", "refs/remotes/upstream/"+branch)
+	return err == nil
+}
+
+// cherryPickTrailerRegex matches the trailer added by cherry-pick -x.
+var cherryPickTrailerRegex = regexp.MustCompile(`\(cherry picked from commit ([0-9a-f]{40})\)`)
+
+// hasEquivalentCommit reports whether the change of commit is on the target
+// branch of upstream already. That is the case if the branch contains the
+// commit, a commit it was cherry picked from, a commit cherry picked from it,
+// or a commit with the same patch id.
+func hasEquivalentCommit(ctx context.Context, dir, target, commit string) (bool, error) {
+	upstreamTarget := "upstream/" + target
+	if isAncestor(ctx, dir, commit, upstreamTarget) {
+		return true, nil
+	}
+
+	message, err := runGit(ctx, dir, "log", "-1", "--format=%B", commit)
+	if err != nil {
+		return false, err
+	}
+	for _, match := range cherryPickTrailerRegex.FindAllStringSubmatch(message, -1) {
+		if isAncestor(ctx, dir, match[1], upstreamTarget) {
+			return true, nil
+		}
+	}
+
+	picked, err := runGit(ctx, dir, "log", "--format=%H", "-n", "1", "--fixed-strings",
+		"--grep=cherry picked from commit "+commit, commit+".."+upstreamTarget)
+	if err != nil {
+		return false, err
+	}
+	if strings.TrimSpace(picked) != "" {
+		return true, nil
+	}
+
+	// Commits of the target branch since the fork point which have the same
+	// patch id are marked with a minus.
+	out, err := runGit(ctx, dir, "cherry", upstreamTarget, commit, commit+"^")
+	if err != nil {
+		return false, err
+	}
+	return strings.HasPrefix(strings.TrimSpace(out), "-"), nil
+}
+
+// isAncestor reports whether commit is reachable from ref. Unknown commits
+// aren't.
+func isAncestor(ctx context.Context, dir, commit, ref string) bool {
+	_, err := runGit(ctx, dir, "merge-base", "--is-ancestor", commit, ref)
+	return err == nil
+}
+
+// conflictingFiles lists the unmerged files of the cherry pick in progress.
+func conflictingFiles(ctx context.Context, dir string) ([]string, error) {
+	out, err := runGit(ctx, dir, "diff", "--name-only", "-z", "--diff-filter=U")
+	if err != nil {
+		return nil, err
+	}
+	var files []string
+	for _, file := range strings.Split(out, "\x00") {
+		if file != "" {
+			files = append(files, file)
+		}
+	}
+	return files, nil
+}
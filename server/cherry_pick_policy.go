@@ -0,0 +1,115 @@
+// Copyright (c) 2017-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package server
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+)
+
+// Reviewer strategies of cherry pick PRs.
+const (
+	reviewerStrategyApprovers = "approvers"
+	reviewerStrategyNone      = "none"
+)
+
+// Ways of handling PRs merged into a release branch only.
+const (
+	forwardPortLabel = "label"
+	forwardPortPR    = "pr"
+	forwardPortNone  = "none"
+)
+
+const defaultCherryPickBranchTemplate = "release-{{.Version}}"
+
+var (
+	defaultCherryPickTriggerLabels = []string{"CherryPick/Approved"}
+	defaultCherryPickResultLabels  = []string{"AutomatedCherryPick", "Changelog/Not Needed", "Docs/Not Needed"}
+)
+
+// cherryPickPolicy returns the cherry pick policy of the repository, with
+// defaults filled in for the unset fields.
+func (s *Server) cherryPickPolicy(repoOwner, repoName string) *CherryPickPolicy {
+	policy := CherryPickPolicy{}
+	if repo, ok := GetRepository(s.Config.Repositories, repoOwner, repoName); ok && repo.CherryPick != nil {
+		policy = *repo.CherryPick
+	}
+
+	if len(policy.TriggerLabels) == 0 {
+		policy.TriggerLabels = defaultCherryPickTriggerLabels
+	}
+	if policy.DoneLabel == "" {
+		policy.DoneLabel = "CherryPick/Done"
+	}
+	if len(policy.ResultLabels) == 0 {
+		policy.ResultLabels = defaultCherryPickResultLabels
+	}
+	if policy.BranchTemplate == "" {
+		policy.BranchTemplate = defaultCherryPickBranchTemplate
+	}
+	if policy.CloudMilestone == "" {
+		policy.CloudMilestone = milestoneCloud
+	}
+	if policy.ReviewerStrategy == "" {
+		policy.ReviewerStrategy = reviewerStrategyApprovers
+	}
+	if policy.ReleaseBranches == "" {
+		policy.ReleaseBranches = "release-*"
+	}
+	if policy.ForwardPort == "" {
+		policy.ForwardPort = forwardPortLabel
+	}
+	if policy.ForwardPortLabel == "" {
+		policy.ForwardPortLabel = "ForwardPort/Needed"
+	}
+	return &policy
+}
+
+// isCherryPickTrigger reports whether the label requests cherry picks in the
+// repository.
+func (s *Server) isCherryPickTrigger(repoOwner, repoName, label string) bool {
+	return contains(s.cherryPickPolicy(repoOwner, repoName).TriggerLabels, label)
+}
+
+// hasCherryPickTrigger reports whether any of the labels requests cherry
+// picks in the repository.
+func (s *Server) hasCherryPickTrigger(repoOwner, repoName string, labels []string) bool {
+	for _, label := range labels {
+		if s.isCherryPickTrigger(repoOwner, repoName, label) {
+			return true
+		}
+	}
+	return false
+}
+
+// cherryPickTarget returns the branch the PRs of a milestone are cherry
+// picked onto, e.g. release-7.1 for v7.1.0. PRs of the cloud milestone go
+// onto the cloud branch of the repository.
+func (s *Server) cherryPickTarget(repoOwner, repoName, milestoneTitle string) (string, error) {
+	policy := s.cherryPickPolicy(repoOwner, repoName)
+	title := strings.TrimSpace(milestoneTitle)
+	if title == policy.CloudMilestone {
+		for _, repo := range s.Config.CloudRepositories {
+			if repo.Name == repoName && repo.MainBranch != "" {
+				return repo.MainBranch, nil
+			}
+		}
+		return milestoneCloud, nil
+	}
+
+	tmpl, err := template.New("branch").Option("missingkey=error").Parse(policy.BranchTemplate)
+	if err != nil {
+		return "", fmt.Errorf("invalid cherry pick branch template %q: %w", policy.BranchTemplate, err)
+	}
+	var b strings.Builder
+	err = tmpl.Execute(&b, struct{ Title, Version string }{
+		Title:   title,
+		Version: strings.TrimSuffix(strings.Trim(title, "v"), ".0"),
+	})
+	if err != nil {
+		return "", fmt.Errorf("could not name the branch of milestone %s: %w", title, err)
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,177 @@
+// Copyright (c) 2017-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/go-github/v39/github"
+	"github.com/mattermost/mattermost-mattermod/model"
+	"github.com/mattermost/mattermost-server/v6/shared/mlog"
+)
+
+const (
+	// cherryPickPreviewContext is the status reporting the outcome of the
+	// preview. It is always successful: conflicts with a release branch are
+	// resolved after the merge, so they must not block it.
+	cherryPickPreviewContext = "cherry-pick/preview"
+	// cherryPickPreviewMarker identifies the comment listing the files which
+	// conflict with the release branch.
+	cherryPickPreviewMarker = "<!-- mattermod:cherry-pick-preview -->"
+)
+
+// cherryPickPreviewAutomation tries out the cherry pick of PRs with a trigger
+// label before they are merged, so that conflicts with the release branch
+// show up while the PR can still be changed.
+type cherryPickPreviewAutomation struct {
+	baseAutomation
+	s *Server
+}
+
+func (a *cherryPickPreviewAutomation) Name() string {
+	return "cherry-pick-preview"
+}
+
+func (a *cherryPickPreviewAutomation) OnPullRequest(ctx context.Context, event *pullRequestEvent, pr *model.PullRequest) error {
+	switch event.Action {
+	case prEventLabeled:
+		if !a.s.isCherryPickTrigger(pr.RepoOwner, pr.RepoName, event.Label.GetName()) {
+			return nil
+		}
+	case prEventSynchronize:
+		if !a.s.hasCherryPickTrigger(pr.RepoOwner, pr.RepoName, pr.Labels) {
+			return nil
+		}
+	default:
+		return nil
+	}
+	return a.s.checkCherryPickPreview(ctx, pr, event.PullRequest.GetBase().GetRef())
+}
+
+// checkCherryPickPreview queues the preview of the open PR on the cherry pick
+// workers, if its milestone has a release branch to cherry pick onto.
+func (s *Server) checkCherryPickPreview(ctx context.Context, pr *model.PullRequest, base string) error {
+	target, err := s.cherryPickPreviewTarget(pr, base)
+	if err != nil || target == "" {
+		return err
+	}
+	if err = s.setCherryPickPreviewStatus(ctx, pr, fmt.Sprintf("Waiting to check the cherry pick onto %s", target)); err != nil {
+		return err
+	}
+	return s.queueCherryPickJob(pr, model.CherryPickJobKindPreview, base)
+}
+
+// cherryPickPreviewTarget returns the release branch the PR is previewed
+// onto, or an empty string if it isn't previewed.
+func (s *Server) cherryPickPreviewTarget(pr *model.PullRequest, base string) (string, error) {
+	if pr.State != model.StateOpen || pr.GetMilestoneTitle() == "" || s.Config.RepoFolder == "" {
+		return "", nil
+	}
+	target, err := s.cherryPickTarget(pr.RepoOwner, pr.RepoName, pr.GetMilestoneTitle())
+	if err != nil || target == base {
+		return "", err
+	}
+	return target, nil
+}
+
+// runCherryPickPreview cherry picks the open PR onto the release branch of
+// its milestone in a scratch worktree, and reports the outcome in the
+// cherry-pick/preview status. Conflicting files are listed in a comment,
+// which is updated once the conflicts are gone. The PR may have changed since
+// the preview was queued, so the target is looked up again.
+func (s *Server) runCherryPickPreview(ctx context.Context, pr *model.PullRequest, base string) error {
+	target, err := s.cherryPickPreviewTarget(pr, base)
+	if err != nil || target == "" {
+		return err
+	}
+
+	if err = s.setCherryPickPreviewStatus(ctx, pr, fmt.Sprintf("Checking the cherry pick onto %s", target)); err != nil {
+		return err
+	}
+
+	files, err := s.previewCherryPickConflicts(ctx, pr, base, target)
+	if errors.Is(err, errNoCherryPickTarget) {
+		return s.setCherryPickPreviewStatus(ctx, pr, fmt.Sprintf("No %s branch to cherry pick onto yet", target))
+	}
+	if err != nil {
+		if statusErr := s.setCherryPickPreviewStatus(ctx, pr, fmt.Sprintf("Could not check the cherry pick onto %s", target)); statusErr != nil {
+			mlog.Warn("Error while setting the cherry pick preview status", mlog.Int("pr", pr.Number), mlog.Err(statusErr))
+		}
+		return err
+	}
+
+	err = s.publishStickyComment(ctx, pr, cherryPickPreviewMarker, func() (string, bool, error) {
+		return renderCherryPickPreview(target, files), len(files) > 0, nil
+	})
+	if err != nil {
+		mlog.Warn("Error while updating the cherry pick preview comment",
+			mlog.String("repo", pr.RepoOwner+"/"+pr.RepoName),
+			mlog.Int("pr", pr.Number),
+			mlog.Err(err))
+	}
+
+	switch len(files) {
+	case 0:
+	case 1:
+		return s.setCherryPickPreviewStatus(ctx, pr, fmt.Sprintf("Conflicts with %s in %s", target, files[0]))
+	default:
+		return s.setCherryPickPreviewStatus(ctx, pr, fmt.Sprintf("Conflicts with %s in %d files", target, len(files)))
+	}
+	return s.setCherryPickPreviewStatus(ctx, pr, fmt.Sprintf("Cherry picks cleanly onto %s", target))
+}
+
+// errNoCherryPickTarget is returned when the release branch of a preview
+// hasn't been created yet.
+var errNoCherryPickTarget = errors.New("the cherry pick target doesn't exist")
+
+// previewCherryPickConflicts returns the files which conflict when cherry
+// picking the head of the PR onto target.
+func (s *Server) previewCherryPickConflicts(ctx context.Context, pr *model.PullRequest, base, target string) ([]string, error) {
+	head := pullHeadRef(pr.Number)
+	// The worktree starts on the base branch, which exists as long as the PR
+	// is open, unlike the release branch.
+	mirror, worktree, err := s.addCherryPickWorktree(ctx, pr, base, "+"+head+":"+head)
+	if err != nil {
+		return nil, err
+	}
+	defer s.removeCherryPickWorktree(pr, mirror, worktree)
+
+	if !hasUpstreamBranch(ctx, worktree, target) {
+		return nil, errNoCherryPickTarget
+	}
+
+	err = previewCherryPick(ctx, worktree, target, base, head)
+	var conflictErr *cherryPickConflictError
+	if errors.As(err, &conflictErr) {
+		return conflictErr.files, nil
+	}
+	return nil, err
+}
+
+func (s *Server) setCherryPickPreviewStatus(ctx context.Context, pr *model.PullRequest, description string) error {
+	return s.createRepoStatus(ctx, pr, &github.RepoStatus{
+		State:       github.String(stateSuccess),
+		Description: github.String(description),
+		Context:     github.String(cherryPickPreviewContext),
+	})
+}
+
+func renderCherryPickPreview(target string, files []string) string {
+	var b strings.Builder
+	b.WriteString(cherryPickPreviewMarker + "\n")
+	b.WriteString("#### Cherry pick preview\n\n")
+	if len(files) == 0 {
+		fmt.Fprintf(&b, "This PR cherry picks cleanly onto `%s` now.\n", target)
+		return b.String()
+	}
+
+	fmt.Fprintf(&b, "This PR conflicts with `%s`, so it will have to be cherry picked manually once merged. These files conflict:\n\n", target)
+	for _, file := range files {
+		fmt.Fprintf(&b, "- `%s`\n", file)
+	}
+	return b.String()
+}
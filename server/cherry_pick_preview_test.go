@@ -0,0 +1,173 @@
+// Copyright (c) 2017-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package server
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/go-github/v39/github"
+	"github.com/mattermost/mattermost-mattermod/model"
+	"github.com/mattermost/mattermost-mattermod/server/mocks"
+	stmock "github.com/mattermost/mattermost-mattermod/store/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCherryPickPreview(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctxInterface := reflect.TypeOf((*context.Context)(nil)).Elem()
+
+	repoFolder := t.TempDir()
+	upstream, sha := setupCherryPickRepos(t, repoFolder, "mattermost", "repo-name")
+	// The fix on master is the head of the PR, opened against release-7.1.
+	// It applies to a copy of release-7.1 but conflicts with release-7.2.
+	out, err := runGit(context.Background(), upstream, "branch", "release-7.3", "release-7.1")
+	require.NoError(t, err, out)
+
+	is := mocks.NewMockIssuesService(ctrl)
+	rs := mocks.NewMockRepositoriesService(ctrl)
+	jobStore := stmock.NewMockCherryPickJobStore(ctrl)
+	ss := stmock.NewMockStore(ctrl)
+	ss.EXPECT().CherryPickJob().Return(jobStore).AnyTimes()
+	s := &Server{
+		Config:             &Config{RepoFolder: repoFolder, Username: "mattermod"},
+		Store:              ss,
+		GithubClient:       &GithubClient{Issues: is, Repositories: rs},
+		cherryPickWakeChan: make(chan struct{}, 1),
+	}
+	a := &cherryPickPreviewAutomation{s: s}
+
+	newEvent := func(action, label string) *pullRequestEvent {
+		return &pullRequestEvent{
+			Action: action,
+			Label:  &github.Label{Name: github.String(label)},
+			PullRequest: &github.PullRequest{
+				Base: &github.PullRequestBranch{Ref: github.String("release-7.1")},
+			},
+		}
+	}
+	newPR := func(milestone string) *model.PullRequest {
+		return &model.PullRequest{
+			RepoOwner:      "mattermost",
+			RepoName:       "repo-name",
+			Number:         123,
+			Sha:            sha,
+			State:          model.StateOpen,
+			MilestoneTitle: github.String(milestone),
+			Labels:         []string{"CherryPick/Approved"},
+		}
+	}
+	expectStatuses := func(statuses ...*github.RepoStatus) {
+		var calls []*gomock.Call
+		for _, status := range statuses {
+			status.Context = github.String(cherryPickPreviewContext)
+			calls = append(calls, rs.EXPECT().CreateStatus(gomock.AssignableToTypeOf(ctxInterface), "mattermost", "repo-name", sha, status).Return(nil, nil, nil))
+		}
+		gomock.InOrder(calls...)
+	}
+	okResponse := &github.Response{Response: &http.Response{StatusCode: http.StatusOK}}
+
+	t.Run("Other labels and actions are ignored", func(t *testing.T) {
+		require.NoError(t, a.OnPullRequest(context.Background(), newEvent(prEventLabeled, "Do Not Merge"), newPR("v7.2")))
+		require.NoError(t, a.OnPullRequest(context.Background(), newEvent(prEventOpened, "CherryPick/Approved"), newPR("v7.2")))
+
+		pr := newPR("v7.2")
+		pr.Labels = nil
+		require.NoError(t, a.OnPullRequest(context.Background(), newEvent(prEventSynchronize, ""), pr))
+	})
+
+	t.Run("PRs against the release branch are not previewed", func(t *testing.T) {
+		require.NoError(t, a.OnPullRequest(context.Background(), newEvent(prEventLabeled, "CherryPick/Approved"), newPR("v7.1")))
+	})
+
+	t.Run("Labeled PRs queue a preview", func(t *testing.T) {
+		expectStatuses(&github.RepoStatus{State: github.String(stateSuccess), Description: github.String("Waiting to check the cherry pick onto release-7.2")})
+		jobStore.EXPECT().ListByPR("mattermost", "repo-name", 123).Return([]*model.CherryPickJob{
+			{Kind: model.CherryPickJobKindCherryPick, Branch: "release-7.1", State: model.CherryPickJobPending},
+		}, nil)
+		jobStore.EXPECT().Create(&model.CherryPickJob{
+			Kind:      model.CherryPickJobKindPreview,
+			RepoOwner: "mattermost",
+			RepoName:  "repo-name",
+			Number:    123,
+			Branch:    "release-7.1",
+			State:     model.CherryPickJobPending,
+		}).Return(nil)
+
+		require.NoError(t, a.OnPullRequest(context.Background(), newEvent(prEventLabeled, "CherryPick/Approved"), newPR("v7.2")))
+		require.Len(t, s.cherryPickWakeChan, 1)
+		<-s.cherryPickWakeChan
+	})
+
+	t.Run("Pending previews are not queued twice", func(t *testing.T) {
+		expectStatuses(&github.RepoStatus{State: github.String(stateSuccess), Description: github.String("Waiting to check the cherry pick onto release-7.2")})
+		jobStore.EXPECT().ListByPR("mattermost", "repo-name", 123).Return([]*model.CherryPickJob{
+			{Kind: model.CherryPickJobKindPreview, Branch: "release-7.1", State: model.CherryPickJobPending},
+		}, nil)
+
+		require.NoError(t, a.OnPullRequest(context.Background(), newEvent(prEventSynchronize, ""), newPR("v7.2")))
+		require.Empty(t, s.cherryPickWakeChan)
+	})
+
+	t.Run("Conflicts are reported and commented", func(t *testing.T) {
+		expectStatuses(
+			&github.RepoStatus{State: github.String(stateSuccess), Description: github.String("Checking the cherry pick onto release-7.2")},
+			&github.RepoStatus{State: github.String(stateSuccess), Description: github.String("Conflicts with release-7.2 in app.go")},
+		)
+		is.EXPECT().ListComments(gomock.AssignableToTypeOf(ctxInterface), "mattermost", "repo-name", 123, gomock.Any()).Return(nil, okResponse, nil)
+		is.EXPECT().CreateComment(gomock.AssignableToTypeOf(ctxInterface), "mattermost", "repo-name", 123, gomock.AssignableToTypeOf(&github.IssueComment{})).
+			DoAndReturn(func(_ context.Context, _, _ string, _ int, comment *github.IssueComment) (*github.IssueComment, *github.Response, error) {
+				assert.Contains(t, comment.GetBody(), cherryPickPreviewMarker)
+				assert.Contains(t, comment.GetBody(), "- `app.go`\n")
+				return &github.IssueComment{ID: github.Int64(42)}, nil, nil
+			})
+
+		require.NoError(t, s.runCherryPickPreview(context.Background(), newPR("v7.2"), "release-7.1"))
+	})
+
+	t.Run("Clean previews update the existing comment", func(t *testing.T) {
+		expectStatuses(
+			&github.RepoStatus{State: github.String(stateSuccess), Description: github.String("Checking the cherry pick onto release-7.3")},
+			&github.RepoStatus{State: github.String(stateSuccess), Description: github.String("Cherry picks cleanly onto release-7.3")},
+		)
+		is.EXPECT().EditComment(gomock.AssignableToTypeOf(ctxInterface), "mattermost", "repo-name", int64(42), &github.IssueComment{
+			Body: github.String(renderCherryPickPreview("release-7.3", nil)),
+		}).Return(nil, nil, nil)
+
+		require.NoError(t, s.runCherryPickPreview(context.Background(), newPR("v7.3"), "release-7.1"))
+	})
+
+	t.Run("Clean previews don't create a comment", func(t *testing.T) {
+		s.stickyComments = nil
+		expectStatuses(
+			&github.RepoStatus{State: github.String(stateSuccess), Description: github.String("Checking the cherry pick onto release-7.3")},
+			&github.RepoStatus{State: github.String(stateSuccess), Description: github.String("Cherry picks cleanly onto release-7.3")},
+		)
+		is.EXPECT().ListComments(gomock.AssignableToTypeOf(ctxInterface), "mattermost", "repo-name", 123, gomock.Any()).Return(nil, okResponse, nil)
+
+		require.NoError(t, s.runCherryPickPreview(context.Background(), newPR("v7.3"), "release-7.1"))
+	})
+
+	t.Run("Missing release branches pass", func(t *testing.T) {
+		expectStatuses(
+			&github.RepoStatus{State: github.String(stateSuccess), Description: github.String("Checking the cherry pick onto release-7.4")},
+			&github.RepoStatus{State: github.String(stateSuccess), Description: github.String("No release-7.4 branch to cherry pick onto yet")},
+		)
+
+		require.NoError(t, s.runCherryPickPreview(context.Background(), newPR("v7.4"), "release-7.1"))
+	})
+}
+
+func TestRenderCherryPickPreview(t *testing.T) {
+	assert.Equal(t, cherryPickPreviewMarker+"\n#### Cherry pick preview\n\n"+
+		"This PR conflicts with `release-7.2`, so it will have to be cherry picked manually once merged. These files conflict:\n\n"+
+		"- `app.go`\n- `user.go`\n",
+		renderCherryPickPreview("release-7.2", []string{"app.go", "user.go"}))
+}
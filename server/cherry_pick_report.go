@@ -0,0 +1,115 @@
+// Copyright (c) 2017-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package server
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/mattermost/mattermost-mattermod/model"
+	"github.com/mattermost/mattermost-server/v6/shared/mlog"
+)
+
+// cherryPickReportMarker identifies the result comment of the cherry picks of
+// a PR, so that it can be found again and edited.
+const cherryPickReportMarker = "<!-- mattermod:cherry-pick-report -->"
+
+// updateCherryPickReport renders the cherry pick jobs of the PR into its
+// result comment. Failing to update the comment doesn't fail the jobs.
+func (s *Server) updateCherryPickReport(ctx context.Context, pr *model.PullRequest) {
+	if err := s.publishCherryPickReport(ctx, pr); err != nil {
+		mlog.Warn("Error while updating the cherry pick comment",
+			mlog.String("repo", pr.RepoOwner+"/"+pr.RepoName),
+			mlog.Int("pr", pr.Number),
+			mlog.Err(err))
+	}
+}
+
+// publishCherryPickReport edits the result comment, or creates it if the PR
+// doesn't have one yet.
+func (s *Server) publishCherryPickReport(ctx context.Context, pr *model.PullRequest) error {
+	return s.publishStickyComment(ctx, pr, cherryPickReportMarker, func() (string, bool, error) {
+		jobs, err := s.Store.CherryPickJob().ListByPR(pr.RepoOwner, pr.RepoName, pr.Number)
+		if err != nil {
+			return "", false, err
+		}
+		backports, err := s.Store.Backport().ListByPR(pr.RepoOwner, pr.RepoName, pr.Number)
+		if err != nil {
+			return "", false, err
+		}
+		return renderCherryPickReport(jobs, backports), true, nil
+	})
+}
+
+// renderCherryPickReport lists the latest job of every branch, in the order
+// the branches were first requested. Cherry pick PRs which were merged or
+// closed since are shown as such.
+func renderCherryPickReport(jobs []*model.CherryPickJob, backports []*model.Backport) string {
+	var branches []string
+	latest := map[string]*model.CherryPickJob{}
+	for _, job := range jobs {
+		if !job.IsCherryPick() {
+			continue
+		}
+		if _, ok := latest[job.Branch]; !ok {
+			branches = append(branches, job.Branch)
+		}
+		latest[job.Branch] = job
+	}
+
+	backportStates := map[int]string{}
+	for _, backport := range backports {
+		if backport.BackportNumber != 0 {
+			backportStates[backport.BackportNumber] = backport.State
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString(cherryPickReportMarker + "\n")
+	b.WriteString("#### Cherry picks\n\n")
+	b.WriteString("| Branch | State |\n")
+	b.WriteString("| --- | --- |\n")
+	for _, branch := range branches {
+		fmt.Fprintf(&b, "| `%s` | %s |\n", escapeTableCell(branch), describeCherryPickJob(latest[branch], backportStates))
+	}
+
+	for _, branch := range branches {
+		job := latest[branch]
+		if job.State != model.CherryPickJobFailed {
+			continue
+		}
+		if job.Conflicts != "" {
+			fmt.Fprintf(&b, "\n<details><summary>Conflicts on %s</summary>\n\n", branch)
+			for _, file := range strings.Split(job.Conflicts, "\n") {
+				fmt.Fprintf(&b, "- `%s`\n", file)
+			}
+			b.WriteString("</details>\n")
+		} else if job.Output != "" {
+			fmt.Fprintf(&b, "\n<details><summary>Output for %s</summary>\n\n```\n%s\n```\n</details>\n", branch, strings.TrimSpace(job.Output))
+		}
+	}
+	return b.String()
+}
+
+func describeCherryPickJob(job *model.CherryPickJob, backportStates map[int]string) string {
+	switch job.State {
+	case model.CherryPickJobPending:
+		return "Queued"
+	case model.CherryPickJobRunning:
+		return "Running"
+	case model.CherryPickJobSucceeded:
+		switch backportStates[job.NewPRNumber] {
+		case model.BackportMerged:
+			return fmt.Sprintf("Merged: #%d", job.NewPRNumber)
+		case model.BackportClosed:
+			return fmt.Sprintf("Closed without merging: #%d", job.NewPRNumber)
+		}
+		return fmt.Sprintf("Done: #%d", job.NewPRNumber)
+	}
+	if job.Conflicts != "" {
+		return "Conflict, please cherry pick manually"
+	}
+	return "Failed, please cherry pick manually"
+}
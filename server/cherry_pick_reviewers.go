@@ -0,0 +1,137 @@
+// Copyright (c) 2017-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package server
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"strings"
+
+	"github.com/google/go-github/v39/github"
+	"github.com/mattermost/mattermost-mattermod/model"
+	"github.com/mattermost/mattermost-server/v6/shared/mlog"
+)
+
+// cherryPickReviewers are the people asked to take care of a cherry pick PR.
+// At most one of reviewer and team is set.
+type cherryPickReviewers struct {
+	reviewer string
+	team     string
+	assignee string
+	// reasons explain the choice in the body of the cherry pick PR.
+	reasons []string
+}
+
+// chooseCherryPickReviewers picks an approver of the original PR as reviewer
+// of its cherry pick, never the author, falling back to the release team of
+// the repository. The author is assigned if they are an org member, else
+// another approver, so that reviewer and assignee always differ.
+func (s *Server) chooseCherryPickReviewers(ctx context.Context, pr *model.PullRequest, policy *CherryPickPolicy) *cherryPickReviewers {
+	choice := &cherryPickReviewers{}
+	if policy.ReviewerStrategy == reviewerStrategyNone {
+		return choice
+	}
+
+	reviews, err := s.getReviews(ctx, pr.RepoOwner, pr.RepoName, pr.Number)
+	if err != nil {
+		mlog.Warn("Error getting the reviews of the original PR",
+			mlog.String("repo", pr.RepoOwner+"/"+pr.RepoName),
+			mlog.Int("pr", pr.Number),
+			mlog.Err(err))
+	}
+	candidates := approvers(reviews, pr.Username, s.Config.Username)
+
+	switch {
+	case len(candidates) > 0:
+		i := rand.Intn(len(candidates)) // nolint:gosec
+		choice.reviewer = candidates[i]
+		candidates = append(candidates[:i:i], candidates[i+1:]...)
+		choice.reasons = append(choice.reasons, fmt.Sprintf("Review requested from @%s, who approved #%d.", choice.reviewer, pr.Number))
+	case policy.ReleaseTeam != "":
+		choice.team = policy.ReleaseTeam
+		choice.reasons = append(choice.reasons, fmt.Sprintf("Review requested from @%s/%s, since #%d wasn't approved by anyone other than its author.", s.Config.Org, choice.team, pr.Number))
+	default:
+		choice.reasons = append(choice.reasons, fmt.Sprintf("No review requested, since #%d wasn't approved by anyone other than its author and there is no release team.", pr.Number))
+	}
+
+	switch {
+	case s.IsOrgMember(pr.Username):
+		choice.assignee = pr.Username
+		choice.reasons = append(choice.reasons, fmt.Sprintf("Assigned to @%s, the author of #%d.", choice.assignee, pr.Number))
+	case len(candidates) > 0:
+		choice.assignee = candidates[0]
+		choice.reasons = append(choice.reasons, fmt.Sprintf("Assigned to @%s, who also approved #%d, since its author isn't an org member.", choice.assignee, pr.Number))
+	}
+	return choice
+}
+
+// approvers returns the users whose latest review of a PR approves it, in the
+// order they first reviewed it. Comments don't withdraw an approval, but
+// requesting changes and dismissals do. The excluded users are skipped.
+func approvers(reviews []*github.PullRequestReview, excluded ...string) []string {
+	var result []string
+	users, latest := latestReviewStates(reviews)
+	for _, user := range users {
+		if latest[user] == "APPROVED" && !containsFold(excluded, user) {
+			result = append(result, user)
+		}
+	}
+	return result
+}
+
+// changesRequesters returns the users whose latest review of a PR requests
+// changes, in the order they first reviewed it.
+func changesRequesters(reviews []*github.PullRequestReview) []string {
+	var result []string
+	users, latest := latestReviewStates(reviews)
+	for _, user := range users {
+		if latest[user] == "CHANGES_REQUESTED" {
+			result = append(result, user)
+		}
+	}
+	return result
+}
+
+// latestReviewStates returns the users who reviewed a PR in the order they
+// first did, along with the state of their latest review which isn't a
+// comment.
+func latestReviewStates(reviews []*github.PullRequestReview) (users []string, latest map[string]string) {
+	latest = map[string]string{}
+	for _, review := range reviews {
+		login := review.GetUser().GetLogin()
+		state := review.GetState()
+		if login == "" || state == "COMMENTED" || state == "PENDING" {
+			continue
+		}
+		if _, ok := latest[login]; !ok {
+			users = append(users, login)
+		}
+		latest[login] = state
+	}
+	return users, latest
+}
+
+func containsFold(list []string, item string) bool {
+	for _, element := range list {
+		if strings.EqualFold(element, item) {
+			return true
+		}
+	}
+	return false
+}
+
+// requestCherryPickReviews asks the chosen reviewers to review the cherry
+// pick PR and assigns it. Failures are logged, the PR exists already.
+func (s *Server) requestCherryPickReviews(ctx context.Context, newPRNumber int, pr *model.PullRequest, choice *cherryPickReviewers) {
+	switch {
+	case choice.reviewer != "":
+		s.addReviewers(ctx, newPRNumber, pr, github.ReviewersRequest{Reviewers: []string{choice.reviewer}})
+	case choice.team != "":
+		s.addReviewers(ctx, newPRNumber, pr, github.ReviewersRequest{TeamReviewers: []string{choice.team}})
+	}
+	if choice.assignee != "" {
+		s.addAssignee(ctx, newPRNumber, pr, []string{choice.assignee})
+	}
+}
@@ -0,0 +1,126 @@
+// Copyright (c) 2017-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package server
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/go-github/v39/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-mattermod/model"
+	"github.com/mattermost/mattermost-mattermod/server/mocks"
+)
+
+func review(login, state string) *github.PullRequestReview {
+	return &github.PullRequestReview{User: &github.User{Login: github.String(login)}, State: github.String(state)}
+}
+
+func TestApprovers(t *testing.T) {
+	reviews := []*github.PullRequestReview{
+		review("changed-mind", "APPROVED"),
+		review("commenter", "COMMENTED"),
+		review("approver", "CHANGES_REQUESTED"),
+		review("author", "APPROVED"),
+		review("mattermod", "APPROVED"),
+		review("approver", "APPROVED"),
+		review("approver", "COMMENTED"),
+		review("changed-mind", "CHANGES_REQUESTED"),
+		review("dismissed", "APPROVED"),
+		review("dismissed", "DISMISSED"),
+		review("second", "APPROVED"),
+	}
+
+	assert.Equal(t, []string{"approver", "second"}, approvers(reviews, "Author", "mattermod"))
+	assert.Empty(t, approvers(nil))
+}
+
+func TestChooseCherryPickReviewers(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctxInterface := reflect.TypeOf((*context.Context)(nil)).Elem()
+	prs := mocks.NewMockPullRequestsService(ctrl)
+	s := &Server{
+		Config: &Config{
+			Org:      "mattermost",
+			Username: "mattermod",
+		},
+		OrgMembers:   []string{"member"},
+		GithubClient: &GithubClient{PullRequests: prs},
+	}
+	ok := &github.Response{Response: &http.Response{StatusCode: http.StatusOK}}
+
+	choose := func(author string, policy *CherryPickPolicy, reviews ...*github.PullRequestReview) *cherryPickReviewers {
+		pr := &model.PullRequest{RepoOwner: "mattermost", RepoName: "server", Number: 12, Username: author}
+		prs.EXPECT().ListReviews(gomock.AssignableToTypeOf(ctxInterface), "mattermost", "server", 12, gomock.Any()).Return(reviews, ok, nil)
+		return s.chooseCherryPickReviewers(context.Background(), pr, policy)
+	}
+
+	t.Run("An approver reviews, the author is assigned", func(t *testing.T) {
+		choice := choose("member", &CherryPickPolicy{}, review("member", "APPROVED"), review("approver", "APPROVED"))
+		assert.Equal(t, &cherryPickReviewers{
+			reviewer: "approver",
+			assignee: "member",
+			reasons: []string{
+				"Review requested from @approver, who approved #12.",
+				"Assigned to @member, the author of #12.",
+			},
+		}, choice)
+	})
+
+	t.Run("Another approver is assigned if the author isn't an org member", func(t *testing.T) {
+		choice := choose("contributor", &CherryPickPolicy{}, review("first", "APPROVED"), review("second", "APPROVED"))
+		require.NotEmpty(t, choice.reviewer)
+		require.NotEmpty(t, choice.assignee)
+		assert.NotEqual(t, choice.reviewer, choice.assignee)
+		assert.ElementsMatch(t, []string{"first", "second"}, []string{choice.reviewer, choice.assignee})
+		assert.Contains(t, choice.reasons, "Assigned to @"+choice.assignee+", who also approved #12, since its author isn't an org member.")
+	})
+
+	t.Run("Nobody is assigned if the only approver reviews", func(t *testing.T) {
+		choice := choose("contributor", &CherryPickPolicy{}, review("approver", "APPROVED"))
+		assert.Equal(t, "approver", choice.reviewer)
+		assert.Empty(t, choice.assignee)
+	})
+
+	t.Run("The release team reviews without approvers", func(t *testing.T) {
+		choice := choose("member", &CherryPickPolicy{ReleaseTeam: "release"}, review("member", "APPROVED"), review("commenter", "COMMENTED"))
+		assert.Equal(t, &cherryPickReviewers{
+			team:     "release",
+			assignee: "member",
+			reasons: []string{
+				"Review requested from @mattermost/release, since #12 wasn't approved by anyone other than its author.",
+				"Assigned to @member, the author of #12.",
+			},
+		}, choice)
+	})
+
+	t.Run("Nobody reviews without approvers or release team", func(t *testing.T) {
+		choice := choose("contributor", &CherryPickPolicy{})
+		assert.Equal(t, &cherryPickReviewers{
+			reasons: []string{"No review requested, since #12 wasn't approved by anyone other than its author and there is no release team."},
+		}, choice)
+	})
+
+	t.Run("Failing to list the reviews falls back to the release team", func(t *testing.T) {
+		pr := &model.PullRequest{RepoOwner: "mattermost", RepoName: "server", Number: 12, Username: "member"}
+		prs.EXPECT().ListReviews(gomock.AssignableToTypeOf(ctxInterface), "mattermost", "server", 12, gomock.Any()).Return(nil, nil, errors.New("boom"))
+		choice := s.chooseCherryPickReviewers(context.Background(), pr, &CherryPickPolicy{ReleaseTeam: "release"})
+		assert.Equal(t, "release", choice.team)
+		assert.Equal(t, "member", choice.assignee)
+	})
+
+	t.Run("The none strategy chooses nobody", func(t *testing.T) {
+		pr := &model.PullRequest{RepoOwner: "mattermost", RepoName: "server", Number: 12, Username: "member"}
+		choice := s.chooseCherryPickReviewers(context.Background(), pr, &CherryPickPolicy{ReviewerStrategy: reviewerStrategyNone})
+		assert.Equal(t, &cherryPickReviewers{}, choice)
+	})
+}
@@ -16,6 +16,28 @@ import (
 	"github.com/mattermost/mattermost-server/v6/shared/mlog"
 )
 
+const claAutomationName = "cla"
+
+// claAutomation sets the CLA status of PRs when they change. The /check-cla
+// command is only available where it is enabled.
+type claAutomation struct {
+	baseAutomation
+	s *Server
+}
+
+func (a *claAutomation) Name() string {
+	return claAutomationName
+}
+
+func (a *claAutomation) OnPullRequest(ctx context.Context, event *pullRequestEvent, pr *model.PullRequest) error {
+	switch event.Action {
+	case prEventOpened, prEventReOpened, prEventSynchronize:
+		_, err := a.s.handleCheckCLA(ctx, pr)
+		return err
+	}
+	return nil
+}
+
 // handleCheckCLA checks if the author of a pull request has signed the CLA and sets a status accordingly.
 // Returns true, if the user hasn't signed yet.
 func (s *Server) handleCheckCLA(ctx context.Context, pr *model.PullRequest) (bool, error) {
@@ -71,6 +93,21 @@ func (s *Server) handleCheckCLA(ctx context.Context, pr *model.PullRequest) (boo
 	return false, s.createRepoStatus(ctx, pr, status)
 }
 
+// needsCLA reports whether the user still has to sign the CLA. It doesn't
+// touch the CLA status of any PR.
+func (s *Server) needsCLA(ctx context.Context, username string) bool {
+	if s.IsBotUserFromCLAExclusionsList(username) {
+		return false
+	}
+
+	body, err := s.getCSV(ctx)
+	if err != nil {
+		return false
+	}
+
+	return !isNameInCLAList(strings.Split(string(body), "\n"), username)
+}
+
 func (s *Server) getCSV(ctx context.Context) ([]byte, error) {
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.Config.SignedCLAURL, http.NoBody)
 	if err != nil {
@@ -0,0 +1,357 @@
+// Copyright (c) 2017-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/mattermost/mattermost-mattermod/model"
+	"github.com/mattermost/mattermost-server/v6/shared/mlog"
+)
+
+// commandNameRegex matches the slash command at the start of a line. The name
+// has to be followed by whitespace or the end of the line, so that paths like
+// /usr/bin aren't taken for commands.
+var commandNameRegex = regexp.MustCompile(`^/([a-zA-Z][a-zA-Z0-9-]*)(?:\s|$)`)
+
+// errNothingToDo is returned by command handlers which had nothing to do,
+// e.g. for a cherry pick of an unmerged PR. These commands don't fail, but
+// don't get the success reaction either.
+var errNothingToDo = errors.New("nothing to do")
+
+// errCommandDenied is returned by command handlers which refused the
+// commenter after explaining why, like permission denials of runCommand.
+var errCommandDenied = errors.New("command denied")
+
+// command is a slash command parsed from a comment, e.g.
+// `/cherry-pick release-6.0 --dry-run`.
+type command struct {
+	// Name is the name of the command without the slash.
+	Name string
+	// Args are the positional arguments, with quotes removed.
+	Args []string
+	// Flags maps the names of --flag and --flag=value arguments to their
+	// values. Flags without a value map to an empty string.
+	Flags map[string]string
+}
+
+// HasFlag reports whether the command was given the flag.
+func (c *command) HasFlag(name string) bool {
+	_, ok := c.Flags[name]
+	return ok
+}
+
+// parseCommands returns the slash commands of a comment in the order they
+// appear. Commands must start a line. Lines in code blocks and quotes are
+// ignored, so commands can be mentioned without running them.
+func parseCommands(body string) []*command {
+	var commands []*command
+	var fence string
+
+	for _, line := range strings.Split(strings.ReplaceAll(body, "\r\n", "\n"), "\n") {
+		// Four spaces or a tab start an indented code block.
+		if strings.HasPrefix(line, "    ") || strings.HasPrefix(line, "\t") {
+			continue
+		}
+		trimmed := strings.TrimSpace(line)
+
+		if fence != "" {
+			if strings.HasPrefix(trimmed, fence) {
+				fence = ""
+			}
+			continue
+		}
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			fence = trimmed[:3]
+			continue
+		}
+
+		if strings.HasPrefix(trimmed, ">") {
+			continue
+		}
+
+		match := commandNameRegex.FindStringSubmatch(trimmed)
+		if match == nil {
+			continue
+		}
+
+		cmd := &command{
+			Name:  strings.ToLower(match[1]),
+			Flags: map[string]string{},
+		}
+		for _, token := range tokenizeCommandArgs(trimmed[len(match[0]):]) {
+			if token.quoted || !strings.HasPrefix(token.value, "--") || len(token.value) == 2 {
+				cmd.Args = append(cmd.Args, token.value)
+				continue
+			}
+
+			name, value := token.value[2:], ""
+			if i := strings.Index(name, "="); i >= 0 {
+				name, value = name[:i], name[i+1:]
+			}
+			cmd.Flags[name] = value
+		}
+		commands = append(commands, cmd)
+	}
+
+	return commands
+}
+
+type commandToken struct {
+	value string
+	// quoted is set if any part of the token was quoted. Quoted tokens are
+	// never taken for flags.
+	quoted bool
+}
+
+// tokenizeCommandArgs splits the arguments of a command on whitespace.
+// Single and double quotes group words into one argument, and a backslash
+// escapes the next character outside of single quotes. An unterminated
+// quote runs until the end of the line.
+func tokenizeCommandArgs(s string) []commandToken {
+	var tokens []commandToken
+	var current strings.Builder
+	var quote rune
+	inToken, quoted, escaped := false, false, false
+
+	for _, r := range s {
+		switch {
+		case escaped:
+			current.WriteRune(r)
+			escaped = false
+		case r == '\\' && quote != '\'':
+			escaped = true
+			inToken = true
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				current.WriteRune(r)
+			}
+		case r == '"' || r == '\'':
+			quote = r
+			inToken, quoted = true, true
+		case unicode.IsSpace(r):
+			if inToken {
+				tokens = append(tokens, commandToken{value: current.String(), quoted: quoted})
+				current.Reset()
+				inToken, quoted = false, false
+			}
+		default:
+			current.WriteRune(r)
+			inToken = true
+		}
+	}
+	if inToken {
+		tokens = append(tokens, commandToken{value: current.String(), quoted: quoted})
+	}
+
+	return tokens
+}
+
+// commandRequest is a command run from a comment on a PR.
+type commandRequest struct {
+	event     *issueCommentEvent
+	pr        *model.PullRequest
+	commenter string
+	cmd       *command
+}
+
+// commandDefinition describes a slash command and how it is run.
+type commandDefinition struct {
+	Name        string
+	Usage       string
+	Description string
+	// Roles are allowed to run the command unless the repository overrides
+	// them in its CommandPermissions.
+	Roles []string
+	// Automation, if set, names the automation the command belongs to. The
+	// command is ignored in repositories where the automation is disabled.
+	Automation string
+	run        func(ctx context.Context, s *Server, req *commandRequest) error
+}
+
+// commandDefinitions returns all commands which can be run from PR comments.
+func commandDefinitions() []*commandDefinition {
+	return []*commandDefinition{
+		{
+			Name:        "check-cla",
+			Usage:       "/check-cla",
+			Description: "Checks again whether the PR author signed the CLA.",
+			Roles:       []string{roleAnyone},
+			Automation:  claAutomationName,
+			run: func(ctx context.Context, s *Server, req *commandRequest) error {
+				if req.pr.State == model.StateClosed {
+					return errNothingToDo
+				}
+				_, err := s.handleCheckCLA(ctx, req.pr)
+				return err
+			},
+		},
+		{
+			Name:        "cherry-pick",
+			Usage:       "/cherry-pick <branch>...",
+			Description: "Cherry picks the merged PR onto each branch. The results are kept up to date in one comment.",
+			Roles:       []string{roleOrgMember},
+			run: func(ctx context.Context, s *Server, req *commandRequest) error {
+				return s.handleCherryPick(ctx, req.cmd.Args, req.pr)
+			},
+		},
+		{
+			Name:        "autoassign",
+			Usage:       "/autoassign",
+			Description: "Asks the auto assigner team to add reviewers.",
+			Roles:       []string{roleAnyone},
+			run: func(ctx context.Context, s *Server, req *commandRequest) error {
+				return s.handleAutoAssign(ctx, req.event.Comment.GetHTMLURL(), req.pr)
+			},
+		},
+		{
+			Name:        "update-branch",
+			Usage:       "/update-branch",
+			Description: "Merges the base branch into the PR branch.",
+			Roles:       []string{roleAuthor, roleOrgMember},
+			run: func(ctx context.Context, s *Server, req *commandRequest) error {
+				return s.handleUpdateBranch(ctx, req.pr)
+			},
+		},
+		{
+			Name:        "merge",
+			Usage:       "/merge [squash|merge|rebase]",
+			Description: "Merges the PR once it is ready: the merge state is clean, all required checks are successful and no reviews are pending. Uses the merge method of the repository by default.",
+			Roles:       []string{roleOrgMember},
+			run: func(ctx context.Context, s *Server, req *commandRequest) error {
+				return s.handleMerge(ctx, req.commenter, req.cmd.Args, req.pr)
+			},
+		},
+		{
+			Name:        "hold",
+			Usage:       "/hold [reason]",
+			Description: "Blocks the merge of the PR until the hold is lifted.",
+			Roles:       []string{roleOrgMember},
+			Automation:  blockMergeAutomationName,
+			run: func(ctx context.Context, s *Server, req *commandRequest) error {
+				return s.handleHold(ctx, req.commenter, req.cmd.Args, req.pr)
+			},
+		},
+		{
+			Name:        "unhold",
+			Usage:       "/unhold",
+			Description: "Lifts your hold on the PR. Maintainers lift all holds.",
+			Roles:       []string{roleOrgMember},
+			Automation:  blockMergeAutomationName,
+			run: func(ctx context.Context, s *Server, req *commandRequest) error {
+				return s.handleUnhold(ctx, req.commenter, req.pr)
+			},
+		},
+		{
+			Name:        "help",
+			Usage:       "/help",
+			Description: "Lists the commands available on this PR.",
+			Roles:       []string{roleAnyone},
+			run: func(ctx context.Context, s *Server, req *commandRequest) error {
+				return s.sendGitHubComment(ctx, req.pr.RepoOwner, req.pr.RepoName, req.pr.Number, s.commandHelp(req.pr.RepoOwner, req.pr.RepoName))
+			},
+		},
+	}
+}
+
+func findCommandDefinition(name string) *commandDefinition {
+	for _, def := range commandDefinitions() {
+		if def.Name == name {
+			return def
+		}
+	}
+	return nil
+}
+
+// isCommandAvailable reports whether the command can be run in the repository.
+func (s *Server) isCommandAvailable(def *commandDefinition, owner, repoName string) bool {
+	return def.Automation == "" || s.isAutomationEnabled(owner, repoName, def.Automation)
+}
+
+// runCommand runs a command from a comment, if the commenter is allowed to.
+// Commands which aren't available in the repository are ignored.
+func (s *Server) runCommand(ctx context.Context, req *commandRequest) error {
+	def := findCommandDefinition(req.cmd.Name)
+	if def == nil || !s.isCommandAvailable(def, req.pr.RepoOwner, req.pr.RepoName) {
+		return nil
+	}
+
+	metric := strings.ReplaceAll(def.Name, "-", "_")
+	s.Metrics.IncreaseWebhookRequest(metric)
+
+	roles := s.commandRoles(def, req.pr.RepoOwner, req.pr.RepoName)
+	allowed, err := s.hasCommandRole(ctx, roles, req.commenter, req.pr)
+	if err != nil {
+		s.Metrics.IncreaseWebhookErrors(metric)
+		return fmt.Errorf("could not check the permissions for /%s: %w", def.Name, err)
+	}
+	if !allowed {
+		s.Metrics.IncreaseCommandDenials(def.Name)
+		mlog.Info("Commenter is not allowed to run the command",
+			mlog.String("command", def.Name),
+			mlog.String("commenter", req.commenter),
+			mlog.String("repo", req.pr.RepoOwner+"/"+req.pr.RepoName),
+			mlog.Int("pr", req.pr.Number))
+		s.reactToCommand(ctx, req, reactionFailed)
+		return s.sendGitHubComment(ctx, req.pr.RepoOwner, req.pr.RepoName, req.pr.Number, commandDeniedMessage(def, roles))
+	}
+
+	s.reactToCommand(ctx, req, reactionAccepted)
+	err = def.run(ctx, s, req)
+	if errors.Is(err, errNothingToDo) {
+		mlog.Info("Command had nothing to do",
+			mlog.String("command", def.Name),
+			mlog.String("repo", req.pr.RepoOwner+"/"+req.pr.RepoName),
+			mlog.Int("pr", req.pr.Number),
+			mlog.Err(err))
+		return nil
+	}
+	if errors.Is(err, errCommandDenied) {
+		mlog.Info("Command was denied",
+			mlog.String("command", def.Name),
+			mlog.String("repo", req.pr.RepoOwner+"/"+req.pr.RepoName),
+			mlog.Int("pr", req.pr.Number),
+			mlog.Err(err))
+		s.reactToCommand(ctx, req, reactionFailed)
+		return nil
+	}
+	if err != nil {
+		s.reactToCommand(ctx, req, reactionFailed)
+		s.Metrics.IncreaseWebhookErrors(metric)
+		return fmt.Errorf("error running /%s: %w", def.Name, err)
+	}
+	s.reactToCommand(ctx, req, reactionSucceeded)
+	return nil
+}
+
+// commandHelp renders the table of the commands available in the repository.
+func (s *Server) commandHelp(owner, repoName string) string {
+	var b strings.Builder
+	b.WriteString("These commands can be used in comments on this PR. Every command has to start a line.\n\n")
+	b.WriteString("| Command | Description | Who can run it |\n")
+	b.WriteString("| --- | --- | --- |\n")
+	for _, def := range commandDefinitions() {
+		if !s.isCommandAvailable(def, owner, repoName) {
+			continue
+		}
+		fmt.Fprintf(&b, "| `%s` | %s | %s |\n",
+			escapeTableCell(def.Usage),
+			escapeTableCell(def.Description),
+			escapeTableCell(describeRoles(s.commandRoles(def, owner, repoName))))
+	}
+	return b.String()
+}
+
+// escapeTableCell keeps pipes from ending a cell of a markdown table. This
+// also works within code spans.
+func escapeTableCell(s string) string {
+	return strings.ReplaceAll(s, "|", "\\|")
+}
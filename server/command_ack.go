@@ -0,0 +1,56 @@
+// Copyright (c) 2017-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package server
+
+import (
+	"context"
+
+	"github.com/mattermost/mattermost-server/v6/shared/mlog"
+)
+
+// Styles of acknowledging commands, set per repository in CommandAcknowledgement.
+const (
+	commandAckComment  = "comment"
+	commandAckReaction = "reaction"
+)
+
+// Reactions added to the comment of a command in the reaction style.
+const (
+	reactionAccepted  = "eyes"
+	reactionSucceeded = "rocket"
+	reactionFailed    = "confused"
+)
+
+// acknowledgesWithReactions reports whether commands in the repository are
+// acknowledged with reactions. Commands then only comment on errors which
+// need an explanation.
+func (s *Server) acknowledgesWithReactions(owner, repoName string) bool {
+	repo, ok := GetRepository(s.Config.Repositories, owner, repoName)
+	return ok && repo.CommandAcknowledgement == commandAckReaction
+}
+
+// reactToCommand adds a reaction to the comment of the command, if the
+// repository uses reactions. Failing to react doesn't fail the command.
+func (s *Server) reactToCommand(ctx context.Context, req *commandRequest, content string) {
+	if !s.acknowledgesWithReactions(req.pr.RepoOwner, req.pr.RepoName) || req.event == nil {
+		return
+	}
+
+	_, _, err := s.GithubClient.Issues.CreateIssueCommentReaction(ctx, req.pr.RepoOwner, req.pr.RepoName, req.event.Comment.GetID(), content)
+	if err != nil {
+		mlog.Warn("Error while reacting to command",
+			mlog.String("command", req.cmd.Name),
+			mlog.String("reaction", content),
+			mlog.Err(err))
+	}
+}
+
+// sendCommandComment comments a message which only acknowledges a command.
+// It is skipped if the repository acknowledges commands with reactions.
+func (s *Server) sendCommandComment(ctx context.Context, repoOwner, repoName string, number int, comment string) error {
+	if s.acknowledgesWithReactions(repoOwner, repoName) {
+		return nil
+	}
+	return s.sendGitHubComment(ctx, repoOwner, repoName, number, comment)
+}
@@ -0,0 +1,177 @@
+// Copyright (c) 2017-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package server
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/go-github/v39/github"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-mattermod/model"
+	"github.com/mattermost/mattermost-mattermod/server/mocks"
+	stmock "github.com/mattermost/mattermost-mattermod/store/mocks"
+)
+
+func TestCommandReactions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctxInterface := reflect.TypeOf((*context.Context)(nil)).Elem()
+
+	metricsMock := mocks.NewMockMetricsProvider(ctrl)
+	metricsMock.EXPECT().IncreaseWebhookRequest(gomock.Any()).AnyTimes()
+	is := mocks.NewMockIssuesService(ctrl)
+	repos := mocks.NewMockRepositoriesService(ctrl)
+	prs := mocks.NewMockPullRequestsService(ctrl)
+	jobStore := stmock.NewMockCherryPickJobStore(ctrl)
+	jobStore.EXPECT().ListByPR("mattertest", "mattermod", 1).Return(nil, nil).AnyTimes()
+	backportStore := stmock.NewMockBackportStore(ctrl)
+	backportStore.EXPECT().Save(gomock.AssignableToTypeOf(&model.Backport{})).Return(nil).AnyTimes()
+	backportStore.EXPECT().ListByPR("mattertest", "mattermod", 1).Return(nil, nil).AnyTimes()
+	ss := stmock.NewMockStore(ctrl)
+	ss.EXPECT().CherryPickJob().Return(jobStore).AnyTimes()
+	ss.EXPECT().Backport().Return(backportStore).AnyTimes()
+	holdStore := stmock.NewMockHoldStore(ctrl)
+	ss.EXPECT().Hold().Return(holdStore).AnyTimes()
+	s := &Server{
+		Config: &Config{
+			Repositories: []*Repository{
+				{
+					Owner:                  "mattertest",
+					Name:                   "mattermod",
+					CommandAcknowledgement: commandAckReaction,
+				},
+			},
+			AutoAssignerTeam:   "reviewers",
+			AutoAssignerTeamID: 1,
+		},
+		OrgMembers: []string{"org-member"},
+		Metrics:    metricsMock,
+		Store:      ss,
+		GithubClient: &GithubClient{
+			Issues:       is,
+			Repositories: repos,
+			PullRequests: prs,
+		},
+	}
+	pr := &model.PullRequest{
+		RepoOwner: "mattertest",
+		RepoName:  "mattermod",
+		Number:    1,
+		Merged:    NewBool(true),
+	}
+	event := &issueCommentEvent{
+		Comment: &github.PullRequestComment{
+			ID:      github.Int64(42),
+			HTMLURL: github.String("https://github.com/mattertest/mattermod/pull/1#issuecomment-42"),
+		},
+	}
+
+	run := func(commenter, body string) error {
+		cmds := parseCommands(body)
+		require.Len(t, cmds, 1)
+		return s.runCommand(context.Background(), &commandRequest{event: event, pr: pr, commenter: commenter, cmd: cmds[0]})
+	}
+	expectReactions := func(reactions ...string) {
+		var calls []*gomock.Call
+		for _, reaction := range reactions {
+			calls = append(calls, is.EXPECT().
+				CreateIssueCommentReaction(gomock.AssignableToTypeOf(ctxInterface), "mattertest", "mattermod", int64(42), reaction).
+				Return(nil, nil, nil))
+		}
+		gomock.InOrder(calls...)
+	}
+
+	t.Run("Successful commands get reactions", func(t *testing.T) {
+		expectReactions(reactionAccepted, reactionSucceeded)
+		jobStore.EXPECT().Create(gomock.AssignableToTypeOf(&model.CherryPickJob{})).Return(nil)
+		is.EXPECT().
+			ListComments(gomock.AssignableToTypeOf(ctxInterface), "mattertest", "mattermod", 1, gomock.Any()).
+			Return(nil, &github.Response{Response: &http.Response{StatusCode: http.StatusOK}}, nil)
+		is.EXPECT().
+			CreateComment(gomock.AssignableToTypeOf(ctxInterface), "mattertest", "mattermod", 1, gomock.AssignableToTypeOf(&github.IssueComment{})).
+			Return(&github.IssueComment{ID: github.Int64(7)}, nil, nil)
+
+		require.NoError(t, run("org-member", "/cherry-pick release-6.0"))
+	})
+
+	t.Run("Failing commands keep their explanation", func(t *testing.T) {
+		metricsMock.EXPECT().IncreaseWebhookErrors("merge")
+		expectReactions(reactionAccepted, reactionFailed)
+		is.EXPECT().
+			CreateComment(gomock.AssignableToTypeOf(ctxInterface), "mattertest", "mattermod", 1, &github.IssueComment{Body: github.String("Unknown merge method `fast-forward`. Please use one of: squash, merge or rebase.")}).
+			Return(nil, nil, nil)
+
+		require.Error(t, run("org-member", "/merge fast-forward"))
+	})
+
+	t.Run("Commands with nothing to do don't get the success reaction", func(t *testing.T) {
+		expectReactions(reactionAccepted)
+
+		require.NoError(t, run("org-member", "/cherry-pick"))
+	})
+
+	t.Run("Auto assign doesn't comment on success", func(t *testing.T) {
+		expectReactions(reactionAccepted, reactionSucceeded)
+		repos.EXPECT().ListTeams(gomock.AssignableToTypeOf(ctxInterface), "mattertest", "mattermod", nil).
+			Return([]*github.Team{{ID: github.Int64(1)}}, nil, nil)
+		prs.EXPECT().RequestReviewers(gomock.AssignableToTypeOf(ctxInterface), "mattertest", "mattermod", 1, github.ReviewersRequest{TeamReviewers: []string{"reviewers"}}).
+			Return(nil, nil, nil)
+
+		require.NoError(t, run("someone", "/autoassign"))
+	})
+
+	t.Run("Denied commands are explained", func(t *testing.T) {
+		metricsMock.EXPECT().IncreaseCommandDenials("cherry-pick")
+		expectReactions(reactionFailed)
+		is.EXPECT().
+			CreateComment(gomock.AssignableToTypeOf(ctxInterface), "mattertest", "mattermod", 1, gomock.AssignableToTypeOf(&github.IssueComment{})).
+			Return(nil, nil, nil)
+
+		require.NoError(t, run("someone", "/cherry-pick release-6.0"))
+	})
+
+	t.Run("Commands refused by their handler get the failure reaction", func(t *testing.T) {
+		metricsMock.EXPECT().IncreaseCommandDenials("unhold")
+		expectReactions(reactionAccepted, reactionFailed)
+		holdStore.EXPECT().List("mattertest", "mattermod", 1).Return([]*model.Hold{{Holder: "alice"}}, nil)
+		repos.EXPECT().GetPermissionLevel(gomock.AssignableToTypeOf(ctxInterface), "mattertest", "mattermod", "org-member").
+			Return(nil, &github.Response{Response: &http.Response{StatusCode: http.StatusNotFound}}, nil)
+		is.EXPECT().
+			CreateComment(gomock.AssignableToTypeOf(ctxInterface), "mattertest", "mattermod", 1, gomock.AssignableToTypeOf(&github.IssueComment{})).
+			Return(nil, nil, nil)
+
+		require.NoError(t, run("org-member", "/unhold"))
+	})
+
+	t.Run("Failing to react doesn't fail the command", func(t *testing.T) {
+		is.EXPECT().
+			CreateIssueCommentReaction(gomock.AssignableToTypeOf(ctxInterface), "mattertest", "mattermod", int64(42), gomock.Any()).
+			Return(nil, nil, errors.New("some-error")).
+			Times(2)
+		jobStore.EXPECT().Create(gomock.AssignableToTypeOf(&model.CherryPickJob{})).Return(nil)
+		is.EXPECT().
+			EditComment(gomock.AssignableToTypeOf(ctxInterface), "mattertest", "mattermod", int64(7), gomock.AssignableToTypeOf(&github.IssueComment{})).
+			Return(nil, nil, nil)
+
+		require.NoError(t, run("org-member", "/cherry-pick release-6.1"))
+	})
+
+	t.Run("Comment style doesn't react", func(t *testing.T) {
+		s.Config.Repositories[0].CommandAcknowledgement = commandAckComment
+		defer func() { s.Config.Repositories[0].CommandAcknowledgement = commandAckReaction }()
+		jobStore.EXPECT().Create(gomock.AssignableToTypeOf(&model.CherryPickJob{})).Return(nil)
+		is.EXPECT().
+			EditComment(gomock.AssignableToTypeOf(ctxInterface), "mattertest", "mattermod", int64(7), gomock.AssignableToTypeOf(&github.IssueComment{})).
+			Return(nil, nil, nil)
+
+		require.NoError(t, run("org-member", "/cherry-pick release-6.2"))
+	})
+}
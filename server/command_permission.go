@@ -0,0 +1,162 @@
+// Copyright (c) 2017-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package server
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/google/go-github/v39/github"
+	"github.com/mattermost/mattermost-mattermod/model"
+	"github.com/mattermost/mattermost-server/v6/shared/mlog"
+)
+
+// Roles which can be allowed to run a command, in the command definitions and
+// in the CommandPermissions of a repository.
+const (
+	roleAnyone    = "anyone"
+	roleAuthor    = "author"
+	roleOrgMember = "org-member"
+	// roleTeamPrefix is followed by the slug of a team of the organization,
+	// e.g. team:core-developers.
+	roleTeamPrefix = "team:"
+	// roleCollaboratorPrefix is followed by the minimum permission on the
+	// repository: write, maintain or admin.
+	roleCollaboratorPrefix = "collaborator:"
+	// roleUserPrefix is followed by a GitHub login.
+	roleUserPrefix = "user:"
+)
+
+const msgCommenterPermission = "Looks like you don't have permissions to trigger `/%s`.\nIt's only available for %s."
+
+// collaboratorLevels ranks the permissions of repository collaborators.
+var collaboratorLevels = map[string]int{
+	"read":     1,
+	"write":    2,
+	"maintain": 3,
+	"admin":    4,
+}
+
+// commandRoles returns the roles allowed to run the command in the repository.
+func (s *Server) commandRoles(def *commandDefinition, owner, repoName string) []string {
+	if repo, ok := GetRepository(s.Config.Repositories, owner, repoName); ok {
+		if roles, ok := repo.CommandPermissions[def.Name]; ok {
+			return roles
+		}
+	}
+	return def.Roles
+}
+
+// hasCommandRole reports whether the commenter has any of the roles. Users in
+// BlockListBots only match if anyone is allowed.
+func (s *Server) hasCommandRole(ctx context.Context, roles []string, commenter string, pr *model.PullRequest) (bool, error) {
+	for _, role := range roles {
+		if role == roleAnyone {
+			return true, nil
+		}
+	}
+	if s.IsInBotBlockList(commenter) {
+		return false, nil
+	}
+
+	for _, role := range roles {
+		ok, err := s.hasRole(ctx, role, commenter, pr)
+		if err != nil {
+			return false, err
+		}
+		if ok {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (s *Server) hasRole(ctx context.Context, role, commenter string, pr *model.PullRequest) (bool, error) {
+	switch {
+	case role == roleAuthor:
+		return commenter == pr.Username, nil
+	case role == roleOrgMember:
+		return s.IsOrgMember(commenter), nil
+	case strings.HasPrefix(role, roleUserPrefix):
+		return strings.EqualFold(commenter, strings.TrimPrefix(role, roleUserPrefix)), nil
+	case strings.HasPrefix(role, roleTeamPrefix):
+		return s.isTeamMember(ctx, strings.TrimPrefix(role, roleTeamPrefix), commenter)
+	case strings.HasPrefix(role, roleCollaboratorPrefix):
+		level, ok := collaboratorLevels[strings.TrimPrefix(role, roleCollaboratorPrefix)]
+		if !ok {
+			break
+		}
+		return s.hasCollaboratorLevel(ctx, pr.RepoOwner, pr.RepoName, commenter, level)
+	}
+
+	mlog.Warn("Ignoring unknown command role", mlog.String("role", role))
+	return false, nil
+}
+
+func (s *Server) isTeamMember(ctx context.Context, slug, user string) (bool, error) {
+	membership, resp, err := s.GithubClient.Teams.GetTeamMembershipBySlug(ctx, s.Config.Org, slug, user)
+	if resp != nil && resp.StatusCode == http.StatusNotFound {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("could not get the membership of %s in team %s: %w", user, slug, err)
+	}
+	return membership.GetState() == "active", nil
+}
+
+func (s *Server) hasCollaboratorLevel(ctx context.Context, owner, repoName, user string, level int) (bool, error) {
+	permission, resp, err := s.GithubClient.Repositories.GetPermissionLevel(ctx, owner, repoName, user)
+	if resp != nil && resp.StatusCode == http.StatusNotFound {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("could not get the permission of %s on %s/%s: %w", user, owner, repoName, err)
+	}
+	return collaboratorLevel(permission) >= level, nil
+}
+
+// collaboratorLevel ranks the permission of a collaborator. The permission
+// field doesn't know about maintainers, they are reported as writers.
+func collaboratorLevel(permission *github.RepositoryPermissionLevel) int {
+	if permission.GetPermission() != "admin" && permission.GetUser().GetPermissions()["maintain"] {
+		return collaboratorLevels["maintain"]
+	}
+	return collaboratorLevels[permission.GetPermission()]
+}
+
+// describeRoles lists the roles for humans, e.g. in the /help table.
+func describeRoles(roles []string) string {
+	descriptions := make([]string, 0, len(roles))
+	for _, role := range roles {
+		switch {
+		case role == roleAnyone:
+			return "Anyone"
+		case role == roleAuthor:
+			descriptions = append(descriptions, "the PR author")
+		case role == roleOrgMember:
+			descriptions = append(descriptions, "org members")
+		case strings.HasPrefix(role, roleUserPrefix):
+			descriptions = append(descriptions, "@"+strings.TrimPrefix(role, roleUserPrefix))
+		case strings.HasPrefix(role, roleTeamPrefix):
+			descriptions = append(descriptions, "members of the "+strings.TrimPrefix(role, roleTeamPrefix)+" team")
+		case strings.HasPrefix(role, roleCollaboratorPrefix):
+			descriptions = append(descriptions, "collaborators with "+strings.TrimPrefix(role, roleCollaboratorPrefix)+" permission")
+		}
+	}
+	if len(descriptions) == 0 {
+		return "Nobody"
+	}
+
+	description := strings.Join(descriptions, ", ")
+	return strings.ToUpper(description[:1]) + description[1:] + ", except blocked bots"
+}
+
+// commandDeniedMessage is the comment sent when the commenter isn't allowed
+// to run the command.
+func commandDeniedMessage(def *commandDefinition, roles []string) string {
+	who := describeRoles(roles)
+	return fmt.Sprintf(msgCommenterPermission, def.Name, strings.ToLower(who[:1])+who[1:])
+}
@@ -0,0 +1,155 @@
+// Copyright (c) 2017-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package server
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/go-github/v39/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-mattermod/model"
+	"github.com/mattermost/mattermost-mattermod/server/mocks"
+)
+
+func TestCommandRoles(t *testing.T) {
+	s := &Server{
+		Config: &Config{
+			Repositories: []*Repository{
+				{
+					Owner:              "mattertest",
+					Name:               "mattermod",
+					CommandPermissions: map[string][]string{"cherry-pick": {"team:release-managers"}},
+				},
+			},
+		},
+	}
+	def := findCommandDefinition("cherry-pick")
+	require.NotNil(t, def)
+
+	assert.Equal(t, []string{"team:release-managers"}, s.commandRoles(def, "mattertest", "mattermod"))
+	assert.Equal(t, []string{roleOrgMember}, s.commandRoles(def, "mattertest", "mattermost-server"))
+	assert.Equal(t, []string{roleAuthor, roleOrgMember}, s.commandRoles(findCommandDefinition("update-branch"), "mattertest", "mattermod"))
+}
+
+func TestHasCommandRole(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctxInterface := reflect.TypeOf((*context.Context)(nil)).Elem()
+	notFound := &github.Response{Response: &http.Response{StatusCode: http.StatusNotFound}}
+
+	ts := mocks.NewMockTeamsService(ctrl)
+	rs := mocks.NewMockRepositoriesService(ctrl)
+	s := &Server{
+		Config: &Config{
+			Org:           "mattertest",
+			BlockListBots: []string{"blocked-bot"},
+		},
+		OrgMembers:   []string{"org-member", "blocked-bot"},
+		GithubClient: &GithubClient{Teams: ts, Repositories: rs},
+	}
+	pr := &model.PullRequest{RepoOwner: "mattertest", RepoName: "mattermod", Username: "author"}
+
+	ts.EXPECT().GetTeamMembershipBySlug(gomock.AssignableToTypeOf(ctxInterface), "mattertest", "core", "team-member").
+		Return(&github.Membership{State: github.String("active")}, nil, nil).AnyTimes()
+	ts.EXPECT().GetTeamMembershipBySlug(gomock.AssignableToTypeOf(ctxInterface), "mattertest", "core", "invited").
+		Return(&github.Membership{State: github.String("pending")}, nil, nil).AnyTimes()
+	ts.EXPECT().GetTeamMembershipBySlug(gomock.AssignableToTypeOf(ctxInterface), "mattertest", "core", gomock.Any()).
+		Return(nil, notFound, errors.New("not found")).AnyTimes()
+
+	permission := func(level string, maintain bool) *github.RepositoryPermissionLevel {
+		return &github.RepositoryPermissionLevel{
+			Permission: github.String(level),
+			User:       &github.User{Permissions: map[string]bool{"maintain": maintain}},
+		}
+	}
+	rs.EXPECT().GetPermissionLevel(gomock.AssignableToTypeOf(ctxInterface), "mattertest", "mattermod", "writer").
+		Return(permission("write", false), nil, nil).AnyTimes()
+	rs.EXPECT().GetPermissionLevel(gomock.AssignableToTypeOf(ctxInterface), "mattertest", "mattermod", "maintainer").
+		Return(permission("write", true), nil, nil).AnyTimes()
+	rs.EXPECT().GetPermissionLevel(gomock.AssignableToTypeOf(ctxInterface), "mattertest", "mattermod", "admin").
+		Return(permission("admin", true), nil, nil).AnyTimes()
+	rs.EXPECT().GetPermissionLevel(gomock.AssignableToTypeOf(ctxInterface), "mattertest", "mattermod", gomock.Any()).
+		Return(nil, notFound, errors.New("not found")).AnyTimes()
+
+	for name, tc := range map[string]struct {
+		roles   []string
+		allowed map[string]bool
+	}{
+		"Anyone": {
+			roles:   []string{roleAnyone},
+			allowed: map[string]bool{"someone": true, "blocked-bot": true},
+		},
+		"Author": {
+			roles:   []string{roleAuthor},
+			allowed: map[string]bool{"author": true, "org-member": false},
+		},
+		"Org members": {
+			roles:   []string{roleOrgMember},
+			allowed: map[string]bool{"someone": false, "author": false, "org-member": true, "blocked-bot": false},
+		},
+		"Explicit users": {
+			roles:   []string{"user:Someone"},
+			allowed: map[string]bool{"someone": true, "org-member": false},
+		},
+		"Team members": {
+			roles:   []string{"team:core"},
+			allowed: map[string]bool{"team-member": true, "invited": false, "someone": false},
+		},
+		"Collaborators with write permission": {
+			roles:   []string{"collaborator:write"},
+			allowed: map[string]bool{"writer": true, "maintainer": true, "admin": true, "someone": false},
+		},
+		"Collaborators with maintain permission": {
+			roles:   []string{"collaborator:maintain"},
+			allowed: map[string]bool{"writer": false, "maintainer": true, "admin": true},
+		},
+		"Collaborators with admin permission": {
+			roles:   []string{"collaborator:admin"},
+			allowed: map[string]bool{"maintainer": false, "admin": true},
+		},
+		"Unknown roles": {
+			roles:   []string{"collaborator:triage", "owner"},
+			allowed: map[string]bool{"admin": false, "org-member": false},
+		},
+		"Any of the roles": {
+			roles:   []string{roleAuthor, "team:core"},
+			allowed: map[string]bool{"author": true, "team-member": true, "org-member": false},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			for commenter, allowed := range tc.allowed {
+				ok, err := s.hasCommandRole(context.Background(), tc.roles, commenter, pr)
+				require.NoError(t, err, commenter)
+				assert.Equal(t, allowed, ok, commenter)
+			}
+		})
+	}
+
+	t.Run("GitHub errors are returned", func(t *testing.T) {
+		ts.EXPECT().GetTeamMembershipBySlug(gomock.AssignableToTypeOf(ctxInterface), "mattertest", "broken", "someone").
+			Return(nil, nil, errors.New("some-error"))
+
+		_, err := s.hasCommandRole(context.Background(), []string{"team:broken"}, "someone", pr)
+		require.Error(t, err)
+	})
+}
+
+func TestDescribeRoles(t *testing.T) {
+	assert.Equal(t, "Anyone", describeRoles([]string{roleOrgMember, roleAnyone}))
+	assert.Equal(t, "Nobody", describeRoles(nil))
+	assert.Equal(t,
+		"The PR author, members of the core team, collaborators with maintain permission, @someone, except blocked bots",
+		describeRoles([]string{roleAuthor, "team:core", "collaborator:maintain", "user:someone"}))
+	assert.Equal(t,
+		"Looks like you don't have permissions to trigger `/update-branch`.\nIt's only available for the PR author, org members, except blocked bots.",
+		commandDeniedMessage(findCommandDefinition("update-branch"), []string{roleAuthor, roleOrgMember}))
+}
@@ -0,0 +1,203 @@
+// Copyright (c) 2017-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package server
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/go-github/v39/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-mattermod/model"
+	"github.com/mattermost/mattermost-mattermod/server/mocks"
+)
+
+func TestParseCommands(t *testing.T) {
+	for name, tc := range map[string]struct {
+		body     string
+		expected []*command
+	}{
+		"No command": {
+			body: "LGTM!",
+		},
+		"Single command": {
+			body: "/cherry-pick release-6.0",
+			expected: []*command{
+				{Name: "cherry-pick", Args: []string{"release-6.0"}, Flags: map[string]string{}},
+			},
+		},
+		"Command after prose on another line": {
+			body: "Looks good to go.\r\n/update-branch",
+			expected: []*command{
+				{Name: "update-branch", Flags: map[string]string{}},
+			},
+		},
+		"Command inside prose": {
+			body: "Please run /cherry-pick release-6.0 once merged",
+		},
+		"Multiple commands in order": {
+			body: "/update-branch\n  /Cherry-Pick release-6.0\n/check-cla",
+			expected: []*command{
+				{Name: "update-branch", Flags: map[string]string{}},
+				{Name: "cherry-pick", Args: []string{"release-6.0"}, Flags: map[string]string{}},
+				{Name: "check-cla", Flags: map[string]string{}},
+			},
+		},
+		"Code fences": {
+			body: "```\n/cherry-pick release-6.0\n```\n~~~sh\n/update-branch\n~~~\n/check-cla",
+			expected: []*command{
+				{Name: "check-cla", Flags: map[string]string{}},
+			},
+		},
+		"Indented code": {
+			body: "    /cherry-pick release-6.0\n\t/update-branch",
+		},
+		"Quotes": {
+			body: "> /cherry-pick release-6.0\n>/update-branch",
+		},
+		"Paths are not commands": {
+			body: "/usr/bin/env bash",
+		},
+		"Quoted arguments and flags": {
+			body: `/hold "waiting for QA" --until=friday --force 'it''s' -- --"not a flag"`,
+			expected: []*command{
+				{
+					Name:  "hold",
+					Args:  []string{"waiting for QA", "its", "--", "--not a flag"},
+					Flags: map[string]string{"until": "friday", "force": ""},
+				},
+			},
+		},
+		"Escapes and unterminated quotes": {
+			body: `/hold a\ b "c \"d\"" "e f`,
+			expected: []*command{
+				{Name: "hold", Args: []string{"a b", `c "d"`, "e f"}, Flags: map[string]string{}},
+			},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, parseCommands(tc.body))
+		})
+	}
+}
+
+func TestCommandHasFlag(t *testing.T) {
+	cmds := parseCommands("/merge squash --now")
+	require.Len(t, cmds, 1)
+	assert.True(t, cmds[0].HasFlag("now"))
+	assert.False(t, cmds[0].HasFlag("squash"))
+}
+
+func TestCommandDefinitions(t *testing.T) {
+	names := map[string]bool{}
+	for _, def := range commandDefinitions() {
+		assert.False(t, names[def.Name], "duplicate command %s", def.Name)
+		names[def.Name] = true
+		assert.NotNil(t, def.run, def.Name)
+		require.NotNil(t, findCommandDefinition(def.Name), def.Name)
+		assert.Equal(t, def.Usage, findCommandDefinition(def.Name).Usage)
+	}
+	assert.Nil(t, findCommandDefinition("unknown"))
+}
+
+func TestRunCommand(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctxInterface := reflect.TypeOf((*context.Context)(nil)).Elem()
+
+	metricsMock := mocks.NewMockMetricsProvider(ctrl)
+	is := mocks.NewMockIssuesService(ctrl)
+	s := &Server{
+		Config: &Config{
+			Repositories: []*Repository{
+				{
+					Owner:       "mattertest",
+					Name:        "mattermod",
+					Automations: map[string]bool{claAutomationName: false},
+					CommandPermissions: map[string][]string{
+						"update-branch": {"user:maintainer"},
+					},
+				},
+			},
+		},
+		OrgMembers:   []string{"org-member"},
+		Metrics:      metricsMock,
+		GithubClient: &GithubClient{Issues: is},
+	}
+	pr := &model.PullRequest{
+		RepoOwner: "mattertest",
+		RepoName:  "mattermod",
+		Number:    1,
+		Username:  "author",
+		Merged:    NewBool(false),
+	}
+
+	run := func(commenter, body string) error {
+		cmds := parseCommands(body)
+		require.Len(t, cmds, 1)
+		return s.runCommand(context.Background(), &commandRequest{pr: pr, commenter: commenter, cmd: cmds[0]})
+	}
+
+	t.Run("Unknown commands are ignored", func(t *testing.T) {
+		require.NoError(t, run("someone", "/unknown"))
+	})
+
+	t.Run("Commands of disabled automations are ignored", func(t *testing.T) {
+		require.NoError(t, run("someone", "/check-cla"))
+	})
+
+	t.Run("Commenters without permission are told so", func(t *testing.T) {
+		metricsMock.EXPECT().IncreaseWebhookRequest("cherry_pick")
+		metricsMock.EXPECT().IncreaseCommandDenials("cherry-pick")
+		is.EXPECT().
+			CreateComment(gomock.AssignableToTypeOf(ctxInterface), "mattertest", "mattermod", 1, &github.IssueComment{
+				Body: github.String("Looks like you don't have permissions to trigger `/cherry-pick`.\nIt's only available for org members, except blocked bots."),
+			}).
+			Return(nil, nil, nil)
+
+		require.NoError(t, run("author", "/cherry-pick release-6.0"))
+	})
+
+	t.Run("Commenters with permission run the command", func(t *testing.T) {
+		metricsMock.EXPECT().IncreaseWebhookRequest("cherry_pick")
+
+		require.NoError(t, run("org-member", "/cherry-pick release-6.0"))
+	})
+
+	t.Run("Help lists the available commands", func(t *testing.T) {
+		metricsMock.EXPECT().IncreaseWebhookRequest("help")
+		is.EXPECT().
+			CreateComment(gomock.AssignableToTypeOf(ctxInterface), "mattertest", "mattermod", 1, gomock.AssignableToTypeOf(&github.IssueComment{})).
+			DoAndReturn(func(_ context.Context, _, _ string, _ int, comment *github.IssueComment) (*github.IssueComment, *github.Response, error) {
+				body := comment.GetBody()
+				assert.Contains(t, body, "| `/cherry-pick <branch>...` | Cherry picks the merged PR onto each branch. The results are kept up to date in one comment. | Org members, except blocked bots |")
+				assert.Contains(t, body, "| `/update-branch` | Merges the base branch into the PR branch. | @maintainer, except blocked bots |")
+				assert.Contains(t, body, "| `/help` |")
+				assert.NotContains(t, body, "/check-cla")
+				return nil, nil, nil
+			})
+
+		require.NoError(t, run("someone", "/help"))
+	})
+}
+
+func TestCommandHelp(t *testing.T) {
+	s := &Server{Config: &Config{}}
+
+	help := s.commandHelp("mattertest", "mattermod")
+	rows := 0
+	for _, line := range strings.Split(help, "\n") {
+		if strings.HasPrefix(line, "| `/") {
+			rows++
+		}
+	}
+	assert.Equal(t, len(commandDefinitions()), rows)
+	assert.Equal(t, "a \\| b", escapeTableCell("a | b"))
+}
@@ -17,6 +17,61 @@ import (
 
 const contributorLabel = "Contributor"
 
+// greetingAutomation welcomes community members opening a PR and hands the
+// PR to the greeting team of the repository.
+type greetingAutomation struct {
+	baseAutomation
+	s *Server
+}
+
+func (a *greetingAutomation) Name() string {
+	return "greeting"
+}
+
+func (a *greetingAutomation) OnPullRequest(ctx context.Context, event *pullRequestEvent, pr *model.PullRequest) error {
+	if event.Action != prEventOpened {
+		return nil
+	}
+
+	// The CLA is checked here rather than taken from the cla automation, so
+	// the welcome message doesn't depend on it running first, or at all.
+	claCommentNeeded := !a.s.IsOrgMember(pr.Username) && a.s.needsCLA(ctx, pr.Username)
+	errWelcome := a.s.postPRWelcomeMessage(ctx, pr, claCommentNeeded)
+
+	repo, ok := GetRepository(a.s.Config.Repositories, pr.RepoOwner, pr.RepoName)
+	if !ok {
+		return errWelcome
+	}
+
+	err := joinErrors(
+		errWelcome,
+		a.s.assignGreeter(ctx, pr, repo),
+		a.s.assignGreetingLabels(ctx, pr, repo),
+	)
+	// A retry would post the welcome message again.
+	if err != nil && errWelcome == nil && !a.s.IsOrgMember(pr.Username) {
+		return newPermanentError(err)
+	}
+	return err
+}
+
+// hacktoberfestAutomation labels PRs of community members opened in October.
+type hacktoberfestAutomation struct {
+	baseAutomation
+	s *Server
+}
+
+func (a *hacktoberfestAutomation) Name() string {
+	return "hacktoberfest"
+}
+
+func (a *hacktoberfestAutomation) OnPullRequest(ctx context.Context, event *pullRequestEvent, pr *model.PullRequest) error {
+	if event.Action == prEventOpened {
+		a.s.addHacktoberfestLabel(ctx, pr)
+	}
+	return nil
+}
+
 func (s *Server) addHacktoberfestLabel(ctx context.Context, pr *model.PullRequest) {
 	if pr.State == model.StateClosed {
 		return
@@ -22,6 +22,13 @@ type LabelResponse struct {
 	Message string
 }
 
+// WebhookSecret is a secret used to sign GitHub webhook requests. The ID
+// identifies the secret in logs and metrics, so it must not be the secret itself.
+type WebhookSecret struct {
+	ID     string
+	Secret string
+}
+
 type Repository struct {
 	Owner                      string
 	Name                       string
@@ -29,8 +36,39 @@ type Repository struct {
 	InstanceSetupScript        string
 	InstanceSetupUpgradeScript string
 	JobName                    string
-	GreetingTeam               string   // GreetingTeam is the GitHub team responsible for triaging non-member PRs for this repo.
-	GreetingLabels             []string // GreetingLabels are the labels applied automatically to non-member PRs for this repo.
+	GreetingTeam               string              // GreetingTeam is the GitHub team responsible for triaging non-member PRs for this repo.
+	GreetingLabels             []string            // GreetingLabels are the labels applied automatically to non-member PRs for this repo.
+	WebhookSecrets             []*WebhookSecret    // WebhookSecrets are accepted for webhooks of this repo in addition to the global ones.
+	Automations                map[string]bool     // Automations enables (true) or disables (false) automations by name. Unlisted automations are enabled.
+	CommandPermissions         map[string][]string // CommandPermissions maps command names to the roles allowed to run them. Unlisted commands keep their default roles.
+	CommandAcknowledgement     string              // CommandAcknowledgement is "comment" (default) to answer commands with comments, or "reaction" to react to the command instead.
+	CherryPick                 *CherryPickPolicy   // CherryPick configures the cherry picks of this repo. Unset fields keep their defaults.
+	Merge                      *MergePolicy        // Merge configures how PRs of this repo are merged by the AutoPRMergeLabel and /merge. Unset fields keep their defaults.
+}
+
+// CherryPickPolicy configures which PRs of a repository are cherry picked
+// onto which branch, and how the cherry pick PRs are set up.
+type CherryPickPolicy struct {
+	TriggerLabels    []string // TriggerLabels request the cherry pick of a PR onto the release branch of its milestone. Defaults to CherryPick/Approved.
+	DoneLabel        string   // DoneLabel replaces the trigger labels once the cherry pick PR is created. Defaults to CherryPick/Done.
+	ResultLabels     []string // ResultLabels are added to the cherry pick PRs. Defaults to AutomatedCherryPick, Changelog/Not Needed and Docs/Not Needed.
+	BranchTemplate   string   // BranchTemplate names the release branch of a milestone from its .Title and .Version, the title without the leading "v" and trailing ".0". Defaults to "release-{{.Version}}".
+	CloudMilestone   string   // CloudMilestone is the milestone of PRs cherry picked onto the cloud branch, which is the MainBranch of the repo in CloudRepositories or "cloud". Defaults to "cloud".
+	ReviewerStrategy string   // ReviewerStrategy is "approvers" (default) to ask an approver of the original PR other than its author to review the cherry pick PR, or "none".
+	ReleaseTeam      string   // ReleaseTeam is the slug of the org team asked to review cherry pick PRs whose original PR has no approver other than its author.
+	ReleaseBranches  string   // ReleaseBranches is the pattern of the release branch names. PRs merged into them are checked for an equivalent commit on the default branch. Defaults to "release-*".
+	ForwardPort      string   // ForwardPort is "label" (default) to add the ForwardPortLabel to PRs merged into a release branch only, "pr" to cherry pick them onto the default branch instead, or "none".
+	ForwardPortLabel string   // ForwardPortLabel marks PRs which still have to be forward ported. Defaults to ForwardPort/Needed.
+}
+
+// MergePolicy configures the merge method and the merge commit message of
+// the PRs of a repository. The templates are rendered from the .Title,
+// .Number, .Author, .CoAuthors and .Issues of the PR.
+type MergePolicy struct {
+	Method        string            // Method is "squash" (default), "merge" or "rebase".
+	MethodLabels  map[string]string // MethodLabels maps labels to the merge method of the PRs they are on, overriding Method.
+	TitleTemplate string            // TitleTemplate renders the title of the merge commit. Defaults to "{{.Title}} (#{{.Number}})".
+	BodyTemplate  string            // BodyTemplate renders the body of the merge commit. Defaults to a "Fixes" line per linked issue followed by a Co-authored-by trailer per co-author.
 }
 
 type CloudRepository struct {
@@ -49,13 +87,16 @@ type Integration struct {
 type Config struct {
 	ListenAddress               string
 	MattermodURL                string
-	GithubAccessToken           string
+	GithubAccessToken           string // Deprecated: use GitHubApp. Only used if GitHubApp is not set.
 	GitHubTokenReserve          int
 	GithubUsername              string
 	GithubEmail                 string
 	GitConfigMergeRenameLimit   string
-	GithubAccessTokenCherryPick string
-	GitHubWebhookSecret         string
+	GithubAccessTokenCherryPick string           // Deprecated: use GitHubApp. Only used if GitHubApp is not set.
+	GitHubApp                   *GitHubAppConfig // GitHubApp authenticates mattermod as a GitHub App instead of using access tokens.
+	GitHubWebhookSecret         string           // Deprecated: use GitHubWebhookSecrets. Still accepted with the ID "default".
+	GitHubWebhookSecrets        []*WebhookSecret // GitHubWebhookSecrets are all the secrets currently accepted for webhooks.
+	AdminToken                  string           // AdminToken authenticates requests to the /admin endpoints, which are disabled if empty.
 	Org                         string
 	Username                    string
 	AutoAssignerTeam            string
@@ -66,6 +107,9 @@ type Config struct {
 	DriverName string
 	DataSource string
 
+	WebhookWorkers     int // WebhookWorkers is the number of workers processing queued webhook deliveries.
+	WebhookMaxAttempts int // WebhookMaxAttempts is how many times a failing webhook delivery is processed before giving up.
+
 	Repositories      []*Repository
 	CloudRepositories []*CloudRepository
 
@@ -130,8 +174,8 @@ type Config struct {
 
 	MetricsServerPort string
 
-	RepoFolder    string // folder containing local checkouts of repositories for cherry-picking
-	ScriptsFolder string // folder containing the cherry-pick.sh script
+	RepoFolder        string // folder containing the mirrors of repositories and the worktrees for cherry-picking
+	CherryPickWorkers int    // CherryPickWorkers is the number of cherry picks run at the same time.
 }
 
 func findConfigFile(fileName string) string {
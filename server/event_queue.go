@@ -0,0 +1,359 @@
+// Copyright (c) 2017-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"time"
+
+	"github.com/google/go-github/v39/github"
+	"github.com/mattermost/mattermost-mattermod/model"
+	"github.com/mattermost/mattermost-server/v6/shared/mlog"
+)
+
+const (
+	defaultWebhookWorkers     = 4
+	defaultWebhookMaxAttempts = 5
+
+	webhookPollInterval   = 30 * time.Second
+	webhookPollBatchSize  = 100
+	webhookRetryBaseDelay = 30 * time.Second
+	webhookRetryMaxDelay  = 30 * time.Minute
+)
+
+// permanentError wraps errors which won't go away by processing the same
+// delivery again, like malformed payloads or commands that already reported
+// the failure back on GitHub.
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string {
+	return e.err.Error()
+}
+
+func (e *permanentError) Unwrap() error {
+	return e.err
+}
+
+func newPermanentError(err error) error {
+	return &permanentError{err: err}
+}
+
+// isQueuedEvent reports whether deliveries of the given event type are
+// persisted and processed by the webhook workers.
+func isQueuedEvent(eventType string) bool {
+	switch eventType {
+	case "issues", "issue_comment", "pull_request", "status", "check_run", "check_suite":
+		return true
+	}
+	return false
+}
+
+func (s *Server) webhookWorkers() int {
+	if s.Config.WebhookWorkers > 0 {
+		return s.Config.WebhookWorkers
+	}
+	return defaultWebhookWorkers
+}
+
+func (s *Server) webhookMaxAttempts() int {
+	if s.Config.WebhookMaxAttempts > 0 {
+		return s.Config.WebhookMaxAttempts
+	}
+	return defaultWebhookMaxAttempts
+}
+
+// startWebhookWorkers starts the pool processing stored webhook deliveries,
+// along with a poller picking up retries and deliveries which didn't fit
+// into the queue.
+func (s *Server) startWebhookWorkers() {
+	if err := s.Store.WebhookDelivery().ResetRunning(); err != nil {
+		mlog.Error("Failed to reset running webhook deliveries", mlog.Err(err))
+	}
+
+	for i := 0; i < s.webhookWorkers(); i++ {
+		s.webhookWorkersWG.Add(1)
+		go s.listenWebhookDeliveries()
+	}
+
+	s.webhookWorkersWG.Add(1)
+	go s.pollWebhookDeliveries()
+}
+
+// stopWebhookWorkers waits for the workers to finish their current delivery.
+// Deliveries left in the queue stay pending in the store and are picked up
+// again on the next start.
+func (s *Server) stopWebhookWorkers() {
+	close(s.webhookStopChan)
+	s.webhookWorkersWG.Wait()
+}
+
+// queueWebhookDelivery persists the delivery and hands it to the workers.
+// GitHub may redeliver the same event, in which case the stored delivery is
+// kept as it is.
+func (s *Server) queueWebhookDelivery(deliveryID, eventType string, payload []byte) error {
+	created, err := s.Store.WebhookDelivery().Create(&model.WebhookDelivery{
+		DeliveryID: deliveryID,
+		EventType:  eventType,
+		Payload:    payload,
+		State:      model.DeliveryStatePending,
+	})
+	if err != nil {
+		return err
+	}
+	if !created {
+		mlog.Info("Ignoring duplicate webhook delivery", mlog.String("delivery", deliveryID))
+		return nil
+	}
+
+	s.enqueueWebhookDelivery(deliveryID)
+	return nil
+}
+
+func (s *Server) enqueueWebhookDelivery(deliveryID string) {
+	select {
+	case s.webhookDeliveries <- deliveryID:
+	default:
+		mlog.Warn("Webhook delivery queue is full, leaving the delivery to the poller", mlog.String("delivery", deliveryID))
+	}
+}
+
+func (s *Server) listenWebhookDeliveries() {
+	defer s.webhookWorkersWG.Done()
+
+	for {
+		select {
+		case <-s.webhookStopChan:
+			return
+		case deliveryID := <-s.webhookDeliveries:
+			s.processWebhookDelivery(deliveryID)
+		}
+	}
+}
+
+func (s *Server) pollWebhookDeliveries() {
+	defer s.webhookWorkersWG.Done()
+
+	ticker := time.NewTicker(webhookPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.webhookStopChan:
+			return
+		case <-ticker.C:
+			deliveries, err := s.Store.WebhookDelivery().ListPending(time.Now(), webhookPollBatchSize)
+			if err != nil {
+				mlog.Error("Failed to list pending webhook deliveries", mlog.Err(err))
+				continue
+			}
+			for _, delivery := range deliveries {
+				select {
+				case <-s.webhookStopChan:
+					return
+				case s.webhookDeliveries <- delivery.DeliveryID:
+				}
+			}
+		}
+	}
+}
+
+func (s *Server) processWebhookDelivery(deliveryID string) {
+	claimed, err := s.Store.WebhookDelivery().Claim(deliveryID)
+	if err != nil {
+		mlog.Error("Failed to claim webhook delivery", mlog.String("delivery", deliveryID), mlog.Err(err))
+		return
+	}
+	if !claimed {
+		// Another worker got it first, or it's not pending anymore.
+		return
+	}
+
+	delivery, err := s.Store.WebhookDelivery().Get(deliveryID)
+	if err != nil {
+		mlog.Error("Failed to get claimed webhook delivery", mlog.String("delivery", deliveryID), mlog.Err(err))
+		// Left running, the delivery would only be picked up again after a
+		// restart.
+		nextAttemptAt := model.GetMillisForTime(time.Now().Add(webhookRetryBaseDelay))
+		if err = s.Store.WebhookDelivery().Release(deliveryID, err.Error(), nextAttemptAt); err != nil {
+			mlog.Error("Failed to release webhook delivery", mlog.String("delivery", deliveryID), mlog.Err(err))
+		}
+		return
+	}
+	if delivery == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), defaultRequestTimeout*time.Second)
+	defer cancel()
+
+	s.Metrics.IncreaseWebhookRequest(delivery.EventType)
+	err = s.handleDelivery(ctx, delivery)
+	if err == nil {
+		delivery.State = model.DeliveryStateSucceeded
+		delivery.LastError = ""
+	} else {
+		s.Metrics.IncreaseWebhookErrors(delivery.EventType)
+		delivery.LastError = err.Error()
+
+		var perr *permanentError
+		if errors.As(err, &perr) || delivery.Attempts >= s.webhookMaxAttempts() {
+			delivery.State = model.DeliveryStateFailed
+			mlog.Error("Webhook delivery failed",
+				mlog.String("delivery", deliveryID),
+				mlog.String("event", delivery.EventType),
+				mlog.Int("attempts", delivery.Attempts),
+				mlog.Err(err))
+		} else {
+			delivery.State = model.DeliveryStatePending
+			delivery.NextAttemptAt = model.GetMillisForTime(time.Now().Add(webhookRetryDelay(delivery.Attempts)))
+			mlog.Warn("Webhook delivery failed, will retry",
+				mlog.String("delivery", deliveryID),
+				mlog.String("event", delivery.EventType),
+				mlog.Int("attempts", delivery.Attempts),
+				mlog.Err(err))
+		}
+	}
+
+	if err = s.Store.WebhookDelivery().Update(delivery); err != nil {
+		mlog.Error("Failed to update webhook delivery", mlog.String("delivery", deliveryID), mlog.Err(err))
+	}
+}
+
+// handleDelivery runs the handler of the delivery. A panic fails the
+// delivery for good instead of taking down the server, which would only
+// process the same delivery again after the restart.
+func (s *Server) handleDelivery(ctx context.Context, delivery *model.WebhookDelivery) (err error) {
+	defer func() {
+		if x := recover(); x != nil {
+			mlog.Error("recovered from a panic",
+				mlog.String("delivery", delivery.DeliveryID),
+				mlog.Any("error", x),
+				mlog.String("stack", string(debug.Stack())))
+			err = newPermanentError(fmt.Errorf("panic: %v", x))
+		}
+	}()
+	return s.handleEvent(ctx, delivery.EventType, delivery.Payload)
+}
+
+// webhookRetryDelay doubles the delay for every failed attempt, up to webhookRetryMaxDelay.
+func webhookRetryDelay(attempts int) time.Duration {
+	delay := webhookRetryBaseDelay
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= webhookRetryMaxDelay {
+			return webhookRetryMaxDelay
+		}
+	}
+	return delay
+}
+
+// routedEvent is a parsed webhook payload along with the handler it is
+// dispatched to.
+type routedEvent struct {
+	// route names the handler, e.g. "issues/pr_from_issue".
+	route string
+	// summary identifies the object the event is about.
+	summary string
+	handle  func(ctx context.Context, s *Server) error
+}
+
+// routeEvent parses the payload for the given event type and decides which
+// handler processes it, without running the handler.
+func routeEvent(eventType string, payload []byte) (*routedEvent, error) {
+	switch eventType {
+	case "issues":
+		event, err := issueEventFromJSON(bytes.NewReader(payload))
+		if err != nil {
+			return nil, fmt.Errorf("could not parse issue event: %w", err)
+		}
+		summary := fmt.Sprintf("%s %s#%d", event.Action, event.Repo.GetFullName(), event.Issue.GetNumber())
+		// An issue can be both an issue or a PR. So we need to differentiate between the two.
+		if event.Issue.IsPullRequest() {
+			return &routedEvent{
+				route:   "issues/pr_from_issue",
+				summary: summary,
+				handle: func(ctx context.Context, s *Server) error {
+					mlog.Info("A PR event is found from an issue. Updating DB.", mlog.String("link", event.Issue.GetPullRequestLinks().GetHTMLURL()))
+					return s.prFromIssueHandler(ctx, event)
+				},
+			}, nil
+		}
+		return &routedEvent{
+			route:   "issues/issue",
+			summary: summary,
+			handle: func(ctx context.Context, s *Server) error {
+				return s.issueEventHandler(ctx, event)
+			},
+		}, nil
+	case "issue_comment":
+		event, err := issueCommentEventFromJSON(bytes.NewReader(payload))
+		if err != nil {
+			return nil, fmt.Errorf("could not parse pr comment event: %w", err)
+		}
+		return &routedEvent{
+			route:   "issue_comment",
+			summary: fmt.Sprintf("%s %s#%d", event.Action, event.Repository.GetFullName(), event.Issue.GetNumber()),
+			handle: func(ctx context.Context, s *Server) error {
+				return s.issueCommentEventHandler(ctx, event)
+			},
+		}, nil
+	case "pull_request":
+		event, err := pullRequestEventFromJSON(bytes.NewReader(payload))
+		if err != nil {
+			return nil, fmt.Errorf("could not parse pr event: %w", err)
+		}
+		return &routedEvent{
+			route:   "pull_request",
+			summary: fmt.Sprintf("%s %s#%d", event.Action, event.Repo.GetFullName(), event.PRNumber),
+			handle: func(ctx context.Context, s *Server) error {
+				return s.pullRequestEventHandler(ctx, event)
+			},
+		}, nil
+	case "status":
+		var event github.StatusEvent
+		if err := json.Unmarshal(payload, &event); err != nil {
+			return nil, fmt.Errorf("could not parse status event: %w", err)
+		}
+		return &routedEvent{
+			route:   "status",
+			summary: fmt.Sprintf("%s %s %s@%s", event.GetState(), event.GetContext(), event.GetRepo().GetFullName(), event.GetSHA()),
+			handle: func(ctx context.Context, s *Server) error {
+				return s.statusEventHandler(ctx, &event)
+			},
+		}, nil
+	case "check_run":
+		var event github.CheckRunEvent
+		if err := json.Unmarshal(payload, &event); err != nil {
+			return nil, fmt.Errorf("could not parse check run event: %w", err)
+		}
+		return &routedEvent{
+			route:   "check_run",
+			summary: fmt.Sprintf("%s %s %s@%s", event.GetAction(), event.GetCheckRun().GetName(), event.GetRepo().GetFullName(), event.GetCheckRun().GetHeadSHA()),
+			handle: func(ctx context.Context, s *Server) error {
+				return s.checkRunEventHandler(ctx, &event)
+			},
+		}, nil
+	case "check_suite":
+		var event github.CheckSuiteEvent
+		if err := json.Unmarshal(payload, &event); err != nil {
+			return nil, fmt.Errorf("could not parse check suite event: %w", err)
+		}
+		return &routedEvent{
+			route:   "check_suite",
+			summary: fmt.Sprintf("%s %s@%s", event.GetAction(), event.GetRepo().GetFullName(), event.GetCheckSuite().GetHeadSHA()),
+			handle: func(ctx context.Context, s *Server) error {
+				return s.checkSuiteEventHandler(ctx, &event)
+			},
+		}, nil
+	default:
+		return nil, fmt.Errorf("unhandled event type %q", eventType)
+	}
+}
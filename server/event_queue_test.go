@@ -0,0 +1,280 @@
+// Copyright (c) 2017-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package server
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/go-github/v39/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-mattermod/model"
+	"github.com/mattermost/mattermost-mattermod/server/mocks"
+	stmock "github.com/mattermost/mattermost-mattermod/store/mocks"
+)
+
+func TestGithubEvent(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	deliveryStoreMock := stmock.NewMockWebhookDeliveryStore(ctrl)
+	ss := stmock.NewMockStore(ctrl)
+	ss.EXPECT().
+		WebhookDelivery().
+		Return(deliveryStoreMock).
+		AnyTimes()
+
+	s := &Server{
+		Config:            &Config{},
+		Store:             ss,
+		webhookDeliveries: make(chan string, 1),
+	}
+
+	ts := httptest.NewServer(http.HandlerFunc(s.githubEvent))
+	defer ts.Close()
+
+	post := func(t *testing.T, eventType, deliveryID, body string) int {
+		req, err := http.NewRequest("POST", ts.URL, bytes.NewReader([]byte(body)))
+		require.NoError(t, err)
+		req.Header.Set("X-GitHub-Event", eventType)
+		if deliveryID != "" {
+			req.Header.Set("X-GitHub-Delivery", deliveryID)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	t.Run("Ping", func(t *testing.T) {
+		require.Equal(t, http.StatusOK, post(t, "ping", "", `{"hook_id": 1}`))
+	})
+
+	t.Run("Unhandled event type", func(t *testing.T) {
+		require.Equal(t, http.StatusNotImplemented, post(t, "deployment", "id", `{}`))
+	})
+
+	t.Run("Missing delivery id", func(t *testing.T) {
+		require.Equal(t, http.StatusBadRequest, post(t, "pull_request", "", `{}`))
+	})
+
+	t.Run("Invalid payload", func(t *testing.T) {
+		require.Equal(t, http.StatusBadRequest, post(t, "pull_request", "id", `{`))
+	})
+
+	t.Run("Store error", func(t *testing.T) {
+		deliveryStoreMock.EXPECT().
+			Create(gomock.Any()).
+			Return(false, errors.New("some-error"))
+
+		require.Equal(t, http.StatusInternalServerError, post(t, "pull_request", "id", `{}`))
+		require.Len(t, s.webhookDeliveries, 0)
+	})
+
+	t.Run("New delivery is queued", func(t *testing.T) {
+		deliveryStoreMock.EXPECT().
+			Create(gomock.Any()).
+			DoAndReturn(func(delivery *model.WebhookDelivery) (bool, error) {
+				assert.Equal(t, "id", delivery.DeliveryID)
+				assert.Equal(t, "pull_request", delivery.EventType)
+				assert.Equal(t, `{"action":"opened"}`, string(delivery.Payload))
+				assert.Equal(t, model.DeliveryStatePending, delivery.State)
+				return true, nil
+			})
+
+		require.Equal(t, http.StatusAccepted, post(t, "pull_request", "id", `{"action":"opened"}`))
+		require.Equal(t, "id", <-s.webhookDeliveries)
+	})
+
+	t.Run("Duplicate delivery is not queued again", func(t *testing.T) {
+		deliveryStoreMock.EXPECT().
+			Create(gomock.Any()).
+			Return(false, nil)
+
+		require.Equal(t, http.StatusAccepted, post(t, "pull_request", "id", `{}`))
+		require.Len(t, s.webhookDeliveries, 0)
+	})
+}
+
+func TestProcessWebhookDelivery(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctxInterface := reflect.TypeOf((*context.Context)(nil)).Elem()
+
+	deliveryStoreMock := stmock.NewMockWebhookDeliveryStore(ctrl)
+	ss := stmock.NewMockStore(ctrl)
+	ss.EXPECT().
+		WebhookDelivery().
+		Return(deliveryStoreMock).
+		AnyTimes()
+
+	metricsMock := mocks.NewMockMetricsProvider(ctrl)
+	metricsMock.EXPECT().IncreaseWebhookRequest(gomock.Any()).AnyTimes()
+	metricsMock.EXPECT().IncreaseWebhookErrors(gomock.Any()).AnyTimes()
+
+	prs := mocks.NewMockPullRequestsService(ctrl)
+	s := &Server{
+		Config: &Config{
+			WebhookMaxAttempts: 3,
+		},
+		Store:        ss,
+		Metrics:      metricsMock,
+		GithubClient: &GithubClient{PullRequests: prs},
+	}
+
+	// A comment event makes the handler fetch the PR from GitHub, which
+	// lets the tests decide whether processing fails.
+	payload := []byte(`{
+		"action": "created",
+		"comment": {"body": "some-text"},
+		"issue": {"number": 1, "pull_request": {}},
+		"repository": {"name": "mattermod", "owner": {"login": "mattertest"}}
+	}`)
+
+	t.Run("Not claimed", func(t *testing.T) {
+		deliveryStoreMock.EXPECT().Claim("id").Return(false, nil)
+
+		s.processWebhookDelivery("id")
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		deliveryStoreMock.EXPECT().Claim("id").Return(true, nil)
+		deliveryStoreMock.EXPECT().Get("id").Return(&model.WebhookDelivery{
+			DeliveryID: "id",
+			EventType:  "issue_comment",
+			Payload:    []byte(`{"action": "deleted", "comment": {}, "issue": {"pull_request": {}}, "repository": {}}`),
+			State:      model.DeliveryStateRunning,
+			LastError:  "previous error",
+			Attempts:   2,
+		}, nil)
+		deliveryStoreMock.EXPECT().
+			Update(gomock.Any()).
+			DoAndReturn(func(delivery *model.WebhookDelivery) error {
+				assert.Equal(t, model.DeliveryStateSucceeded, delivery.State)
+				assert.Empty(t, delivery.LastError)
+				return nil
+			})
+
+		s.processWebhookDelivery("id")
+	})
+
+	t.Run("Failure is retried", func(t *testing.T) {
+		prs.EXPECT().
+			Get(gomock.AssignableToTypeOf(ctxInterface), "mattertest", "mattermod", 1).
+			Return(nil, nil, errors.New("some-error"))
+
+		deliveryStoreMock.EXPECT().Claim("id").Return(true, nil)
+		deliveryStoreMock.EXPECT().Get("id").Return(&model.WebhookDelivery{
+			DeliveryID: "id",
+			EventType:  "issue_comment",
+			Payload:    payload,
+			State:      model.DeliveryStateRunning,
+			Attempts:   1,
+		}, nil)
+		deliveryStoreMock.EXPECT().
+			Update(gomock.Any()).
+			DoAndReturn(func(delivery *model.WebhookDelivery) error {
+				assert.Equal(t, model.DeliveryStatePending, delivery.State)
+				assert.Contains(t, delivery.LastError, "some-error")
+				assert.Greater(t, delivery.NextAttemptAt, model.GetMillis())
+				return nil
+			})
+
+		s.processWebhookDelivery("id")
+	})
+
+	t.Run("Failure after max attempts", func(t *testing.T) {
+		prs.EXPECT().
+			Get(gomock.AssignableToTypeOf(ctxInterface), "mattertest", "mattermod", 1).
+			Return(nil, nil, errors.New("some-error"))
+
+		deliveryStoreMock.EXPECT().Claim("id").Return(true, nil)
+		deliveryStoreMock.EXPECT().Get("id").Return(&model.WebhookDelivery{
+			DeliveryID: "id",
+			EventType:  "issue_comment",
+			Payload:    payload,
+			State:      model.DeliveryStateRunning,
+			Attempts:   3,
+		}, nil)
+		deliveryStoreMock.EXPECT().
+			Update(gomock.Any()).
+			DoAndReturn(func(delivery *model.WebhookDelivery) error {
+				assert.Equal(t, model.DeliveryStateFailed, delivery.State)
+				return nil
+			})
+
+		s.processWebhookDelivery("id")
+	})
+
+	t.Run("Panics fail the delivery", func(t *testing.T) {
+		prs.EXPECT().
+			Get(gomock.AssignableToTypeOf(ctxInterface), "mattertest", "mattermod", 1).
+			DoAndReturn(func(context.Context, string, string, int) (*github.PullRequest, *github.Response, error) {
+				panic("some-panic")
+			})
+
+		deliveryStoreMock.EXPECT().Claim("id").Return(true, nil)
+		deliveryStoreMock.EXPECT().Get("id").Return(&model.WebhookDelivery{
+			DeliveryID: "id",
+			EventType:  "issue_comment",
+			Payload:    payload,
+			State:      model.DeliveryStateRunning,
+			Attempts:   1,
+		}, nil)
+		deliveryStoreMock.EXPECT().
+			Update(gomock.Any()).
+			DoAndReturn(func(delivery *model.WebhookDelivery) error {
+				assert.Equal(t, model.DeliveryStateFailed, delivery.State)
+				assert.Equal(t, "panic: some-panic", delivery.LastError)
+				return nil
+			})
+
+		s.processWebhookDelivery("id")
+	})
+
+	t.Run("Deliveries failing to load are released", func(t *testing.T) {
+		deliveryStoreMock.EXPECT().Claim("id").Return(true, nil)
+		deliveryStoreMock.EXPECT().Get("id").Return(nil, errors.New("some-error"))
+		deliveryStoreMock.EXPECT().Release("id", "some-error", gomock.Any()).Return(nil)
+
+		s.processWebhookDelivery("id")
+	})
+
+	t.Run("Malformed payload is not retried", func(t *testing.T) {
+		deliveryStoreMock.EXPECT().Claim("id").Return(true, nil)
+		deliveryStoreMock.EXPECT().Get("id").Return(&model.WebhookDelivery{
+			DeliveryID: "id",
+			EventType:  "issue_comment",
+			Payload:    []byte(`{"action": "created"}`),
+			State:      model.DeliveryStateRunning,
+			Attempts:   1,
+		}, nil)
+		deliveryStoreMock.EXPECT().
+			Update(gomock.Any()).
+			DoAndReturn(func(delivery *model.WebhookDelivery) error {
+				assert.Equal(t, model.DeliveryStateFailed, delivery.State)
+				assert.NotEmpty(t, delivery.LastError)
+				return nil
+			})
+
+		s.processWebhookDelivery("id")
+	})
+}
+
+func TestWebhookRetryDelay(t *testing.T) {
+	assert.Equal(t, 30*time.Second, webhookRetryDelay(1))
+	assert.Equal(t, time.Minute, webhookRetryDelay(2))
+	assert.Equal(t, 2*time.Minute, webhookRetryDelay(3))
+	assert.Equal(t, webhookRetryMaxDelay, webhookRetryDelay(10))
+}
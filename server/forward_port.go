@@ -0,0 +1,138 @@
+// Copyright (c) 2017-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package server
+
+import (
+	"context"
+	"fmt"
+	"path"
+
+	"github.com/mattermost/mattermost-mattermod/model"
+	"github.com/mattermost/mattermost-server/v6/shared/mlog"
+)
+
+// forwardPortAutomation notices fixes which were merged into a release
+// branch without making it to the default branch.
+type forwardPortAutomation struct {
+	baseAutomation
+	s *Server
+}
+
+func (a *forwardPortAutomation) Name() string {
+	return "forward-port"
+}
+
+func (a *forwardPortAutomation) OnPullRequest(ctx context.Context, event *pullRequestEvent, pr *model.PullRequest) error {
+	if event.Action != prEventClosed {
+		return nil
+	}
+	return a.s.checkForwardPort(ctx, pr, event.PullRequest.GetBase().GetRef(), eventDefaultBranch(event))
+}
+
+// eventDefaultBranch returns the default branch of the repository of the
+// event.
+func eventDefaultBranch(event *pullRequestEvent) string {
+	if branch := event.Repo.GetDefaultBranch(); branch != "" {
+		return branch
+	}
+	if branch := event.PullRequest.GetBase().GetRepo().GetDefaultBranch(); branch != "" {
+		return branch
+	}
+	return "master"
+}
+
+// checkForwardPort queues the forward port check of a PR merged into a
+// release branch on the cherry pick workers. Cherry pick PRs are skipped,
+// they come from another branch already.
+func (s *Server) checkForwardPort(ctx context.Context, pr *model.PullRequest, base, defaultBranch string) error {
+	if !pr.GetMerged() || pr.MergeCommitSHA == "" || s.Config.RepoFolder == "" || base == defaultBranch {
+		return nil
+	}
+	policy := s.cherryPickPolicy(pr.RepoOwner, pr.RepoName)
+	if policy.ForwardPort == forwardPortNone {
+		return nil
+	}
+	isRelease, err := path.Match(policy.ReleaseBranches, base)
+	if err != nil {
+		return fmt.Errorf("invalid release branch pattern %q: %w", policy.ReleaseBranches, err)
+	}
+	if !isRelease {
+		return nil
+	}
+
+	backport, err := s.Store.Backport().GetByBackport(pr.RepoOwner, pr.RepoName, pr.Number)
+	if err != nil {
+		return err
+	}
+	if backport != nil {
+		return nil
+	}
+	return s.queueCherryPickJob(pr, model.CherryPickJobKindForwardPort, defaultBranch)
+}
+
+// runForwardPort looks for the changes of the merged PR on the default branch.
+// If some are missing, the PR is labeled or cherry picked onto the default
+// branch, depending on the policy of the repository.
+func (s *Server) runForwardPort(ctx context.Context, pr *model.PullRequest, defaultBranch string) error {
+	missing, err := s.missingForwardPortCommits(ctx, pr, defaultBranch)
+	if err != nil {
+		return fmt.Errorf("could not look for the changes of #%d on %s: %w", pr.Number, defaultBranch, err)
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+	mlog.Info("PR merged into a release branch has to be forward ported",
+		mlog.String("repo", pr.RepoOwner+"/"+pr.RepoName),
+		mlog.Int("pr", pr.Number),
+		mlog.Int("missing_commits", len(missing)))
+
+	policy := s.cherryPickPolicy(pr.RepoOwner, pr.RepoName)
+	if policy.ForwardPort == forwardPortPR {
+		return s.queueCherryPicks(ctx, pr, []string{defaultBranch}, 0)
+	}
+	_, _, err = s.GithubClient.Issues.AddLabelsToIssue(ctx, pr.RepoOwner, pr.RepoName, pr.Number, []string{policy.ForwardPortLabel})
+	if err != nil {
+		return fmt.Errorf("could not label #%d with %s: %w", pr.Number, policy.ForwardPortLabel, err)
+	}
+	return nil
+}
+
+// missingForwardPortCommits returns the commits the merged PR landed which
+// have no equivalent on the default branch. The commits of merge commits are
+// looked up one by one.
+func (s *Server) missingForwardPortCommits(ctx context.Context, pr *model.PullRequest, defaultBranch string) ([]string, error) {
+	head := pullHeadRef(pr.Number)
+	mirror, worktree, err := s.addCherryPickWorktree(ctx, pr, defaultBranch, "+"+head+":"+head)
+	if err != nil {
+		return nil, err
+	}
+	defer s.removeCherryPickWorktree(pr, mirror, worktree)
+
+	commits, mainline, err := mergedCommits(ctx, worktree, pr.MergeCommitSHA, head)
+	if err != nil {
+		return nil, err
+	}
+	if mainline {
+		merged, logErr := logCommits(ctx, worktree, "--no-merges", pr.MergeCommitSHA+"^1.."+pr.MergeCommitSHA)
+		if logErr != nil {
+			return nil, logErr
+		}
+		commits = nil
+		for _, commit := range merged {
+			commits = append(commits, commit.sha)
+		}
+	}
+
+	var missing []string
+	for _, commit := range commits {
+		found, findErr := hasEquivalentCommit(ctx, worktree, defaultBranch, commit)
+		if findErr != nil {
+			return nil, findErr
+		}
+		if !found {
+			missing = append(missing, commit)
+		}
+	}
+	return missing, nil
+}
@@ -0,0 +1,193 @@
+// Copyright (c) 2017-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package server
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/go-github/v39/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-mattermod/model"
+	"github.com/mattermost/mattermost-mattermod/server/mocks"
+	stmock "github.com/mattermost/mattermost-mattermod/store/mocks"
+)
+
+func TestHasEquivalentCommit(t *testing.T) {
+	t.Setenv("GIT_AUTHOR_NAME", "mattermod")
+	t.Setenv("GIT_AUTHOR_EMAIL", "mattermod@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "mattermod")
+	t.Setenv("GIT_COMMITTER_EMAIL", "mattermod@example.com")
+
+	ctx := context.Background()
+	work := t.TempDir()
+	git := func(args ...string) string {
+		out, err := runGit(ctx, work, args...)
+		require.NoError(t, err, out)
+		return strings.TrimSpace(out)
+	}
+	commit := func(name, message string) string {
+		require.NoError(t, os.WriteFile(filepath.Join(work, name), []byte(name+"\n"), 0600))
+		git("add", name)
+		git("commit", "--quiet", "-m", message)
+		return git("rev-parse", "HEAD")
+	}
+
+	git("init", "--quiet", "--initial-branch=master")
+	initial := commit("app.go", "Initial commit")
+	git("branch", "release-7.1")
+
+	fromMaster := commit("master.go", "Fix on master")
+	git("checkout", "--quiet", "release-7.1")
+	releaseOnly := commit("release.go", "Fix on the release branch only")
+	forwardPorted := commit("forward.go", "Fix forward ported with a trailer")
+	samePatch := commit("same.go", "Fix forward ported by hand")
+	git("cherry-pick", "-x", fromMaster)
+	backported := git("rev-parse", "HEAD")
+
+	git("checkout", "--quiet", "master")
+	git("cherry-pick", "-x", forwardPorted)
+	commit("same.go", "Same fix, other message")
+	git("update-ref", "refs/remotes/upstream/master", "master")
+
+	for name, tc := range map[string]struct {
+		commit   string
+		expected bool
+	}{
+		"Commits on the branch":                 {commit: initial, expected: true},
+		"Commits without equivalent":            {commit: releaseOnly, expected: false},
+		"Commits cherry picked onto the branch": {commit: forwardPorted, expected: true},
+		"Commits cherry picked from the branch": {commit: backported, expected: true},
+		"Commits with the same patch on branch": {commit: samePatch, expected: true},
+	} {
+		t.Run(name, func(t *testing.T) {
+			found, err := hasEquivalentCommit(ctx, work, "master", tc.commit)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, found)
+		})
+	}
+}
+
+func TestCheckForwardPort(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repoFolder := t.TempDir()
+	upstream, sha := setupCherryPickRepos(t, repoFolder, "mattermost", "repo-name")
+	out, err := runGit(context.Background(), upstream, "rev-parse", "release-7.2")
+	require.NoError(t, err, out)
+	releaseSHA := strings.TrimSpace(out)
+
+	ctxInterface := reflect.TypeOf((*context.Context)(nil)).Elem()
+	jobStore := stmock.NewMockCherryPickJobStore(ctrl)
+	backportStore := stmock.NewMockBackportStore(ctrl)
+	ss := stmock.NewMockStore(ctrl)
+	ss.EXPECT().CherryPickJob().Return(jobStore).AnyTimes()
+	ss.EXPECT().Backport().Return(backportStore).AnyTimes()
+	is := mocks.NewMockIssuesService(ctrl)
+
+	s := &Server{
+		Config:             &Config{RepoFolder: repoFolder},
+		Store:              ss,
+		GithubClient:       &GithubClient{Issues: is},
+		stickyComments:     map[string]*stickyComment{"mattermost/repo-name#123 " + cherryPickReportMarker: {id: 7}},
+		cherryPickWakeChan: make(chan struct{}, 1),
+	}
+	mergedPR := func(mergeSHA string) *model.PullRequest {
+		return &model.PullRequest{
+			RepoOwner:      "mattermost",
+			RepoName:       "repo-name",
+			Number:         123,
+			Ref:            "fix",
+			Merged:         NewBool(true),
+			MergeCommitSHA: mergeSHA,
+		}
+	}
+
+	t.Run("PRs merged into other branches are ignored", func(t *testing.T) {
+		require.NoError(t, s.checkForwardPort(context.Background(), mergedPR(sha), "master", "master"))
+		require.NoError(t, s.checkForwardPort(context.Background(), mergedPR(sha), "feature", "master"))
+	})
+
+	t.Run("Cherry pick PRs are ignored", func(t *testing.T) {
+		backportStore.EXPECT().GetByBackport("mattermost", "repo-name", 123).Return(&model.Backport{Number: 100}, nil)
+
+		require.NoError(t, s.checkForwardPort(context.Background(), mergedPR(releaseSHA), "release-7.2", "master"))
+	})
+
+	t.Run("PRs merged into release branches are checked by a job", func(t *testing.T) {
+		backportStore.EXPECT().GetByBackport("mattermost", "repo-name", 123).Return(nil, nil)
+		jobStore.EXPECT().ListByPR("mattermost", "repo-name", 123).Return(nil, nil)
+		jobStore.EXPECT().Create(&model.CherryPickJob{
+			Kind:           model.CherryPickJobKindForwardPort,
+			RepoOwner:      "mattermost",
+			RepoName:       "repo-name",
+			Number:         123,
+			Branch:         "master",
+			MergeCommitSHA: releaseSHA,
+			State:          model.CherryPickJobPending,
+		}).Return(nil)
+
+		require.NoError(t, s.checkForwardPort(context.Background(), mergedPR(releaseSHA), "release-7.2", "master"))
+		require.Len(t, s.cherryPickWakeChan, 1)
+		<-s.cherryPickWakeChan
+	})
+
+	t.Run("PRs whose changes are on the default branch are left alone", func(t *testing.T) {
+		require.NoError(t, s.runForwardPort(context.Background(), mergedPR(sha), "master"))
+	})
+
+	t.Run("PRs which weren't forward ported are labeled", func(t *testing.T) {
+		is.EXPECT().AddLabelsToIssue(gomock.AssignableToTypeOf(ctxInterface), "mattermost", "repo-name", 123, []string{"ForwardPort/Needed"}).Return(nil, nil, nil)
+
+		require.NoError(t, s.runForwardPort(context.Background(), mergedPR(releaseSHA), "master"))
+	})
+
+	t.Run("PRs which weren't forward ported are cherry picked if configured", func(t *testing.T) {
+		s.Config.Repositories = []*Repository{
+			{Owner: "mattermost", Name: "repo-name", CherryPick: &CherryPickPolicy{ForwardPort: forwardPortPR}},
+		}
+		defer func() { s.Config.Repositories = nil }()
+
+		jobStore.EXPECT().ListByPR("mattermost", "repo-name", 123).Return(nil, nil).Times(2)
+		jobStore.EXPECT().Create(&model.CherryPickJob{
+			RepoOwner:      "mattermost",
+			RepoName:       "repo-name",
+			Number:         123,
+			Branch:         "master",
+			MergeCommitSHA: releaseSHA,
+			State:          model.CherryPickJobPending,
+		}).Return(nil)
+		backportStore.EXPECT().Save(gomock.AssignableToTypeOf(&model.Backport{})).Return(nil)
+		backportStore.EXPECT().ListByPR("mattermost", "repo-name", 123).Return(nil, nil)
+		is.EXPECT().EditComment(gomock.AssignableToTypeOf(ctxInterface), "mattermost", "repo-name", int64(7), gomock.AssignableToTypeOf(&github.IssueComment{})).Return(nil, nil, nil)
+
+		require.NoError(t, s.runForwardPort(context.Background(), mergedPR(releaseSHA), "master"))
+		require.Len(t, s.cherryPickWakeChan, 1)
+	})
+
+	t.Run("Nothing is checked with the none policy", func(t *testing.T) {
+		s.Config.Repositories = []*Repository{
+			{Owner: "mattermost", Name: "repo-name", CherryPick: &CherryPickPolicy{ForwardPort: forwardPortNone}},
+		}
+		defer func() { s.Config.Repositories = nil }()
+
+		require.NoError(t, s.checkForwardPort(context.Background(), mergedPR(releaseSHA), "release-7.2", "master"))
+	})
+}
+
+func TestEventDefaultBranch(t *testing.T) {
+	assert.Equal(t, "main", eventDefaultBranch(&pullRequestEvent{Repo: &github.Repository{DefaultBranch: github.String("main")}}))
+	assert.Equal(t, "develop", eventDefaultBranch(&pullRequestEvent{
+		PullRequest: &github.PullRequest{Base: &github.PullRequestBranch{Repo: &github.Repository{DefaultBranch: github.String("develop")}}},
+	}))
+	assert.Equal(t, "master", eventDefaultBranch(&pullRequestEvent{}))
+}
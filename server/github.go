@@ -19,6 +19,7 @@ const (
 	statePending       = "pending"
 	stateSuccess       = "success"
 	stateError         = "error"
+	stateFailure       = "failure"
 	prEventOpened      = "opened"
 	prEventReOpened    = "reopened"
 	prEventLabeled     = "labeled"
@@ -48,40 +49,25 @@ func (s *Server) GetPullRequestFromGithub(ctx context.Context, pullRequest *gith
 
 	pr.FullName = pullRequest.GetHead().GetRepo().GetFullName()
 
-	repo, ok := GetRepository(s.Config.Repositories, pr.RepoOwner, pr.RepoName)
-	if ok && repo.BuildStatusContext != "" {
-		combined, _, err := s.GithubClient.Repositories.GetCombinedStatus(ctx, pr.RepoOwner, pr.RepoName, pr.Sha, nil)
-		if err != nil {
-			return nil, err
-		}
-
-		for _, status := range combined.Statuses {
-			if status.GetContext() == repo.BuildStatusContext {
-				pr.BuildStatus = status.GetState()
-				pr.BuildLink = status.GetTargetURL()
-				break
-			}
-		}
-
-		// for the repos using circleci we have the checks now
-		checks, _, err := s.GithubClient.Checks.ListCheckRunsForRef(ctx, pr.RepoOwner, pr.RepoName, pr.Sha, nil)
-		if err != nil {
-			return nil, err
-		}
-
-		for _, status := range checks.CheckRuns {
-			if status.GetName() == repo.BuildStatusContext {
-				pr.BuildStatus = status.GetStatus()
-				pr.BuildConclusion = status.GetConclusion()
-				pr.BuildLink = status.GetHTMLURL()
-				break
-			}
-		}
+	// The build status is kept up to date by the status and check_run
+	// webhooks. It's only fetched when we haven't seen the head commit yet,
+	// in case its events arrived before the PR was stored.
+	oldPr, err := s.Store.PullRequest().Get(pr.RepoOwner, pr.RepoName, pr.Number)
+	if err != nil {
+		return nil, err
+	}
+	if oldPr != nil && oldPr.Sha == pr.Sha {
+		pr.BuildStatus = oldPr.BuildStatus
+		pr.BuildConclusion = oldPr.BuildConclusion
+		pr.BuildLink = oldPr.BuildLink
+	} else if err = s.fetchBuildStatus(ctx, pr); err != nil {
+		return nil, err
 	}
 
 	// if is opened it might not have any label yet
 	if action != prEventOpened {
-		labels, _, err := s.GithubClient.Issues.ListLabelsByIssue(ctx, pr.RepoOwner, pr.RepoName, pr.Number, nil)
+		var labels []*github.Label
+		labels, _, err = s.GithubClient.Issues.ListLabelsByIssue(ctx, pr.RepoOwner, pr.RepoName, pr.Number, nil)
 		if err != nil {
 			return nil, err
 		}
@@ -89,7 +75,7 @@ func (s *Server) GetPullRequestFromGithub(ctx context.Context, pullRequest *gith
 		pr.Labels = labelsToStringArray(labels)
 	}
 
-	if _, err := s.Store.PullRequest().Save(pr); err != nil {
+	if _, err = s.Store.PullRequest().Save(pr); err != nil {
 		return nil, err
 	}
 
@@ -197,6 +183,77 @@ func (s *Server) getFiles(ctx context.Context, repoOwner, repoName string, issue
 	return allFiles, nil
 }
 
+func (s *Server) getReviews(ctx context.Context, repoOwner, repoName string, number int) ([]*github.PullRequestReview, error) {
+	opts := &github.ListOptions{
+		PerPage: 100,
+	}
+	var allReviews []*github.PullRequestReview
+
+	for {
+		reviews, r, err := s.GithubClient.PullRequests.ListReviews(ctx, repoOwner, repoName, number, opts)
+		if err != nil {
+			return nil, err
+		}
+		allReviews = append(allReviews, reviews...)
+		if r != nil && r.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("failed fetching reviews: got http status: %s", r.Status)
+		}
+		if r.NextPage == 0 {
+			break
+		}
+		opts.Page = r.NextPage
+	}
+	return allReviews, nil
+}
+
+func (s *Server) getCommits(ctx context.Context, repoOwner, repoName string, number int) ([]*github.RepositoryCommit, error) {
+	opts := &github.ListOptions{
+		PerPage: 100,
+	}
+	var allCommits []*github.RepositoryCommit
+
+	for {
+		commits, r, err := s.GithubClient.PullRequests.ListCommits(ctx, repoOwner, repoName, number, opts)
+		if err != nil {
+			return nil, err
+		}
+		allCommits = append(allCommits, commits...)
+		if r != nil && r.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("failed fetching commits: got http status: %s", r.Status)
+		}
+		if r.NextPage == 0 {
+			break
+		}
+		opts.Page = r.NextPage
+	}
+	return allCommits, nil
+}
+
+func (s *Server) getCheckRuns(ctx context.Context, repoOwner, repoName, ref string) ([]*github.CheckRun, error) {
+	opts := &github.ListCheckRunsOptions{
+		ListOptions: github.ListOptions{
+			PerPage: 100,
+		},
+	}
+	var allRuns []*github.CheckRun
+
+	for {
+		runs, r, err := s.GithubClient.Checks.ListCheckRunsForRef(ctx, repoOwner, repoName, ref, opts)
+		if err != nil {
+			return nil, err
+		}
+		allRuns = append(allRuns, runs.CheckRuns...)
+		if r != nil && r.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("failed fetching check runs: got http status: %s", r.Status)
+		}
+		if r.NextPage == 0 {
+			break
+		}
+		opts.Page = r.NextPage
+	}
+	return allRuns, nil
+}
+
 func (s *Server) GetUpdateChecks(ctx context.Context, owner, repoName string, prNumber int) (*model.PullRequest, error) {
 	prGitHub, _, err := s.GithubClient.PullRequests.Get(ctx, owner, repoName, prNumber)
 	if err != nil {
@@ -0,0 +1,299 @@
+// Copyright (c) 2017-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package server
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/go-github/v39/github"
+)
+
+const (
+	// appJWTLifetime is how long the JWTs used to authenticate as the app are
+	// valid. GitHub rejects anything longer than 10 minutes.
+	appJWTLifetime = 9 * time.Minute
+	// appJWTClockDrift is subtracted from the issue time of the JWTs to allow
+	// for clock drift between us and GitHub.
+	appJWTClockDrift = time.Minute
+	// installationTokenRefreshMargin is how long before expiry cached
+	// installation tokens are replaced by new ones.
+	installationTokenRefreshMargin = 5 * time.Minute
+)
+
+// GitHubAppConfig configures authenticating against GitHub as a GitHub App.
+type GitHubAppConfig struct {
+	AppID          int64
+	PrivateKeyPath string
+	// InstallationIDs maps repository owners to the installation of the app.
+	// Owners missing from the map are looked up through the API.
+	InstallationIDs map[string]int64
+}
+
+// GitHubApp mints installation tokens for a GitHub App. Tokens are cached per
+// repository owner and refreshed shortly before they expire.
+type GitHubApp struct {
+	appID        int64
+	key          *rsa.PrivateKey
+	defaultOwner string
+	// client is authenticated as the app itself, using a JWT.
+	client *github.Client
+	now    func() time.Time
+
+	// mut guards the maps. It is never held during requests to GitHub.
+	mut           sync.Mutex
+	installations map[string]int64
+	tokens        map[string]*github.InstallationToken
+	// ownerLocks serialize refreshing the token of an owner, so that
+	// concurrent requests wait for one new token instead of each minting one.
+	ownerLocks map[string]*sync.Mutex
+}
+
+// NewGitHubApp returns a GitHubApp for the given config. Requests not tied to
+// a repository owner use the installation of defaultOwner. The base transport
+// is used for all requests made to GitHub.
+func NewGitHubApp(cfg *GitHubAppConfig, defaultOwner string, base http.RoundTripper) (*GitHubApp, error) {
+	pemBytes, err := os.ReadFile(cfg.PrivateKeyPath)
+	if err != nil {
+		return nil, fmt.Errorf("could not read the GitHub App private key: %w", err)
+	}
+	key, err := parseRSAPrivateKey(pemBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	return newGitHubApp(cfg, key, defaultOwner, base), nil
+}
+
+func newGitHubApp(cfg *GitHubAppConfig, key *rsa.PrivateKey, defaultOwner string, base http.RoundTripper) *GitHubApp {
+	app := &GitHubApp{
+		appID:         cfg.AppID,
+		key:           key,
+		defaultOwner:  defaultOwner,
+		now:           time.Now,
+		installations: make(map[string]int64),
+		tokens:        make(map[string]*github.InstallationToken),
+		ownerLocks:    make(map[string]*sync.Mutex),
+	}
+	for owner, id := range cfg.InstallationIDs {
+		app.installations[strings.ToLower(owner)] = id
+	}
+	app.client = github.NewClient(&http.Client{Transport: &appJWTTransport{base: base, app: app}})
+
+	return app
+}
+
+func parseRSAPrivateKey(pemBytes []byte) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode(pemBytes)
+	if block == nil {
+		return nil, errors.New("GitHub App private key is not PEM encoded")
+	}
+
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse the GitHub App private key: %w", err)
+	}
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("GitHub App private key is not an RSA key")
+	}
+	return key, nil
+}
+
+// JWT returns a JWT authenticating requests as the app itself.
+func (a *GitHubApp) JWT() (string, error) {
+	now := a.now()
+	header, err := json.Marshal(map[string]string{"alg": "RS256", "typ": "JWT"})
+	if err != nil {
+		return "", err
+	}
+	claims, err := json.Marshal(map[string]interface{}{
+		"iat": now.Add(-appJWTClockDrift).Unix(),
+		"exp": now.Add(appJWTLifetime).Unix(),
+		"iss": strconv.FormatInt(a.appID, 10),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	enc := base64.RawURLEncoding
+	unsigned := enc.EncodeToString(header) + "." + enc.EncodeToString(claims)
+	digest := sha256.Sum256([]byte(unsigned))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, a.key, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", fmt.Errorf("could not sign the GitHub App JWT: %w", err)
+	}
+
+	return unsigned + "." + enc.EncodeToString(signature), nil
+}
+
+// Slug returns the slug of the app. Comments of the app are made by the
+// "<slug>[bot]" user.
+func (a *GitHubApp) Slug(ctx context.Context) (string, error) {
+	app, _, err := a.client.Apps.Get(ctx, "")
+	if err != nil {
+		return "", fmt.Errorf("could not get the GitHub App: %w", err)
+	}
+	return app.GetSlug(), nil
+}
+
+// Token returns an installation token for the given repository owner. It
+// falls back to the default owner if owner is empty.
+func (a *GitHubApp) Token(ctx context.Context, owner string) (string, error) {
+	if owner == "" {
+		owner = a.defaultOwner
+	}
+	owner = strings.ToLower(owner)
+
+	if token, ok := a.cachedToken(owner); ok {
+		return token, nil
+	}
+
+	lock := a.ownerLock(owner)
+	lock.Lock()
+	defer lock.Unlock()
+
+	// Another request may have refreshed the token meanwhile.
+	if token, ok := a.cachedToken(owner); ok {
+		return token, nil
+	}
+
+	id, err := a.installationID(ctx, owner)
+	if err != nil {
+		return "", err
+	}
+
+	token, _, err := a.client.Apps.CreateInstallationToken(ctx, id, nil)
+	if err != nil {
+		return "", fmt.Errorf("could not create an installation token for %s: %w", owner, err)
+	}
+	a.mut.Lock()
+	a.tokens[owner] = token
+	a.mut.Unlock()
+
+	return token.GetToken(), nil
+}
+
+// cachedToken returns the cached token of the owner, unless it is about to
+// expire.
+func (a *GitHubApp) cachedToken(owner string) (string, bool) {
+	a.mut.Lock()
+	defer a.mut.Unlock()
+
+	token, ok := a.tokens[owner]
+	if !ok || !a.now().Add(installationTokenRefreshMargin).Before(token.GetExpiresAt()) {
+		return "", false
+	}
+	return token.GetToken(), true
+}
+
+// ownerLock returns the lock serializing the token refreshes of the owner.
+func (a *GitHubApp) ownerLock(owner string) *sync.Mutex {
+	a.mut.Lock()
+	defer a.mut.Unlock()
+
+	if _, ok := a.ownerLocks[owner]; !ok {
+		a.ownerLocks[owner] = &sync.Mutex{}
+	}
+	return a.ownerLocks[owner]
+}
+
+// installationID must be called with the lock of the owner held.
+func (a *GitHubApp) installationID(ctx context.Context, owner string) (int64, error) {
+	a.mut.Lock()
+	id, ok := a.installations[owner]
+	a.mut.Unlock()
+	if ok {
+		return id, nil
+	}
+
+	installation, resp, err := a.client.Apps.FindOrganizationInstallation(ctx, owner)
+	if resp != nil && resp.StatusCode == http.StatusNotFound {
+		installation, _, err = a.client.Apps.FindUserInstallation(ctx, owner)
+	}
+	if err != nil {
+		return 0, fmt.Errorf("could not find the GitHub App installation for %s: %w", owner, err)
+	}
+	a.mut.Lock()
+	a.installations[owner] = installation.GetID()
+	a.mut.Unlock()
+
+	return installation.GetID(), nil
+}
+
+// Transport returns a transport authenticating each request with the
+// installation token of the repository owner the request is about.
+func (a *GitHubApp) Transport(base http.RoundTripper) http.RoundTripper {
+	return &appInstallationTransport{base: base, app: a}
+}
+
+type appJWTTransport struct {
+	base http.RoundTripper
+	app  *GitHubApp
+}
+
+func (t *appJWTTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	jwt, err := t.app.JWT()
+	if err != nil {
+		return nil, err
+	}
+
+	req = req.Clone(req.Context())
+	req.Header.Set("Authorization", "Bearer "+jwt)
+	return t.base.RoundTrip(req)
+}
+
+type appInstallationTransport struct {
+	base http.RoundTripper
+	app  *GitHubApp
+}
+
+func (t *appInstallationTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	token, err := t.app.Token(req.Context(), ownerFromAPIPath(req.URL))
+	if err != nil {
+		return nil, err
+	}
+
+	req = req.Clone(req.Context())
+	req.Header.Set("Authorization", "token "+token)
+	return t.base.RoundTrip(req)
+}
+
+// ownerFromAPIPath returns the repository owner or organization a GitHub API
+// request is about, or an empty string if it isn't about one.
+func ownerFromAPIPath(u *url.URL) string {
+	parts := strings.Split(strings.TrimPrefix(u.Path, "/"), "/")
+	// GitHub Enterprise serves the API below /api/v3.
+	if len(parts) > 2 && parts[0] == "api" && parts[1] == "v3" {
+		parts = parts[2:]
+	}
+	if len(parts) < 2 {
+		return ""
+	}
+
+	switch parts[0] {
+	case "repos", "orgs":
+		return parts[1]
+	}
+	return ""
+}
@@ -0,0 +1,218 @@
+// Copyright (c) 2017-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package server
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestGitHubApp(t *testing.T, handler http.Handler) (*GitHubApp, *rsa.PrivateKey) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	ts := httptest.NewServer(handler)
+	t.Cleanup(ts.Close)
+
+	app := newGitHubApp(&GitHubAppConfig{AppID: 42}, key, "mattermost", http.DefaultTransport)
+	app.client.BaseURL, err = url.Parse(ts.URL + "/")
+	require.NoError(t, err)
+
+	return app, key
+}
+
+func TestGitHubAppJWT(t *testing.T) {
+	app, key := newTestGitHubApp(t, http.NotFoundHandler())
+	now := time.Now()
+	app.now = func() time.Time { return now }
+
+	jwt, err := app.JWT()
+	require.NoError(t, err)
+
+	parts := strings.Split(jwt, ".")
+	require.Len(t, parts, 3)
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	require.NoError(t, err)
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	require.NoError(t, rsa.VerifyPKCS1v15(&key.PublicKey, crypto.SHA256, digest[:], signature))
+
+	rawClaims, err := base64.RawURLEncoding.DecodeString(parts[1])
+	require.NoError(t, err)
+	var claims struct {
+		Iat int64  `json:"iat"`
+		Exp int64  `json:"exp"`
+		Iss string `json:"iss"`
+	}
+	require.NoError(t, json.Unmarshal(rawClaims, &claims))
+	assert.Equal(t, "42", claims.Iss)
+	assert.Less(t, claims.Iat, now.Unix())
+	assert.LessOrEqual(t, claims.Exp, now.Add(10*time.Minute).Unix())
+}
+
+func TestGitHubAppToken(t *testing.T) {
+	var tokenRequests int32
+	expiresAt := time.Now().Add(time.Hour).UTC()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/orgs/mattermost/installation", func(w http.ResponseWriter, r *http.Request) {
+		assert.True(t, strings.HasPrefix(r.Header.Get("Authorization"), "Bearer "))
+		fmt.Fprint(w, `{"id": 1}`)
+	})
+	mux.HandleFunc("/orgs/someone/installation", func(w http.ResponseWriter, r *http.Request) {
+		http.NotFound(w, r)
+	})
+	mux.HandleFunc("/users/someone/installation", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"id": 2}`)
+	})
+	mux.HandleFunc("/app/installations/", func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&tokenRequests, 1)
+		id := strings.Split(strings.TrimPrefix(r.URL.Path, "/app/installations/"), "/")[0]
+		fmt.Fprintf(w, `{"token": "token-%s-%d", "expires_at": %q}`, id, n, expiresAt.Format(time.RFC3339))
+	})
+
+	app, _ := newTestGitHubApp(t, mux)
+
+	t.Run("Should create a token for the default owner", func(t *testing.T) {
+		token, err := app.Token(context.Background(), "")
+		require.NoError(t, err)
+		assert.Equal(t, "token-1-1", token)
+	})
+
+	t.Run("Should cache the token", func(t *testing.T) {
+		token, err := app.Token(context.Background(), "Mattermost")
+		require.NoError(t, err)
+		assert.Equal(t, "token-1-1", token)
+		assert.EqualValues(t, 1, atomic.LoadInt32(&tokenRequests))
+	})
+
+	t.Run("Should fall back to the user installation", func(t *testing.T) {
+		token, err := app.Token(context.Background(), "someone")
+		require.NoError(t, err)
+		assert.Equal(t, "token-2-2", token)
+	})
+
+	t.Run("Should refresh the token before it expires", func(t *testing.T) {
+		app.now = func() time.Time { return expiresAt.Add(-time.Minute) }
+		defer func() { app.now = time.Now }()
+
+		token, err := app.Token(context.Background(), "mattermost")
+		require.NoError(t, err)
+		assert.Equal(t, "token-1-3", token)
+	})
+
+	t.Run("Should use the configured installation", func(t *testing.T) {
+		app.installations["configured"] = 3
+
+		token, err := app.Token(context.Background(), "configured")
+		require.NoError(t, err)
+		assert.Equal(t, "token-3-4", token)
+	})
+}
+
+func TestGitHubAppTokenOwnersDontWaitForEachOther(t *testing.T) {
+	release := make(chan struct{})
+	mux := http.NewServeMux()
+	mux.HandleFunc("/app/installations/1/access_tokens", func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		fmt.Fprintf(w, `{"token": "slow-token", "expires_at": %q}`, time.Now().Add(time.Hour).Format(time.RFC3339))
+	})
+	mux.HandleFunc("/app/installations/2/access_tokens", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"token": "fast-token", "expires_at": %q}`, time.Now().Add(time.Hour).Format(time.RFC3339))
+	})
+
+	app, _ := newTestGitHubApp(t, mux)
+	app.installations["slow"] = 1
+	app.installations["fast"] = 2
+
+	slowDone := make(chan string)
+	go func() {
+		token, _ := app.Token(context.Background(), "slow")
+		slowDone <- token
+	}()
+
+	token, err := app.Token(context.Background(), "fast")
+	require.NoError(t, err)
+	assert.Equal(t, "fast-token", token)
+
+	close(release)
+	assert.Equal(t, "slow-token", <-slowDone)
+}
+
+func TestGitHubAppTransport(t *testing.T) {
+	var gotAuth string
+	mux := http.NewServeMux()
+	mux.HandleFunc("/orgs/mattermost/installation", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"id": 1}`)
+	})
+	mux.HandleFunc("/app/installations/1/access_tokens", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"token": "installation-token", "expires_at": %q}`, time.Now().Add(time.Hour).Format(time.RFC3339))
+	})
+	mux.HandleFunc("/repos/mattermost/mattermod/pulls/1", func(w http.ResponseWriter, r *http.Request) {
+		gotAuth = r.Header.Get("Authorization")
+		fmt.Fprint(w, `{}`)
+	})
+
+	app, _ := newTestGitHubApp(t, mux)
+
+	client := &http.Client{Transport: app.Transport(http.DefaultTransport)}
+	resp, err := client.Get(app.client.BaseURL.String() + "repos/mattermost/mattermod/pulls/1")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "token installation-token", gotAuth)
+}
+
+func TestParseRSAPrivateKey(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	pkcs1 := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+	parsed, err := parseRSAPrivateKey(pkcs1)
+	require.NoError(t, err)
+	assert.True(t, key.Equal(parsed))
+
+	pkcs8Bytes, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+	pkcs8 := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: pkcs8Bytes})
+	parsed, err = parseRSAPrivateKey(pkcs8)
+	require.NoError(t, err)
+	assert.True(t, key.Equal(parsed))
+
+	_, err = parseRSAPrivateKey([]byte("not a key"))
+	require.Error(t, err)
+}
+
+func TestOwnerFromAPIPath(t *testing.T) {
+	for path, owner := range map[string]string{
+		"/repos/mattermost/mattermod/pulls/1":        "mattermost",
+		"/api/v3/repos/mattermost/mattermod/pulls/1": "mattermost",
+		"/orgs/mattertest/members":                   "mattertest",
+		"/rate_limit":                                "",
+		"/search/issues":                             "",
+	} {
+		u, err := url.Parse("https://api.github.com" + path)
+		require.NoError(t, err)
+		assert.Equal(t, owner, ownerFromAPIPath(u), path)
+	}
+}
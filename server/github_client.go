@@ -6,6 +6,7 @@ package server
 import (
 	"context"
 	"errors"
+	"net/http"
 	"time"
 
 	"github.com/die-net/lrucache"
@@ -23,8 +24,10 @@ type IssuesService interface {
 	AddAssignees(ctx context.Context, owner, repo string, number int, assignees []string) (*github.Issue, *github.Response, error)
 	AddLabelsToIssue(ctx context.Context, owner string, repo string, number int, labels []string) ([]*github.Label, *github.Response, error)
 	CreateComment(ctx context.Context, owner string, repo string, number int, comment *github.IssueComment) (*github.IssueComment, *github.Response, error)
+	CreateIssueCommentReaction(ctx context.Context, owner, repo string, id int64, content string) (*github.Reaction, *github.Response, error)
 	DeleteComment(ctx context.Context, owner string, repo string, commentID int64) (*github.Response, error)
 	Edit(ctx context.Context, owner string, repo string, number int, issue *github.IssueRequest) (*github.Issue, *github.Response, error)
+	EditComment(ctx context.Context, owner string, repo string, commentID int64, comment *github.IssueComment) (*github.IssueComment, *github.Response, error)
 	Get(ctx context.Context, owner string, repo string, number int) (*github.Issue, *github.Response, error)
 	ListByRepo(ctx context.Context, owner string, repo string, opts *github.IssueListByRepoOptions) ([]*github.Issue, *github.Response, error)
 	ListComments(ctx context.Context, owner string, repo string, number int, opts *github.IssueListCommentsOptions) ([]*github.IssueComment, *github.Response, error)
@@ -32,6 +35,17 @@ type IssuesService interface {
 	RemoveLabelForIssue(ctx context.Context, owner string, repo string, number int, label string) (*github.Response, error)
 }
 
+// issuesService adds the reactions to issue comments, which go-github has in
+// a separate service, to its IssuesService.
+type issuesService struct {
+	*github.IssuesService
+	reactions *github.ReactionsService
+}
+
+func (s *issuesService) CreateIssueCommentReaction(ctx context.Context, owner, repo string, id int64, content string) (*github.Reaction, *github.Response, error) {
+	return s.reactions.CreateIssueCommentReaction(ctx, owner, repo, id, content)
+}
+
 type GitService interface {
 	CreateRef(ctx context.Context, owner string, repo string, ref *github.Reference) (*github.Reference, *github.Response, error)
 	DeleteRef(ctx context.Context, owner string, repo string, ref string) (*github.Response, error)
@@ -47,8 +61,10 @@ type OrganizationsService interface {
 }
 
 type PullRequestsService interface {
+	Create(ctx context.Context, owner string, repo string, pull *github.NewPullRequest) (*github.PullRequest, *github.Response, error)
 	Get(ctx context.Context, owner string, repo string, number int) (*github.PullRequest, *github.Response, error)
 	List(ctx context.Context, owner string, repo string, opts *github.PullRequestListOptions) ([]*github.PullRequest, *github.Response, error)
+	ListCommits(ctx context.Context, owner string, repo string, number int, opts *github.ListOptions) ([]*github.RepositoryCommit, *github.Response, error)
 	ListFiles(ctx context.Context, owner string, repo string, number int, opts *github.ListOptions) ([]*github.CommitFile, *github.Response, error)
 	ListReviewers(ctx context.Context, owner, repo string, number int, opts *github.ListOptions) (*github.Reviewers, *github.Response, error)
 	ListReviews(ctx context.Context, owner, repo string, number int, opts *github.ListOptions) ([]*github.PullRequestReview, *github.Response, error)
@@ -59,16 +75,20 @@ type PullRequestsService interface {
 }
 
 type RepositoriesService interface {
+	CompareCommits(ctx context.Context, owner, repo string, base, head string, opts *github.ListOptions) (*github.CommitsComparison, *github.Response, error)
 	CreateStatus(ctx context.Context, owner, repo, ref string, status *github.RepoStatus) (*github.RepoStatus, *github.Response, error)
 	Get(ctx context.Context, owner, repo string) (*github.Repository, *github.Response, error)
 	GetBranch(ctx context.Context, owner, repo, branch string, followRedirects bool) (*github.Branch, *github.Response, error)
+	GetBranchProtection(ctx context.Context, owner, repo, branch string) (*github.Protection, *github.Response, error)
 	ListBranches(ctx context.Context, owner string, repo string, opts *github.BranchListOptions) ([]*github.Branch, *github.Response, error)
 	GetCombinedStatus(ctx context.Context, owner, repo, ref string, opts *github.ListOptions) (*github.CombinedStatus, *github.Response, error)
+	GetPermissionLevel(ctx context.Context, owner, repo, user string) (*github.RepositoryPermissionLevel, *github.Response, error)
 	ListTeams(ctx context.Context, owner string, repo string, opts *github.ListOptions) ([]*github.Team, *github.Response, error)
 	ListStatuses(ctx context.Context, owner, repo, ref string, opts *github.ListOptions) ([]*github.RepoStatus, *github.Response, error)
 }
 
 type TeamsService interface {
+	GetTeamMembershipBySlug(ctx context.Context, org, slug, user string) (*github.Membership, *github.Response, error)
 	ListTeamMembersBySlug(ctx context.Context, org, slug string, opts *github.TeamListTeamMembersOptions) ([]*github.User, *github.Response, error)
 }
 
@@ -88,14 +108,39 @@ type GithubClient struct {
 // NewGithubClientWithLimiter returns a new Github client with the provided limit and burst tokens
 // that will be used by the rate limit transport.
 func NewGithubClientWithLimiter(accessToken string, limit rate.Limit, burstTokens int, metricsProvider MetricsProvider) *GithubClient {
+	ts := oauth2.StaticTokenSource(&oauth2.Token{AccessToken: accessToken})
+	tc := oauth2.NewClient(context.Background(), ts)
+	return newGithubClient(tc.Transport, limit, burstTokens, metricsProvider)
+}
+
+// NewGithubClient returns a new Github client that will use a fixed 10 req/sec / 10 burst
+// tokens rate limiter configuration
+func NewGithubClient(accessToken string, limitTokens int, metrics MetricsProvider) (*GithubClient, error) {
+	if limitTokens <= 0 {
+		return nil, errors.New("rate limit tokens for github client must be greater than 0")
+	}
+	limit := rate.Every(time.Second / time.Duration(limitTokens))
+	return NewGithubClientWithLimiter(accessToken, limit, limitTokens, metrics), nil
+}
+
+// NewGithubAppClient returns a new Github client authenticated with the installation
+// tokens of the given GitHub App, using the same rate limiter configuration as NewGithubClient.
+func NewGithubAppClient(app *GitHubApp, limitTokens int, metrics MetricsProvider) (*GithubClient, error) {
+	if limitTokens <= 0 {
+		return nil, errors.New("rate limit tokens for github client must be greater than 0")
+	}
+	limit := rate.Every(time.Second / time.Duration(limitTokens))
+	return newGithubClient(app.Transport(http.DefaultTransport), limit, limitTokens, metrics), nil
+}
+
+// newGithubClient wraps the authenticating transport with rate limiting, caching and metrics.
+func newGithubClient(authTransport http.RoundTripper, limit rate.Limit, burstTokens int, metricsProvider MetricsProvider) *GithubClient {
 	const (
 		lruCacheMaxSizeInBytes  = 1000 * 1000 * 1000 // 1GB
 		lruCacheMaxAgeInSeconds = 2629800            // 1 month
 	)
 
-	ts := oauth2.StaticTokenSource(&oauth2.Token{AccessToken: accessToken})
-	tc := oauth2.NewClient(context.Background(), ts)
-	limiterTransport := NewRateLimitTransport(limit, burstTokens, tc.Transport, metricsProvider)
+	limiterTransport := NewRateLimitTransport(limit, burstTokens, authTransport, metricsProvider)
 	httpCache := lrucache.New(lruCacheMaxSizeInBytes, lruCacheMaxAgeInSeconds)
 	httpCacheTransport := httpcache.NewTransport(httpCache)
 	httpCacheTransport.Transport = limiterTransport
@@ -106,7 +151,7 @@ func NewGithubClientWithLimiter(accessToken string, limit rate.Limit, burstToken
 		client:        client,
 		Checks:        client.Checks,
 		Git:           client.Git,
-		Issues:        client.Issues,
+		Issues:        &issuesService{IssuesService: client.Issues, reactions: client.Reactions},
 		Organizations: client.Organizations,
 		PullRequests:  client.PullRequests,
 		Repositories:  client.Repositories,
@@ -114,16 +159,6 @@ func NewGithubClientWithLimiter(accessToken string, limit rate.Limit, burstToken
 	}
 }
 
-// NewGithubClient returns a new Github client that will use a fixed 10 req/sec / 10 burst
-// tokens rate limiter configuration
-func NewGithubClient(accessToken string, limitTokens int, metrics MetricsProvider) (*GithubClient, error) {
-	if limitTokens <= 0 {
-		return nil, errors.New("rate limit tokens for github client must be greater than 0")
-	}
-	limit := rate.Every(time.Second / time.Duration(limitTokens))
-	return NewGithubClientWithLimiter(accessToken, limit, limitTokens, metrics), nil
-}
-
 func (c *GithubClient) RateLimits(ctx context.Context) (*github.RateLimits, *github.Response, error) {
 	return c.client.RateLimits(ctx)
 }
@@ -0,0 +1,102 @@
+// Copyright (c) 2017-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package server
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/mattermost/mattermost-mattermod/model"
+)
+
+const (
+	msgHoldPlaced     = "The merge of this PR is on hold until @%s lifts it with `/unhold`."
+	msgHoldLifted     = "The hold of @%s has been lifted."
+	msgHoldsLifted    = "All holds have been lifted."
+	msgNoHolds        = "There are no holds on this PR."
+	msgUnholdNotOwner = "Only the holders (%s) or maintainers can lift the holds on this PR."
+)
+
+// holdMaintainerRoles may lift the holds of other users.
+var holdMaintainerRoles = []string{roleCollaboratorPrefix + "maintain"}
+
+// handleHold places a hold of the commenter on the PR, blocking its merge.
+func (s *Server) handleHold(ctx context.Context, commenter string, args []string, pr *model.PullRequest) error {
+	if pr.State == model.StateClosed {
+		return fmt.Errorf("%w: the PR is closed", errNothingToDo)
+	}
+
+	err := s.Store.Hold().Save(&model.Hold{
+		RepoOwner: pr.RepoOwner,
+		RepoName:  pr.RepoName,
+		Number:    pr.Number,
+		Holder:    commenter,
+		Reason:    strings.Join(args, " "),
+	})
+	if err != nil {
+		return err
+	}
+	if err = s.updateMergeBlockedStatus(ctx, pr); err != nil {
+		return err
+	}
+
+	return s.sendCommandComment(ctx, pr.RepoOwner, pr.RepoName, pr.Number, fmt.Sprintf(msgHoldPlaced, commenter))
+}
+
+// handleUnhold lifts the hold of the commenter. Maintainers lift all holds.
+func (s *Server) handleUnhold(ctx context.Context, commenter string, pr *model.PullRequest) error {
+	holds, err := s.Store.Hold().List(pr.RepoOwner, pr.RepoName, pr.Number)
+	if err != nil {
+		return err
+	}
+	if len(holds) == 0 {
+		if err = s.sendGitHubComment(ctx, pr.RepoOwner, pr.RepoName, pr.Number, msgNoHolds); err != nil {
+			return err
+		}
+		return fmt.Errorf("%w: there are no holds", errNothingToDo)
+	}
+
+	isMaintainer, err := s.hasCommandRole(ctx, holdMaintainerRoles, commenter, pr)
+	if err != nil {
+		return err
+	}
+
+	var msg string
+	switch {
+	case isMaintainer:
+		err = s.Store.Hold().DeleteAll(pr.RepoOwner, pr.RepoName, pr.Number)
+		msg = msgHoldsLifted
+	case hasHold(holds, commenter):
+		err = s.Store.Hold().Delete(pr.RepoOwner, pr.RepoName, pr.Number, commenter)
+		msg = fmt.Sprintf(msgHoldLifted, commenter)
+	default:
+		s.Metrics.IncreaseCommandDenials("unhold")
+		holders := make([]string, 0, len(holds))
+		for _, hold := range holds {
+			holders = append(holders, "@"+hold.Holder)
+		}
+		if err = s.sendGitHubComment(ctx, pr.RepoOwner, pr.RepoName, pr.Number, fmt.Sprintf(msgUnholdNotOwner, strings.Join(holders, ", "))); err != nil {
+			return err
+		}
+		return fmt.Errorf("%w: %s doesn't hold the PR", errCommandDenied, commenter)
+	}
+	if err != nil {
+		return err
+	}
+	if err = s.updateMergeBlockedStatus(ctx, pr); err != nil {
+		return err
+	}
+
+	return s.sendCommandComment(ctx, pr.RepoOwner, pr.RepoName, pr.Number, msg)
+}
+
+func hasHold(holds []*model.Hold, holder string) bool {
+	for _, hold := range holds {
+		if hold.Holder == holder {
+			return true
+		}
+	}
+	return false
+}
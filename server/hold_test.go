@@ -0,0 +1,133 @@
+// Copyright (c) 2017-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package server
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/go-github/v39/github"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-mattermod/model"
+	srmock "github.com/mattermost/mattermost-mattermod/server/mocks"
+	stmock "github.com/mattermost/mattermost-mattermod/store/mocks"
+)
+
+func TestHolds(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctxInterface := reflect.TypeOf((*context.Context)(nil)).Elem()
+
+	holdStoreMock := stmock.NewMockHoldStore(ctrl)
+	ss := stmock.NewMockStore(ctrl)
+	ss.EXPECT().Hold().Return(holdStoreMock).AnyTimes()
+
+	repoMock := srmock.NewMockRepositoriesService(ctrl)
+	issueMock := srmock.NewMockIssuesService(ctrl)
+	metricsMock := srmock.NewMockMetricsProvider(ctrl)
+	s := &Server{
+		Config: &Config{
+			BlockPRMergeLabels: []string{"Do Not Merge"},
+		},
+		Store:   ss,
+		Metrics: metricsMock,
+		GithubClient: &GithubClient{
+			Repositories: repoMock,
+			Issues:       issueMock,
+		},
+	}
+	pr := &model.PullRequest{
+		RepoOwner: "mattertest",
+		RepoName:  "mattermod",
+		Number:    1,
+		Sha:       "sha",
+		State:     model.StateOpen,
+		Labels:    []string{"Do Not Merge"},
+	}
+	hold := func(holder, reason string) *model.Hold {
+		return &model.Hold{RepoOwner: "mattertest", RepoName: "mattermod", Number: 1, Holder: holder, Reason: reason}
+	}
+
+	expectStatus := func(state, description string) {
+		repoMock.EXPECT().
+			CreateStatus(gomock.AssignableToTypeOf(ctxInterface), "mattertest", "mattermod", "sha", &github.RepoStatus{
+				Context:     github.String(mergeBlockedContext),
+				State:       github.String(state),
+				Description: github.String(description),
+				TargetURL:   github.String(""),
+			}).
+			Return(nil, nil, nil)
+	}
+	expectComment := func(body string) {
+		issueMock.EXPECT().
+			CreateComment(gomock.AssignableToTypeOf(ctxInterface), "mattertest", "mattermod", 1, &github.IssueComment{Body: github.String(body)}).
+			Return(nil, nil, nil)
+	}
+	expectPermission := func(user, permission string) {
+		repoMock.EXPECT().
+			GetPermissionLevel(gomock.AssignableToTypeOf(ctxInterface), "mattertest", "mattermod", user).
+			Return(&github.RepositoryPermissionLevel{Permission: github.String(permission)}, nil, nil)
+	}
+
+	t.Run("Hold blocks the merge with the labels", func(t *testing.T) {
+		holdStoreMock.EXPECT().Save(hold("alice", "waiting for QA")).Return(nil)
+		holdStoreMock.EXPECT().List("mattertest", "mattermod", 1).Return([]*model.Hold{hold("alice", "waiting for QA")}, nil)
+		expectStatus(statePending, "Merge blocked due hold by @alice (waiting for QA), Do Not Merge label")
+		expectComment("The merge of this PR is on hold until @alice lifts it with `/unhold`.")
+
+		require.NoError(t, s.handleHold(context.Background(), "alice", []string{"waiting", "for", "QA"}, pr))
+	})
+
+	t.Run("Holders lift their own hold", func(t *testing.T) {
+		holdStoreMock.EXPECT().List("mattertest", "mattermod", 1).Return([]*model.Hold{hold("alice", ""), hold("bob", "")}, nil)
+		expectPermission("bob", "write")
+		holdStoreMock.EXPECT().Delete("mattertest", "mattermod", 1, "bob").Return(nil)
+		holdStoreMock.EXPECT().List("mattertest", "mattermod", 1).Return([]*model.Hold{hold("alice", "")}, nil)
+		expectStatus(statePending, "Merge blocked due hold by @alice, Do Not Merge label")
+		expectComment("The hold of @bob has been lifted.")
+
+		require.NoError(t, s.handleUnhold(context.Background(), "bob", pr))
+	})
+
+	t.Run("Maintainers lift all holds", func(t *testing.T) {
+		unlabeled := *pr
+		unlabeled.Labels = nil
+		holdStoreMock.EXPECT().List("mattertest", "mattermod", 1).Return([]*model.Hold{hold("alice", ""), hold("bob", "")}, nil)
+		repoMock.EXPECT().
+			GetPermissionLevel(gomock.AssignableToTypeOf(ctxInterface), "mattertest", "mattermod", "carol").
+			Return(&github.RepositoryPermissionLevel{
+				Permission: github.String("write"),
+				User:       &github.User{Permissions: map[string]bool{"maintain": true}},
+			}, nil, nil)
+		holdStoreMock.EXPECT().DeleteAll("mattertest", "mattermod", 1).Return(nil)
+		holdStoreMock.EXPECT().List("mattertest", "mattermod", 1).Return(nil, nil)
+		expectStatus(stateSuccess, "Merged allowed")
+		expectComment(msgHoldsLifted)
+
+		require.NoError(t, s.handleUnhold(context.Background(), "carol", &unlabeled))
+	})
+
+	t.Run("Others can't lift holds", func(t *testing.T) {
+		holdStoreMock.EXPECT().List("mattertest", "mattermod", 1).Return([]*model.Hold{hold("alice", ""), hold("bob", "")}, nil)
+		repoMock.EXPECT().
+			GetPermissionLevel(gomock.AssignableToTypeOf(ctxInterface), "mattertest", "mattermod", "mallory").
+			Return(nil, &github.Response{Response: &http.Response{StatusCode: http.StatusNotFound}}, nil)
+		metricsMock.EXPECT().IncreaseCommandDenials("unhold")
+		expectComment("Only the holders (@alice, @bob) or maintainers can lift the holds on this PR.")
+
+		require.ErrorIs(t, s.handleUnhold(context.Background(), "mallory", pr), errCommandDenied)
+	})
+
+	t.Run("Unhold without holds", func(t *testing.T) {
+		holdStoreMock.EXPECT().List("mattertest", "mattermod", 1).Return(nil, nil)
+		expectComment(msgNoHolds)
+
+		require.ErrorIs(t, s.handleUnhold(context.Background(), "alice", pr), errNothingToDo)
+	})
+}
@@ -8,19 +8,12 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
-	"net/http"
-	"strings"
-	"time"
 
 	"github.com/google/go-github/v39/github"
 	"github.com/mattermost/mattermost-server/v6/shared/mlog"
 	"github.com/pkg/errors"
 )
 
-const (
-	commenterNoPermissions = "commenter does not have permissions"
-)
-
 type issueCommentEvent struct {
 	Comment    *github.PullRequestComment `json:"comment"`
 	Issue      *github.Issue              `json:"issue"`
@@ -28,76 +21,47 @@ type issueCommentEvent struct {
 	Action     string                     `json:"action"`
 }
 
-func (s *Server) issueCommentEventHandler(w http.ResponseWriter, r *http.Request) {
-	ctx, cancel := context.WithTimeout(context.Background(), defaultRequestTimeout*time.Second)
-	defer cancel()
-
-	ev, err := issueCommentEventFromJSON(r.Body)
-	if err != nil {
-		mlog.Error("could not parse pr comment event", mlog.Err(err))
-		http.Error(w, err.Error(), http.StatusBadRequest)
-		return
-	}
-
+func (s *Server) issueCommentEventHandler(ctx context.Context, ev *issueCommentEvent) error {
 	// We ignore comments from issues.
 	if !ev.Issue.IsPullRequest() {
-		return
+		return nil
 	}
 
 	// We ignore deletion events for now.
 	if ev.Action == "deleted" {
-		return
+		return nil
 	}
 
 	pr, err := s.getPRFromIssueCommentEvent(ctx, ev)
 	if err != nil {
-		mlog.Error("Error getting PR from Comment", mlog.Err(err))
-		http.Error(w, err.Error(), http.StatusInternalServerError)
-		return
+		return fmt.Errorf("error getting PR from comment: %w", err)
 	}
 	commenter := ev.Comment.GetUser().GetLogin()
 
-	errs := make([]error, 0)
+	errs := s.runCommentAutomations(ctx, ev, pr)
 
-	if ev.HasCheckCLA() {
-		s.Metrics.IncreaseWebhookRequest("check_cla")
-		if _, err := s.handleCheckCLA(ctx, pr); err != nil {
-			s.Metrics.IncreaseWebhookErrors("check_cla")
-			errs = append(errs, fmt.Errorf("error checking CLA: %w", err))
+	var cmdErrs []error
+	ran := false
+	for _, cmd := range parseCommands(ev.Comment.GetBody()) {
+		if err = s.runCommand(ctx, &commandRequest{event: ev, pr: pr, commenter: commenter, cmd: cmd}); err != nil {
+			cmdErrs = append(cmdErrs, err)
+		} else {
+			ran = true
 		}
 	}
-
-	if ev.HasCherryPick() {
-		s.Metrics.IncreaseWebhookRequest("cherry_pick")
-		if err := s.handleCherryPick(ctx, commenter, ev.Comment.GetBody(), pr); err != nil {
-			s.Metrics.IncreaseWebhookErrors("cherry_pick")
-			errs = append(errs, fmt.Errorf("error cherry picking: %w", err))
-		}
-	}
-
-	if ev.HasAutoAssign() {
-		s.Metrics.IncreaseWebhookRequest("auto_assign")
-		if err := s.handleAutoAssign(ctx, ev.Comment.GetHTMLURL(), pr); err != nil {
-			s.Metrics.IncreaseWebhookErrors("auto_assign")
-			errs = append(errs, fmt.Errorf("error auto assigning: %w", err))
-		}
-	}
-
-	if ev.HasUpdateBranch() {
-		s.Metrics.IncreaseWebhookRequest("update_branch")
-		if err := s.handleUpdateBranch(ctx, commenter, pr); err != nil {
-			s.Metrics.IncreaseWebhookErrors("update_branch")
-			errs = append(errs, fmt.Errorf("error updating branch: %w", err))
-		}
+	// Retrying the comment runs all of its commands again, which must not
+	// repeat the ones which succeeded.
+	if ran && len(cmdErrs) > 0 {
+		errs = append(errs, newPermanentError(joinErrors(cmdErrs...)))
+	} else {
+		errs = append(errs, cmdErrs...)
 	}
 
 	for _, err := range errs {
 		mlog.Error("Error handling PR comment", mlog.Err(err))
 	}
 
-	if len(errs) > 0 {
-		http.Error(w, "Error handling PR comment", http.StatusInternalServerError)
-	}
+	return automationsError(errs)
 }
 
 func issueCommentEventFromJSON(data io.Reader) (*issueCommentEvent, error) {
@@ -119,23 +83,3 @@ func issueCommentEventFromJSON(data io.Reader) (*issueCommentEvent, error) {
 
 	return &pr, nil
 }
-
-// HasCheckCLA is true if body contains "/check-cla"
-func (e *issueCommentEvent) HasCheckCLA() bool {
-	return strings.Contains(strings.TrimSpace(e.Comment.GetBody()), "/check-cla")
-}
-
-// HasCherryPick is true if body contains "/cherry-pick"
-func (e *issueCommentEvent) HasCherryPick() bool {
-	return strings.Contains(strings.TrimSpace(e.Comment.GetBody()), "/cherry-pick")
-}
-
-// HasAutoAssign is true if body contains "/autoassign"
-func (e *issueCommentEvent) HasAutoAssign() bool {
-	return strings.Contains(strings.TrimSpace(e.Comment.GetBody()), "/autoassign")
-}
-
-// HasUpdateBranch is true if body contains "/update-branch"
-func (e *issueCommentEvent) HasUpdateBranch() bool {
-	return strings.Contains(strings.TrimSpace(e.Comment.GetBody()), "/update-branch")
-}
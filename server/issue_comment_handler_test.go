@@ -4,12 +4,9 @@
 package server
 
 import (
-	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
-	"net/http"
-	"net/http/httptest"
 	"reflect"
 	"testing"
 
@@ -25,11 +22,13 @@ func TestIssueCommentEventHandler(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
+	metricsMock := mocks.NewMockMetricsProvider(ctrl)
 	s := &Server{
 		GithubClient: &GithubClient{},
 		Config: &Config{
 			Repositories: []*Repository{},
 		},
+		Metrics: metricsMock,
 	}
 	prs := mocks.NewMockPullRequestsService(ctrl)
 	s.GithubClient.PullRequests = prs
@@ -72,18 +71,12 @@ func TestIssueCommentEventHandler(t *testing.T) {
 		},
 	}
 
-	ts := httptest.NewServer(http.HandlerFunc(s.issueCommentEventHandler))
-	defer ts.Close()
-
 	ctxInterface := reflect.TypeOf((*context.Context)(nil)).Elem()
 
 	t.Run("Should fail with no body", func(t *testing.T) {
-		req, err := http.NewRequest("POST", ts.URL, nil)
-		require.NoError(t, err)
-		resp, err := http.DefaultClient.Do(req)
-		require.NoError(t, err)
-		defer resp.Body.Close()
-		require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		err := s.handleEvent(context.Background(), "issue_comment", nil)
+		var perr *permanentError
+		require.ErrorAs(t, err, &perr)
 	})
 
 	t.Run("Missing entities in body", func(t *testing.T) {
@@ -93,12 +86,9 @@ func TestIssueCommentEventHandler(t *testing.T) {
 		})
 		require.NoError(t, err)
 
-		req, err := http.NewRequest("POST", ts.URL, bytes.NewReader(b))
-		require.NoError(t, err)
-		resp, err := http.DefaultClient.Do(req)
-		require.NoError(t, err)
-		defer resp.Body.Close()
-		require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		err = s.handleEvent(context.Background(), "issue_comment", b)
+		var perr *permanentError
+		require.ErrorAs(t, err, &perr)
 	})
 
 	t.Run("Not a pull request comment, should not fail", func(t *testing.T) {
@@ -109,12 +99,8 @@ func TestIssueCommentEventHandler(t *testing.T) {
 		})
 		require.NoError(t, err)
 
-		req, err := http.NewRequest("POST", ts.URL, bytes.NewReader(b))
+		err = s.handleEvent(context.Background(), "issue_comment", b)
 		require.NoError(t, err)
-		resp, err := http.DefaultClient.Do(req)
-		require.NoError(t, err)
-		defer resp.Body.Close()
-		require.Equal(t, http.StatusOK, resp.StatusCode)
 	})
 
 	t.Run("Deletion event, should not fail", func(t *testing.T) {
@@ -126,12 +112,8 @@ func TestIssueCommentEventHandler(t *testing.T) {
 		})
 		require.NoError(t, err)
 
-		req, err := http.NewRequest("POST", ts.URL, bytes.NewReader(b))
-		require.NoError(t, err)
-		resp, err := http.DefaultClient.Do(req)
+		err = s.handleEvent(context.Background(), "issue_comment", b)
 		require.NoError(t, err)
-		defer resp.Body.Close()
-		require.Equal(t, http.StatusOK, resp.StatusCode)
 	})
 
 	t.Run("Should fail on getting the PR", func(t *testing.T) {
@@ -142,12 +124,8 @@ func TestIssueCommentEventHandler(t *testing.T) {
 		b, err := json.Marshal(&event)
 		require.NoError(t, err)
 
-		req, err := http.NewRequest("POST", ts.URL, bytes.NewReader(b))
-		require.NoError(t, err)
-		resp, err := http.DefaultClient.Do(req)
-		require.NoError(t, err)
-		defer resp.Body.Close()
-		require.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+		err = s.handleEvent(context.Background(), "issue_comment", b)
+		require.Error(t, err)
 	})
 
 	t.Run("Should fallthrough the handler", func(t *testing.T) {
@@ -155,6 +133,10 @@ func TestIssueCommentEventHandler(t *testing.T) {
 			Times(1).
 			Return(nil, nil, nil)
 
+		prStoreMock.EXPECT().Get("", "", 0).
+			Times(1).
+			Return(nil, nil)
+
 		is.EXPECT().ListLabelsByIssue(gomock.AssignableToTypeOf(ctxInterface), "", "", 0, nil).
 			Times(1).
 			Return([]*github.Label{}, nil, nil)
@@ -166,11 +148,33 @@ func TestIssueCommentEventHandler(t *testing.T) {
 		b, err := json.Marshal(&event)
 		require.NoError(t, err)
 
-		req, err := http.NewRequest("POST", ts.URL, bytes.NewReader(b))
+		err = s.handleEvent(context.Background(), "issue_comment", b)
+		require.NoError(t, err)
+	})
+	t.Run("Should only run commands at the start of a line", func(t *testing.T) {
+		ev := event
+		ev.Comment = &github.PullRequestComment{
+			Body: github.String("> /update-branch\n```\n/update-branch\n```\nPlease /update-branch\n/update-branch"),
+			User: &github.User{Login: github.String("someone")},
+		}
+
+		prs.EXPECT().Get(gomock.AssignableToTypeOf(ctxInterface), "mattertest", "mattermod", 1).
+			Return(&github.PullRequest{Number: github.Int(1)}, nil, nil)
+		prStoreMock.EXPECT().Get("", "", 1).Return(nil, nil)
+		is.EXPECT().ListLabelsByIssue(gomock.AssignableToTypeOf(ctxInterface), "", "", 1, nil).
+			Return([]*github.Label{}, nil, nil)
+		prStoreMock.EXPECT().Save(gomock.AssignableToTypeOf(&model.PullRequest{})).Return(nil, nil)
+
+		metricsMock.EXPECT().IncreaseWebhookRequest("update_branch").Times(1)
+		metricsMock.EXPECT().IncreaseCommandDenials("update-branch").Times(1)
+		is.EXPECT().CreateComment(gomock.AssignableToTypeOf(ctxInterface), "", "", 1, gomock.AssignableToTypeOf(&github.IssueComment{})).
+			Times(1).
+			Return(nil, nil, nil)
+
+		b, err := json.Marshal(&ev)
 		require.NoError(t, err)
-		resp, err := http.DefaultClient.Do(req)
+
+		err = s.handleEvent(context.Background(), "issue_comment", b)
 		require.NoError(t, err)
-		defer resp.Body.Close()
-		require.Equal(t, http.StatusOK, resp.StatusCode)
 	})
 }
@@ -8,9 +8,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
-	"net/http"
 	"strings"
-	"time"
 
 	"github.com/google/go-github/v39/github"
 	"github.com/mattermost/mattermost-mattermod/model"
@@ -25,17 +23,7 @@ type issueEvent struct {
 	Action string             `json:"action"`
 }
 
-func (s *Server) issueEventHandler(w http.ResponseWriter, r *http.Request) {
-	ctx, cancel := context.WithTimeout(context.Background(), defaultRequestTimeout*time.Second)
-	defer cancel()
-
-	event, err := issueEventFromJSON(r.Body)
-	if err != nil {
-		mlog.Error("could not parse issue event", mlog.Err(err))
-		http.Error(w, err.Error(), http.StatusBadRequest)
-		return
-	}
-
+func (s *Server) issueEventHandler(ctx context.Context, event *issueEvent) error {
 	mlog.Info("handle issue event",
 		mlog.String("repoUrl", event.Issue.GetHTMLURL()),
 		mlog.String("Action", event.Action),
@@ -43,15 +31,14 @@ func (s *Server) issueEventHandler(w http.ResponseWriter, r *http.Request) {
 
 	issue, err := s.GetIssueFromGithub(ctx, event.Issue)
 	if err != nil {
-		mlog.Error("could not get the issue from GitHub", mlog.Err(err))
-		http.Error(w, "could not get the issue from GitHub", http.StatusInternalServerError)
-		return
+		return fmt.Errorf("could not get the issue from GitHub: %w", err)
 	}
 
 	if err := s.checkIssueForChanges(ctx, issue); err != nil {
-		mlog.Error("could not check issue for changes", mlog.Err(err))
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return fmt.Errorf("could not check issue for changes: %w", err)
 	}
+
+	return automationsError(s.runIssueAutomations(ctx, event, issue))
 }
 
 func (s *Server) checkIssueForChanges(ctx context.Context, issue *model.Issue) error {
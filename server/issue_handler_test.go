@@ -4,12 +4,10 @@
 package server
 
 import (
-	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
 	"net/http"
-	"net/http/httptest"
 	"reflect"
 	"testing"
 
@@ -88,18 +86,12 @@ func TestIssueEventHandler(t *testing.T) {
 		Number:    1,
 	}
 
-	ts := httptest.NewServer(http.HandlerFunc(s.issueEventHandler))
-	defer ts.Close()
-
 	ctxInterface := reflect.TypeOf((*context.Context)(nil)).Elem()
 
 	t.Run("Should fail with no body", func(t *testing.T) {
-		req, err := http.NewRequest("POST", ts.URL, nil)
-		require.NoError(t, err)
-		resp, err := http.DefaultClient.Do(req)
-		require.NoError(t, err)
-		defer resp.Body.Close()
-		require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		err := s.handleEvent(context.Background(), "issues", nil)
+		var perr *permanentError
+		require.ErrorAs(t, err, &perr)
 	})
 
 	t.Run("Should fail for incorrect url", func(t *testing.T) {
@@ -118,12 +110,8 @@ func TestIssueEventHandler(t *testing.T) {
 		})
 		require.NoError(t, err)
 
-		req, err := http.NewRequest("POST", ts.URL, bytes.NewReader(b))
-		require.NoError(t, err)
-		resp, err := http.DefaultClient.Do(req)
-		require.NoError(t, err)
-		defer resp.Body.Close()
-		require.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+		err = s.handleEvent(context.Background(), "issues", b)
+		require.Error(t, err)
 	})
 
 	t.Run("Should be able to parse url", func(t *testing.T) {
@@ -146,12 +134,8 @@ func TestIssueEventHandler(t *testing.T) {
 		})
 		require.NoError(t, err)
 
-		req, err := http.NewRequest("POST", ts.URL, bytes.NewReader(b))
-		require.NoError(t, err)
-		resp, err := http.DefaultClient.Do(req)
-		require.NoError(t, err)
-		defer resp.Body.Close()
-		require.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+		err = s.handleEvent(context.Background(), "issues", b)
+		require.Error(t, err)
 	})
 
 	t.Run("Should fail for not getting issue from github", func(t *testing.T) {
@@ -162,12 +146,8 @@ func TestIssueEventHandler(t *testing.T) {
 		b, err := json.Marshal(&event)
 		require.NoError(t, err)
 
-		req, err := http.NewRequest("POST", ts.URL, bytes.NewReader(b))
-		require.NoError(t, err)
-		resp, err := http.DefaultClient.Do(req)
-		require.NoError(t, err)
-		defer resp.Body.Close()
-		require.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+		err = s.handleEvent(context.Background(), "issues", b)
+		require.Error(t, err)
 	})
 
 	t.Run("Issue does not have changes", func(t *testing.T) {
@@ -183,12 +163,8 @@ func TestIssueEventHandler(t *testing.T) {
 		b, err := json.Marshal(&event)
 		require.NoError(t, err)
 
-		req, err := http.NewRequest("POST", ts.URL, bytes.NewReader(b))
+		err = s.handleEvent(context.Background(), "issues", b)
 		require.NoError(t, err)
-		resp, err := http.DefaultClient.Do(req)
-		require.NoError(t, err)
-		defer resp.Body.Close()
-		require.Equal(t, http.StatusOK, resp.StatusCode)
 	})
 
 	t.Run("Issue has changes", func(t *testing.T) {
@@ -213,12 +189,8 @@ func TestIssueEventHandler(t *testing.T) {
 		b, err := json.Marshal(&event)
 		require.NoError(t, err)
 
-		req, err := http.NewRequest("POST", ts.URL, bytes.NewReader(b))
-		require.NoError(t, err)
-		resp, err := http.DefaultClient.Do(req)
+		err = s.handleEvent(context.Background(), "issues", b)
 		require.NoError(t, err)
-		defer resp.Body.Close()
-		require.Equal(t, http.StatusOK, resp.StatusCode)
 	})
 
 	t.Run("Issue labeled", func(t *testing.T) {
@@ -265,11 +237,7 @@ func TestIssueEventHandler(t *testing.T) {
 		b, err := json.Marshal(&event)
 		require.NoError(t, err)
 
-		req, err := http.NewRequest("POST", ts.URL, bytes.NewReader(b))
-		require.NoError(t, err)
-		resp, err := http.DefaultClient.Do(req)
+		err = s.handleEvent(context.Background(), "issues", b)
 		require.NoError(t, err)
-		defer resp.Body.Close()
-		require.Equal(t, http.StatusOK, resp.StatusCode)
 	})
 }
@@ -0,0 +1,194 @@
+// Copyright (c) 2017-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package server
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"text/template"
+
+	"github.com/google/go-github/v39/github"
+	"github.com/mattermost/mattermost-mattermod/model"
+	"github.com/mattermost/mattermost-server/v6/shared/mlog"
+)
+
+const (
+	defaultMergeTitleTemplate = "{{.Title}} (#{{.Number}})"
+	defaultMergeBodyTemplate  = "{{range .Issues}}Fixes {{.}}\n{{end}}" +
+		"{{if .CoAuthors}}\n{{range .CoAuthors}}Co-authored-by: {{.}}\n{{end}}{{end}}"
+)
+
+// issueReferenceRegex matches the issues a PR body closes with a keyword,
+// e.g. "Fixes #12", "closes mattermost/mattermost-server#34" or "resolves"
+// followed by the URL of the issue.
+var issueReferenceRegex = regexp.MustCompile(`(?i)\b(?:close[sd]?|fix(?:e[sd])?|resolve[sd]?):?\s+(?:([\w.-]+/[\w.-]+)?#(\d+)|https://github\.com/([\w.-]+/[\w.-]+)/issues/(\d+))`)
+
+// coAuthorTrailerRegex matches the Co-authored-by trailers of a commit
+// message.
+var coAuthorTrailerRegex = regexp.MustCompile(`(?im)^Co-authored-by:\s*(.+?)\s*$`)
+
+// mergePolicy returns the merge policy of the repository, with defaults
+// filled in for the unset fields.
+func (s *Server) mergePolicy(repoOwner, repoName string) *MergePolicy {
+	policy := MergePolicy{}
+	if repo, ok := GetRepository(s.Config.Repositories, repoOwner, repoName); ok && repo.Merge != nil {
+		policy = *repo.Merge
+	}
+
+	if policy.Method != "" && !isMergeMethod(policy.Method) {
+		mlog.Warn("Unknown merge method in the merge policy, using the default",
+			mlog.String("repo", repoOwner+"/"+repoName),
+			mlog.String("method", policy.Method),
+			mlog.String("default", defaultMergeMethod))
+		policy.Method = ""
+	}
+	if policy.Method == "" {
+		policy.Method = defaultMergeMethod
+	}
+	if policy.TitleTemplate == "" {
+		policy.TitleTemplate = defaultMergeTitleTemplate
+	}
+	if policy.BodyTemplate == "" {
+		policy.BodyTemplate = defaultMergeBodyTemplate
+	}
+	return &policy
+}
+
+// mergeMethod returns the method the PR is merged with: the one of its first
+// label listed in the MethodLabels of the policy, or else the Method. Labels
+// mapped to unknown methods are ignored.
+func mergeMethod(policy *MergePolicy, labels []*github.Label) string {
+	for _, label := range labels {
+		method, ok := policy.MethodLabels[label.GetName()]
+		if !ok {
+			continue
+		}
+		if !isMergeMethod(method) {
+			mlog.Warn("Unknown merge method of a label in the merge policy, ignoring the label",
+				mlog.String("label", label.GetName()),
+				mlog.String("method", method))
+			continue
+		}
+		return method
+	}
+	return policy.Method
+}
+
+// mergeCommitFields are the fields of a PR the merge commit templates are
+// rendered from.
+type mergeCommitFields struct {
+	Title  string
+	Number int
+	Author string
+	// CoAuthors are the other authors of the commits of the PR, as
+	// "Name <email>".
+	CoAuthors []string
+	// Issues are the issues closed by the PR, as "#12" for issues of the
+	// same repository or "owner/repo#12" for others.
+	Issues []string
+}
+
+// mergeCommitMessage renders the title and body of the merge commit of the
+// PR from the templates of the policy.
+func (s *Server) mergeCommitMessage(ctx context.Context, pr *model.PullRequest, ghPR *github.PullRequest, policy *MergePolicy) (title, body string, err error) {
+	commits, err := s.getCommits(ctx, pr.RepoOwner, pr.RepoName, pr.Number)
+	if err != nil {
+		// The message is still worth having without the co-authors.
+		mlog.Warn("Error getting the commits of the PR to merge",
+			mlog.String("repo", pr.RepoOwner+"/"+pr.RepoName),
+			mlog.Int("pr", pr.Number),
+			mlog.Err(err))
+	}
+
+	author := ghPR.GetUser().GetLogin()
+	fields := mergeCommitFields{
+		Title:     ghPR.GetTitle(),
+		Number:    pr.Number,
+		Author:    author,
+		CoAuthors: coAuthors(commits, author),
+		Issues:    linkedIssues(ghPR.GetBody(), pr.RepoOwner, pr.RepoName),
+	}
+
+	title, err = renderMergeTemplate("title", policy.TitleTemplate, fields)
+	if err != nil {
+		return "", "", err
+	}
+	body, err = renderMergeTemplate("body", policy.BodyTemplate, fields)
+	if err != nil {
+		return "", "", err
+	}
+	return title, body, nil
+}
+
+func renderMergeTemplate(name, text string, fields mergeCommitFields) (string, error) {
+	tmpl, err := template.New(name).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("invalid merge commit %s template %q: %w", name, text, err)
+	}
+	var b strings.Builder
+	if err = tmpl.Execute(&b, fields); err != nil {
+		return "", fmt.Errorf("could not render the merge commit %s: %w", name, err)
+	}
+	return strings.TrimSpace(b.String()), nil
+}
+
+// coAuthors returns the authors of the commits other than the author of the
+// PR, along with the co-authors their messages credit, in order of
+// appearance. Authors are told apart by email.
+func coAuthors(commits []*github.RepositoryCommit, author string) []string {
+	var result []string
+	seen := map[string]bool{}
+	add := func(coAuthor, email string) {
+		email = strings.ToLower(email)
+		if email == "" || seen[email] {
+			return
+		}
+		seen[email] = true
+		result = append(result, coAuthor)
+	}
+
+	for _, commit := range commits {
+		commitAuthor := commit.GetCommit().GetAuthor()
+		if commit.GetAuthor().GetLogin() == author {
+			// Later commits of the PR author may use another name.
+			seen[strings.ToLower(commitAuthor.GetEmail())] = true
+		} else {
+			add(fmt.Sprintf("%s <%s>", commitAuthor.GetName(), commitAuthor.GetEmail()), commitAuthor.GetEmail())
+		}
+
+		for _, match := range coAuthorTrailerRegex.FindAllStringSubmatch(commit.GetCommit().GetMessage(), -1) {
+			coAuthor := match[1]
+			start, end := strings.LastIndex(coAuthor, "<"), strings.LastIndex(coAuthor, ">")
+			if start < 0 || end < start {
+				continue
+			}
+			add(coAuthor, coAuthor[start+1:end])
+		}
+	}
+	return result
+}
+
+// linkedIssues returns the issues the body of a PR closes, without
+// duplicates. Issues of the repository of the PR are shortened to "#12".
+func linkedIssues(body, repoOwner, repoName string) []string {
+	var issues []string
+	seen := map[string]bool{}
+	for _, match := range issueReferenceRegex.FindAllStringSubmatch(body, -1) {
+		repo, number := match[1], match[2]
+		if number == "" {
+			repo, number = match[3], match[4]
+		}
+		if strings.EqualFold(repo, repoOwner+"/"+repoName) {
+			repo = ""
+		}
+		issue := repo + "#" + number
+		if !seen[issue] {
+			seen[issue] = true
+			issues = append(issues, issue)
+		}
+	}
+	return issues
+}
@@ -0,0 +1,154 @@
+// Copyright (c) 2017-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package server
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/go-github/v39/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-mattermod/model"
+	"github.com/mattermost/mattermost-mattermod/server/mocks"
+)
+
+func TestMergePolicy(t *testing.T) {
+	s := &Server{Config: &Config{Repositories: []*Repository{
+		{Owner: "mattermost", Name: "server", Merge: &MergePolicy{Method: "merge", TitleTemplate: "{{.Title}}"}},
+		{Owner: "mattermost", Name: "desktop", Merge: &MergePolicy{Method: "fast-forward"}},
+	}}}
+
+	policy := s.mergePolicy("mattermost", "server")
+	assert.Equal(t, "merge", policy.Method)
+	assert.Equal(t, "{{.Title}}", policy.TitleTemplate)
+	assert.Equal(t, defaultMergeBodyTemplate, policy.BodyTemplate)
+
+	policy = s.mergePolicy("mattermost", "webapp")
+	assert.Equal(t, &MergePolicy{
+		Method:        "squash",
+		TitleTemplate: defaultMergeTitleTemplate,
+		BodyTemplate:  defaultMergeBodyTemplate,
+	}, policy)
+
+	policy = s.mergePolicy("mattermost", "desktop")
+	assert.Equal(t, defaultMergeMethod, policy.Method)
+}
+
+func TestMergeMethod(t *testing.T) {
+	policy := &MergePolicy{
+		Method:       "squash",
+		MethodLabels: map[string]string{"Merge/Rebase": "rebase", "Merge/Commit": "merge", "Merge/Octopus": "octopus"},
+	}
+	labels := func(names ...string) []*github.Label {
+		var result []*github.Label
+		for _, name := range names {
+			result = append(result, &github.Label{Name: github.String(name)})
+		}
+		return result
+	}
+
+	assert.Equal(t, "squash", mergeMethod(policy, nil))
+	assert.Equal(t, "squash", mergeMethod(policy, labels("2: Dev Review")))
+	assert.Equal(t, "rebase", mergeMethod(policy, labels("2: Dev Review", "Merge/Rebase")))
+	assert.Equal(t, "merge", mergeMethod(policy, labels("Merge/Commit", "Merge/Rebase")))
+	assert.Equal(t, "squash", mergeMethod(policy, labels("Merge/Octopus")))
+	assert.Equal(t, "rebase", mergeMethod(policy, labels("Merge/Octopus", "Merge/Rebase")))
+}
+
+func TestLinkedIssues(t *testing.T) {
+	body := "#### Summary\nFixes #12 and closes mattermost/mattermost-webapp#34.\n" +
+		"Resolves: https://github.com/mattermost/mattermost-server/issues/56\n" +
+		"fixed #12, related to #78\n" +
+		"Close https://github.com/Mattermost/Mattermost-Server/issues/90"
+
+	assert.Equal(t, []string{"#12", "mattermost/mattermost-webapp#34", "#56", "#90"}, linkedIssues(body, "mattermost", "mattermost-server"))
+	assert.Empty(t, linkedIssues("No issue, see #12", "mattermost", "mattermost-server"))
+}
+
+func TestCoAuthors(t *testing.T) {
+	commit := func(login, name, email, message string) *github.RepositoryCommit {
+		return &github.RepositoryCommit{
+			Author: &github.User{Login: github.String(login)},
+			Commit: &github.Commit{
+				Author:  &github.CommitAuthor{Name: github.String(name), Email: github.String(email)},
+				Message: github.String(message),
+			},
+		}
+	}
+	commits := []*github.RepositoryCommit{
+		commit("author", "Author", "author@example.com", "First commit"),
+		commit("helper", "Helper", "helper@example.com", "Second commit\n\nCo-authored-by: Pair <pair@example.com>\nco-authored-by: Author Again <AUTHOR@example.com>"),
+		commit("", "No Account", "no-account@example.com", "Third commit"),
+		commit("helper", "Helper Laptop", "Helper@Example.com", "Fourth commit\n\nCo-authored-by: not an email"),
+	}
+
+	assert.Equal(t, []string{
+		"Helper <helper@example.com>",
+		"Pair <pair@example.com>",
+		"No Account <no-account@example.com>",
+	}, coAuthors(commits, "author"))
+}
+
+func TestMergeCommitMessage(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctxInterface := reflect.TypeOf((*context.Context)(nil)).Elem()
+	prs := mocks.NewMockPullRequestsService(ctrl)
+	s := &Server{GithubClient: &GithubClient{PullRequests: prs}}
+	ok := &github.Response{Response: &http.Response{StatusCode: http.StatusOK}}
+
+	pr := &model.PullRequest{RepoOwner: "mattermost", RepoName: "server", Number: 12}
+	ghPR := &github.PullRequest{
+		Title: github.String("MM-123 Fix the channel sidebar"),
+		Body:  github.String("Fixes #10\nFixes mattermost/webapp#11"),
+		User:  &github.User{Login: github.String("author")},
+	}
+	expectCommits := func() {
+		prs.EXPECT().ListCommits(gomock.AssignableToTypeOf(ctxInterface), "mattermost", "server", 12, gomock.Any()).
+			Return([]*github.RepositoryCommit{{
+				Author: &github.User{Login: github.String("helper")},
+				Commit: &github.Commit{Author: &github.CommitAuthor{Name: github.String("Helper"), Email: github.String("helper@example.com")}},
+			}}, ok, nil)
+	}
+
+	t.Run("Default templates", func(t *testing.T) {
+		expectCommits()
+
+		title, body, err := s.mergeCommitMessage(context.Background(), pr, ghPR, &MergePolicy{
+			TitleTemplate: defaultMergeTitleTemplate,
+			BodyTemplate:  defaultMergeBodyTemplate,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "MM-123 Fix the channel sidebar (#12)", title)
+		assert.Equal(t, "Fixes #10\nFixes mattermost/webapp#11\n\nCo-authored-by: Helper <helper@example.com>", body)
+	})
+
+	t.Run("Custom templates", func(t *testing.T) {
+		expectCommits()
+
+		title, body, err := s.mergeCommitMessage(context.Background(), pr, ghPR, &MergePolicy{
+			TitleTemplate: "[#{{.Number}}] {{.Title}}",
+			BodyTemplate:  "Author: @{{.Author}}\nCo-authors: {{len .CoAuthors}}",
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "[#12] MM-123 Fix the channel sidebar", title)
+		assert.Equal(t, "Author: @author\nCo-authors: 1", body)
+	})
+
+	t.Run("Invalid templates", func(t *testing.T) {
+		expectCommits()
+
+		_, _, err := s.mergeCommitMessage(context.Background(), pr, ghPR, &MergePolicy{
+			TitleTemplate: "{{.Milestone}}",
+			BodyTemplate:  defaultMergeBodyTemplate,
+		})
+		require.Error(t, err)
+	})
+}
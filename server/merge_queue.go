@@ -0,0 +1,281 @@
+// Copyright (c) 2017-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/go-github/v39/github"
+	"github.com/mattermost/mattermost-mattermod/model"
+	"github.com/mattermost/mattermost-server/v6/shared/mlog"
+)
+
+// mergeQueueContext is the status telling the place of a PR in the merge
+// queue. It is always successful, so that it never holds back the combined
+// status of the PR it reports on.
+const mergeQueueContext = "merge-queue"
+
+// queuedPR is a PR in the merge queue, along with its current state on
+// GitHub.
+type queuedPR struct {
+	pr   *model.PullRequest
+	ghPR *github.PullRequest
+}
+
+// runMergeQueues merges the PRs with the auto merge label one at a time per
+// base branch, in the order they joined the queue. The first PR which isn't
+// blocked is brought up to date with its base branch and merged once its
+// checks passed on the new head. The others are told their position.
+func (s *Server) runMergeQueues(ctx context.Context, prs []*model.PullRequest) error {
+	s.mergeQueueLock.Lock()
+	defer s.mergeQueueLock.Unlock()
+
+	queued, err := s.syncMergeQueue(prs)
+	if err != nil {
+		return err
+	}
+
+	branches, queues := s.mergeQueuesByBranch(ctx, queued)
+	for _, branch := range branches {
+		s.advanceMergeQueue(ctx, queues[branch])
+	}
+	return nil
+}
+
+// advanceMergeQueuesOf advances the merge queues holding the queued PRs whose
+// head is at sha, so that a finished check doesn't wait for the next run.
+// PRs further back become the head of their queue as soon as the PRs ahead of
+// them are blocked, so their checks move the queue as well.
+func (s *Server) advanceMergeQueuesOf(ctx context.Context, owner, name, sha string) error {
+	prs, err := s.Store.PullRequest().ListBySha(owner, name, sha)
+	if err != nil {
+		return err
+	}
+	checked := map[int]bool{}
+	for _, pr := range s.autoMergePRs(prs) {
+		if pr.State == model.StateOpen {
+			checked[pr.Number] = true
+		}
+	}
+	if len(checked) == 0 {
+		return nil
+	}
+
+	open, err := s.Store.PullRequest().ListOpen()
+	if err != nil {
+		return fmt.Errorf("error while listing open PRs %w", err)
+	}
+
+	s.mergeQueueLock.Lock()
+	defer s.mergeQueueLock.Unlock()
+
+	queued, err := s.syncMergeQueue(s.autoMergePRs(open))
+	if err != nil {
+		return err
+	}
+	var repoQueued []*model.PullRequest
+	for _, pr := range queued {
+		if pr.RepoOwner == owner && pr.RepoName == name {
+			repoQueued = append(repoQueued, pr)
+		}
+	}
+
+	branches, queues := s.mergeQueuesByBranch(ctx, repoQueued)
+	for _, branch := range branches {
+		for _, q := range queues[branch] {
+			if checked[q.pr.Number] {
+				s.advanceMergeQueue(ctx, queues[branch])
+				break
+			}
+		}
+	}
+	return nil
+}
+
+// mergeQueuesByBranch gets the queued PRs from GitHub and splits them by base
+// branch, keeping the queue order. PRs which can't be fetched are left out.
+func (s *Server) mergeQueuesByBranch(ctx context.Context, queued []*model.PullRequest) ([]string, map[string][]*queuedPR) {
+	var branches []string
+	queues := map[string][]*queuedPR{}
+	for _, pr := range queued {
+		ghPR, _, err := s.GithubClient.PullRequests.Get(ctx, pr.RepoOwner, pr.RepoName, pr.Number)
+		if err != nil {
+			mlog.Error("Error getting the queued PR",
+				mlog.String("repo", pr.RepoOwner+"/"+pr.RepoName),
+				mlog.Int("pr", pr.Number),
+				mlog.Err(err))
+			continue
+		}
+		branch := pr.RepoOwner + "/" + pr.RepoName + ":" + ghPR.GetBase().GetRef()
+		if _, ok := queues[branch]; !ok {
+			branches = append(branches, branch)
+		}
+		queues[branch] = append(queues[branch], &queuedPR{pr: pr, ghPR: ghPR})
+	}
+	return branches, queues
+}
+
+// syncMergeQueue adds the PRs which aren't queued yet to the end of the queue
+// and removes the queued PRs which aren't in prs anymore. It returns the PRs
+// in queue order.
+func (s *Server) syncMergeQueue(prs []*model.PullRequest) ([]*model.PullRequest, error) {
+	entries, err := s.Store.MergeQueue().List()
+	if err != nil {
+		return nil, fmt.Errorf("error while listing the merge queue: %w", err)
+	}
+
+	key := func(owner, name string, number int) string {
+		return fmt.Sprintf("%s/%s#%d", owner, name, number)
+	}
+	byKey := map[string]*model.PullRequest{}
+	for _, pr := range prs {
+		byKey[key(pr.RepoOwner, pr.RepoName, pr.Number)] = pr
+	}
+
+	var queued []*model.PullRequest
+	inQueue := map[string]bool{}
+	for _, entry := range entries {
+		k := key(entry.RepoOwner, entry.RepoName, entry.Number)
+		if pr, ok := byKey[k]; ok {
+			queued = append(queued, pr)
+			inQueue[k] = true
+			continue
+		}
+		if err = s.Store.MergeQueue().Delete(entry.RepoOwner, entry.RepoName, entry.Number); err != nil {
+			return nil, err
+		}
+	}
+	for _, pr := range prs {
+		if inQueue[key(pr.RepoOwner, pr.RepoName, pr.Number)] {
+			continue
+		}
+		if err = s.Store.MergeQueue().Add(&model.MergeQueueEntry{RepoOwner: pr.RepoOwner, RepoName: pr.RepoName, Number: pr.Number}); err != nil {
+			return nil, err
+		}
+		queued = append(queued, pr)
+	}
+	return queued, nil
+}
+
+// advanceMergeQueue moves the queue of one base branch forward. Blocked PRs
+// are skipped until they are unblocked, so that they don't hold up the PRs
+// behind them.
+func (s *Server) advanceMergeQueue(ctx context.Context, queue []*queuedPR) {
+	position := 0
+	for _, q := range queue {
+		base := q.ghPR.GetBase().GetRef()
+		if position > 0 {
+			position++
+			s.setMergeQueueStatus(ctx, q, fmt.Sprintf("Position %d in the merge queue of %s", position, base))
+			continue
+		}
+		if s.advanceMergeQueueHead(ctx, q) {
+			position = 1
+		}
+	}
+}
+
+// advanceMergeQueueHead updates the branch of the first PR of the queue or
+// merges it. It returns whether the PR keeps its place at the head of the
+// queue, which is the case as long as its branch is being updated or its
+// checks are running.
+func (s *Server) advanceMergeQueueHead(ctx context.Context, q *queuedPR) bool {
+	pr, base := q.pr, q.ghPR.GetBase().GetRef()
+	readiness, err := s.assessMergeReadiness(ctx, pr, q.ghPR)
+	if err != nil {
+		// Skipping the PR would let the ones behind it jump the queue.
+		mlog.Error("Error checking if the queued PR is ready to merge",
+			mlog.String("repo", pr.RepoOwner+"/"+pr.RepoName),
+			mlog.Int("pr", pr.Number),
+			mlog.Err(err))
+		return true
+	}
+	if q.ghPR.GetState() == model.StateClosed {
+		return false
+	}
+	if readiness.reason != "" && !readiness.waiting {
+		s.setMergeQueueStatus(ctx, q, fmt.Sprintf("Skipped in the merge queue of %s: %s", base, readiness.reason))
+		return false
+	}
+
+	comparison, _, err := s.GithubClient.Repositories.CompareCommits(ctx, pr.RepoOwner, pr.RepoName, base, q.ghPR.GetHead().GetSHA(), nil)
+	if err != nil {
+		mlog.Error("Error comparing the queued PR with its base branch",
+			mlog.String("repo", pr.RepoOwner+"/"+pr.RepoName),
+			mlog.Int("pr", pr.Number),
+			mlog.Err(err))
+		return true
+	}
+	if comparison.GetBehindBy() > 0 {
+		_, _, err = s.GithubClient.PullRequests.UpdateBranch(ctx, pr.RepoOwner, pr.RepoName, pr.Number, &github.PullRequestBranchUpdateOptions{
+			ExpectedHeadSHA: github.String(q.ghPR.GetHead().GetSHA()),
+		})
+		var acceptedErr *github.AcceptedError
+		if err != nil && !errors.As(err, &acceptedErr) {
+			mlog.Warn("Error updating the branch of the queued PR",
+				mlog.String("repo", pr.RepoOwner+"/"+pr.RepoName),
+				mlog.Int("pr", pr.Number),
+				mlog.Err(err))
+			s.setMergeQueueStatus(ctx, q, fmt.Sprintf("Skipped in the merge queue of %s: the branch could not be updated", base))
+			return false
+		}
+		s.setMergeQueueStatus(ctx, q, fmt.Sprintf("Updating the branch, next to merge into %s", base))
+		return true
+	}
+
+	if readiness.waiting {
+		s.setMergeQueueStatus(ctx, q, fmt.Sprintf("Next to merge into %s: %s", base, readiness.reason))
+		return true
+	}
+
+	method := mergeMethod(s.mergePolicy(pr.RepoOwner, pr.RepoName), q.ghPR.Labels)
+	if err = s.mergePR(ctx, pr, q.ghPR, method); err != nil {
+		// The error has been commented on the PR already. Trying again on
+		// every run wouldn't help, the PR leaves the queue until it's
+		// labeled again.
+		s.removeFromMergeQueue(ctx, q)
+		s.setMergeQueueStatus(ctx, q, fmt.Sprintf("Removed from the merge queue of %s: the merge failed", base))
+		return false
+	}
+	if err = s.Store.MergeQueue().Delete(pr.RepoOwner, pr.RepoName, pr.Number); err != nil {
+		mlog.Warn("Error removing the merged PR from the merge queue", mlog.Int("pr", pr.Number), mlog.Err(err))
+	}
+	return false
+}
+
+// removeFromMergeQueue removes the auto merge label of the queued PR, and the
+// PR from the queue. Failures are logged, the PR is then tried again on the
+// next run.
+func (s *Server) removeFromMergeQueue(ctx context.Context, q *queuedPR) {
+	pr := q.pr
+	if _, err := s.GithubClient.Issues.RemoveLabelForIssue(ctx, pr.RepoOwner, pr.RepoName, pr.Number, s.Config.AutoPRMergeLabel); err != nil {
+		mlog.Warn("Error removing the auto merge label of the queued PR",
+			mlog.String("repo", pr.RepoOwner+"/"+pr.RepoName),
+			mlog.Int("pr", pr.Number),
+			mlog.Err(err))
+		return
+	}
+	if err := s.Store.MergeQueue().Delete(pr.RepoOwner, pr.RepoName, pr.Number); err != nil {
+		mlog.Warn("Error removing the PR from the merge queue", mlog.Int("pr", pr.Number), mlog.Err(err))
+	}
+}
+
+// setMergeQueueStatus reports the state of the queued PR on its head.
+// Failures are logged, they don't change the queue.
+func (s *Server) setMergeQueueStatus(ctx context.Context, q *queuedPR, description string) {
+	status := &github.RepoStatus{
+		Context:     github.String(mergeQueueContext),
+		State:       github.String(stateSuccess),
+		Description: github.String(truncateStatusDescription(description)),
+		TargetURL:   github.String(""),
+	}
+	if _, _, err := s.GithubClient.Repositories.CreateStatus(ctx, q.pr.RepoOwner, q.pr.RepoName, q.ghPR.GetHead().GetSHA(), status); err != nil {
+		mlog.Warn("Error setting the merge queue status",
+			mlog.String("repo", q.pr.RepoOwner+"/"+q.pr.RepoName),
+			mlog.Int("pr", q.pr.Number),
+			mlog.Err(err))
+	}
+}
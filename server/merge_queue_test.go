@@ -0,0 +1,274 @@
+// Copyright (c) 2017-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/go-github/v39/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-mattermod/model"
+	"github.com/mattermost/mattermost-mattermod/server/mocks"
+	stmock "github.com/mattermost/mattermost-mattermod/store/mocks"
+)
+
+func TestSyncMergeQueue(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mqStore := stmock.NewMockMergeQueueStore(ctrl)
+	ss := stmock.NewMockStore(ctrl)
+	ss.EXPECT().MergeQueue().Return(mqStore).AnyTimes()
+	s := &Server{Store: ss}
+
+	first := &model.PullRequest{RepoOwner: "mattermost", RepoName: "server", Number: 1}
+	second := &model.PullRequest{RepoOwner: "mattermost", RepoName: "server", Number: 2}
+	third := &model.PullRequest{RepoOwner: "mattermost", RepoName: "webapp", Number: 2}
+
+	mqStore.EXPECT().List().Return([]*model.MergeQueueEntry{
+		{RepoOwner: "mattermost", RepoName: "server", Number: 2},
+		{RepoOwner: "mattermost", RepoName: "server", Number: 3},
+		{RepoOwner: "mattermost", RepoName: "server", Number: 1},
+	}, nil)
+	mqStore.EXPECT().Delete("mattermost", "server", 3).Return(nil)
+	mqStore.EXPECT().Add(&model.MergeQueueEntry{RepoOwner: "mattermost", RepoName: "webapp", Number: 2}).Return(nil)
+
+	queued, err := s.syncMergeQueue([]*model.PullRequest{first, third, second})
+	require.NoError(t, err)
+	assert.Equal(t, []*model.PullRequest{second, first, third}, queued)
+}
+
+func TestAdvanceMergeQueue(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctxInterface := reflect.TypeOf((*context.Context)(nil)).Elem()
+	mqStore := stmock.NewMockMergeQueueStore(ctrl)
+	ss := stmock.NewMockStore(ctrl)
+	ss.EXPECT().MergeQueue().Return(mqStore).AnyTimes()
+	repos := mocks.NewMockRepositoriesService(ctrl)
+	prs := mocks.NewMockPullRequestsService(ctrl)
+	issues := mocks.NewMockIssuesService(ctrl)
+	s := &Server{
+		Config: &Config{
+			AutoPRMergeLabel: "Merge/Auto",
+			Repositories: []*Repository{{
+				Owner: "mattermost",
+				Name:  "server",
+				Merge: &MergePolicy{MethodLabels: map[string]string{"Merge/Rebase": "rebase"}},
+			}},
+		},
+		Store:        ss,
+		GithubClient: &GithubClient{Repositories: repos, PullRequests: prs, Issues: issues},
+	}
+
+	queued := func(number int, mergeableState string) *queuedPR {
+		sha := fmt.Sprintf("sha%d", number)
+		return &queuedPR{
+			pr: &model.PullRequest{RepoOwner: "mattermost", RepoName: "server", Number: number},
+			ghPR: &github.PullRequest{
+				Number:         github.Int(number),
+				Title:          github.String("Fix the thing"),
+				State:          github.String("open"),
+				MergeableState: github.String(mergeableState),
+				Head:           &github.PullRequestBranch{SHA: github.String(sha)},
+				Base:           &github.PullRequestBranch{Ref: github.String("master")},
+			},
+		}
+	}
+	notProtected := &github.Response{Response: &http.Response{StatusCode: http.StatusNotFound}}
+	expectStatus := func(q *queuedPR, state string) {
+		sha := q.ghPR.GetHead().GetSHA()
+		repos.EXPECT().GetCombinedStatus(gomock.AssignableToTypeOf(ctxInterface), "mattermost", "server", sha, nil).
+			Return(&github.CombinedStatus{
+				SHA:      github.String(sha),
+				State:    github.String(state),
+				Statuses: []*github.RepoStatus{{Context: github.String("ci"), State: github.String(state)}},
+			}, nil, nil)
+		repos.EXPECT().GetBranchProtection(gomock.AssignableToTypeOf(ctxInterface), "mattermost", "server", "master").
+			Return(nil, notProtected, errBranchNotProtected)
+	}
+	expectReviews := func(q *queuedPR) {
+		ok := &github.Response{Response: &http.Response{StatusCode: http.StatusOK}}
+		prs.EXPECT().ListReviews(gomock.AssignableToTypeOf(ctxInterface), "mattermost", "server", q.pr.Number, gomock.Any()).Return(nil, ok, nil)
+		// Pending reviewers are only asked for once the merge state is clean.
+		if q.ghPR.GetMergeableState() == model.MergeableStateClean {
+			prs.EXPECT().ListReviewers(gomock.AssignableToTypeOf(ctxInterface), "mattermost", "server", q.pr.Number, nil).Return(&github.Reviewers{}, nil, nil)
+		}
+	}
+	expectBehindBy := func(q *queuedPR, behindBy int) {
+		repos.EXPECT().CompareCommits(gomock.AssignableToTypeOf(ctxInterface), "mattermost", "server", "master", q.ghPR.GetHead().GetSHA(), nil).
+			Return(&github.CommitsComparison{BehindBy: github.Int(behindBy)}, nil, nil)
+	}
+	expectQueueStatus := func(q *queuedPR, description string) {
+		repos.EXPECT().CreateStatus(gomock.AssignableToTypeOf(ctxInterface), "mattermost", "server", q.ghPR.GetHead().GetSHA(), &github.RepoStatus{
+			Context:     github.String(mergeQueueContext),
+			State:       github.String(stateSuccess),
+			Description: github.String(description),
+			TargetURL:   github.String(""),
+		}).Return(nil, nil, nil)
+	}
+
+	t.Run("A head behind its base branch is updated", func(t *testing.T) {
+		head, next := queued(1, "behind"), queued(2, "clean")
+		expectStatus(head, stateSuccess)
+		expectReviews(head)
+		expectBehindBy(head, 3)
+		prs.EXPECT().UpdateBranch(gomock.AssignableToTypeOf(ctxInterface), "mattermost", "server", 1, &github.PullRequestBranchUpdateOptions{
+			ExpectedHeadSHA: github.String("sha1"),
+		}).Return(nil, nil, &github.AcceptedError{})
+		expectQueueStatus(head, "Updating the branch, next to merge into master")
+		expectQueueStatus(next, "Position 2 in the merge queue of master")
+
+		s.advanceMergeQueue(context.Background(), []*queuedPR{head, next})
+	})
+
+	t.Run("A head whose checks are running holds the queue", func(t *testing.T) {
+		head, next := queued(1, "blocked"), queued(2, "clean")
+		expectStatus(head, statePending)
+		expectBehindBy(head, 0)
+		expectQueueStatus(head, "Next to merge into master: the combined status is `pending`")
+		expectQueueStatus(next, "Position 2 in the merge queue of master")
+
+		s.advanceMergeQueue(context.Background(), []*queuedPR{head, next})
+	})
+
+	t.Run("Blocked PRs are skipped", func(t *testing.T) {
+		blocked, conflicting, head, next := queued(1, "blocked"), queued(2, "dirty"), queued(3, "clean"), queued(4, "clean")
+		repos.EXPECT().GetCombinedStatus(gomock.AssignableToTypeOf(ctxInterface), "mattermost", "server", "sha1", nil).
+			Return(&github.CombinedStatus{
+				SHA:      github.String("sha1"),
+				State:    github.String(statePending),
+				Statuses: []*github.RepoStatus{{Context: github.String(mergeBlockedContext), State: github.String(statePending)}},
+			}, nil, nil)
+		expectQueueStatus(blocked, "Skipped in the merge queue of master: the merge is blocked")
+		expectQueueStatus(conflicting, "Skipped in the merge queue of master: the merge state is `dirty`")
+		expectStatus(head, statePending)
+		expectBehindBy(head, 0)
+		expectQueueStatus(head, "Next to merge into master: the combined status is `pending`")
+		expectQueueStatus(next, "Position 2 in the merge queue of master")
+
+		s.advanceMergeQueue(context.Background(), []*queuedPR{blocked, conflicting, head, next})
+	})
+
+	t.Run("A ready head is merged and leaves the queue", func(t *testing.T) {
+		head, next := queued(1, "clean"), queued(2, "clean")
+		head.ghPR.Labels = []*github.Label{{Name: github.String("Merge/Rebase")}}
+		expectStatus(head, stateSuccess)
+		expectReviews(head)
+		expectBehindBy(head, 0)
+		issues.EXPECT().CreateComment(gomock.AssignableToTypeOf(ctxInterface), "mattermost", "server", 1, gomock.Any()).Return(nil, nil, nil)
+		prs.EXPECT().ListCommits(gomock.AssignableToTypeOf(ctxInterface), "mattermost", "server", 1, gomock.Any()).
+			Return(nil, &github.Response{Response: &http.Response{StatusCode: http.StatusOK}}, nil)
+		prs.EXPECT().Merge(gomock.AssignableToTypeOf(ctxInterface), "mattermost", "server", 1, "", &github.PullRequestOptions{
+			CommitTitle:        "Fix the thing (#1)",
+			SHA:                "sha1",
+			MergeMethod:        "rebase",
+			DontDefaultIfBlank: true,
+		}).Return(&github.PullRequestMergeResult{SHA: github.String("merged")}, nil, nil)
+		mqStore.EXPECT().Delete("mattermost", "server", 1).Return(nil)
+		// The next PR becomes the head once the base branch moved.
+		expectStatus(next, stateSuccess)
+		expectReviews(next)
+		expectBehindBy(next, 1)
+		prs.EXPECT().UpdateBranch(gomock.AssignableToTypeOf(ctxInterface), "mattermost", "server", 2, gomock.Any()).Return(nil, nil, nil)
+		expectQueueStatus(next, "Updating the branch, next to merge into master")
+
+		s.advanceMergeQueue(context.Background(), []*queuedPR{head, next})
+	})
+
+	t.Run("A head failing to merge leaves the queue", func(t *testing.T) {
+		head, next := queued(1, "clean"), queued(2, "clean")
+		expectStatus(head, stateSuccess)
+		expectReviews(head)
+		expectBehindBy(head, 0)
+		issues.EXPECT().CreateComment(gomock.AssignableToTypeOf(ctxInterface), "mattermost", "server", 1, gomock.Any()).Return(nil, nil, nil)
+		prs.EXPECT().ListCommits(gomock.AssignableToTypeOf(ctxInterface), "mattermost", "server", 1, gomock.Any()).
+			Return(nil, &github.Response{Response: &http.Response{StatusCode: http.StatusOK}}, nil)
+		prs.EXPECT().Merge(gomock.AssignableToTypeOf(ctxInterface), "mattermost", "server", 1, "", gomock.Any()).
+			Return(nil, nil, errors.New("merge commits are not allowed"))
+		issues.EXPECT().RemoveLabelForIssue(gomock.AssignableToTypeOf(ctxInterface), "mattermost", "server", 1, "Merge/Auto").Return(nil, nil)
+		mqStore.EXPECT().Delete("mattermost", "server", 1).Return(nil)
+		expectQueueStatus(head, "Removed from the merge queue of master: the merge failed")
+		expectStatus(next, stateSuccess)
+		expectReviews(next)
+		expectBehindBy(next, 1)
+		prs.EXPECT().UpdateBranch(gomock.AssignableToTypeOf(ctxInterface), "mattermost", "server", 2, gomock.Any()).Return(nil, nil, nil)
+		expectQueueStatus(next, "Updating the branch, next to merge into master")
+
+		s.advanceMergeQueue(context.Background(), []*queuedPR{head, next})
+	})
+}
+
+func TestAdvanceMergeQueuesOf(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctxInterface := reflect.TypeOf((*context.Context)(nil)).Elem()
+	prStore := stmock.NewMockPullRequestStore(ctrl)
+	mqStore := stmock.NewMockMergeQueueStore(ctrl)
+	ss := stmock.NewMockStore(ctrl)
+	ss.EXPECT().PullRequest().Return(prStore).AnyTimes()
+	ss.EXPECT().MergeQueue().Return(mqStore).AnyTimes()
+	repos := mocks.NewMockRepositoriesService(ctrl)
+	prs := mocks.NewMockPullRequestsService(ctrl)
+	s := &Server{
+		Config:       &Config{AutoPRMergeLabel: "Merge/Auto"},
+		Store:        ss,
+		GithubClient: &GithubClient{Repositories: repos, PullRequests: prs},
+	}
+
+	queued := &model.PullRequest{RepoOwner: "mattermost", RepoName: "server", Number: 1, State: model.StateOpen, Labels: []string{"Merge/Auto"}}
+	other := &model.PullRequest{RepoOwner: "mattermost", RepoName: "webapp", Number: 2, State: model.StateOpen, Labels: []string{"Merge/Auto"}}
+
+	t.Run("Checks of PRs outside the queue are ignored", func(t *testing.T) {
+		prStore.EXPECT().ListBySha("mattermost", "server", "sha1").
+			Return([]*model.PullRequest{{RepoOwner: "mattermost", RepoName: "server", Number: 3, State: model.StateOpen}}, nil)
+
+		require.NoError(t, s.advanceMergeQueuesOf(context.Background(), "mattermost", "server", "sha1"))
+	})
+
+	t.Run("Checks of queued PRs advance the queue of their base branch", func(t *testing.T) {
+		prStore.EXPECT().ListBySha("mattermost", "server", "sha1").Return([]*model.PullRequest{queued}, nil)
+		prStore.EXPECT().ListOpen().Return([]*model.PullRequest{queued, other}, nil)
+		mqStore.EXPECT().List().Return([]*model.MergeQueueEntry{
+			{RepoOwner: "mattermost", RepoName: "webapp", Number: 2},
+			{RepoOwner: "mattermost", RepoName: "server", Number: 1},
+		}, nil)
+		// Only the queues of the repository of the check are advanced.
+		prs.EXPECT().Get(gomock.AssignableToTypeOf(ctxInterface), "mattermost", "server", 1).Return(&github.PullRequest{
+			Number:         github.Int(1),
+			State:          github.String("open"),
+			MergeableState: github.String("blocked"),
+			Head:           &github.PullRequestBranch{SHA: github.String("sha1")},
+			Base:           &github.PullRequestBranch{Ref: github.String("master")},
+		}, nil, nil)
+		repos.EXPECT().GetCombinedStatus(gomock.AssignableToTypeOf(ctxInterface), "mattermost", "server", "sha1", nil).
+			Return(&github.CombinedStatus{
+				SHA:      github.String("sha1"),
+				State:    github.String(statePending),
+				Statuses: []*github.RepoStatus{{Context: github.String("ci"), State: github.String(statePending)}},
+			}, nil, nil)
+		repos.EXPECT().GetBranchProtection(gomock.AssignableToTypeOf(ctxInterface), "mattermost", "server", "master").
+			Return(nil, &github.Response{Response: &http.Response{StatusCode: http.StatusNotFound}}, errBranchNotProtected)
+		repos.EXPECT().CompareCommits(gomock.AssignableToTypeOf(ctxInterface), "mattermost", "server", "master", "sha1", nil).
+			Return(&github.CommitsComparison{BehindBy: github.Int(0)}, nil, nil)
+		repos.EXPECT().CreateStatus(gomock.AssignableToTypeOf(ctxInterface), "mattermost", "server", "sha1", &github.RepoStatus{
+			Context:     github.String(mergeQueueContext),
+			State:       github.String(stateSuccess),
+			Description: github.String("Next to merge into master: the combined status is `pending`"),
+			TargetURL:   github.String(""),
+		}).Return(nil, nil, nil)
+
+		require.NoError(t, s.advanceMergeQueuesOf(context.Background(), "mattermost", "server", "sha1"))
+	})
+}
@@ -25,6 +25,9 @@ type MetricsProvider interface {
 	IncreaseWebhookRequest(name string)
 	// IncreaseWebhookErrors stores the number of errors identified by name
 	IncreaseWebhookErrors(name string)
+	// IncreaseWebhookSignatureValidations increases the counter for webhook requests
+	// validated by the secret identified by secretID and the given algorithm
+	IncreaseWebhookSignatureValidations(secretID, algorithm string)
 
 	// ObserveGithubRequestDuration stores the elapsed time for github requests
 	ObserveGithubRequestDuration(method, handler, statusCode string, elapsed float64)
@@ -43,6 +46,17 @@ type MetricsProvider interface {
 	ObserveCronTaskDuration(name string, elapsed float64)
 	// IncreaseCronTaskErrors stores the number of errors for a cron task
 	IncreaseCronTaskErrors(name string)
+
+	// ObserveAutomationDuration stores the elapsed time for running the hook
+	// of an automation
+	ObserveAutomationDuration(name, hook string, elapsed float64)
+	// IncreaseAutomationErrors stores the number of errors returned by the hook
+	// of an automation
+	IncreaseAutomationErrors(name, hook string)
+
+	// IncreaseCommandDenials stores the number of times a command was not run
+	// because the commenter wasn't allowed to
+	IncreaseCommandDenials(name string)
 }
 
 // Transport is an HTTP transport that would check
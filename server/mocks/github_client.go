@@ -122,6 +122,22 @@ func (mr *MockIssuesServiceMockRecorder) CreateComment(ctx, owner, repo, number,
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateComment", reflect.TypeOf((*MockIssuesService)(nil).CreateComment), ctx, owner, repo, number, comment)
 }
 
+// CreateIssueCommentReaction mocks base method.
+func (m *MockIssuesService) CreateIssueCommentReaction(ctx context.Context, owner, repo string, id int64, content string) (*github.Reaction, *github.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateIssueCommentReaction", ctx, owner, repo, id, content)
+	ret0, _ := ret[0].(*github.Reaction)
+	ret1, _ := ret[1].(*github.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// CreateIssueCommentReaction indicates an expected call of CreateIssueCommentReaction.
+func (mr *MockIssuesServiceMockRecorder) CreateIssueCommentReaction(ctx, owner, repo, id, content interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateIssueCommentReaction", reflect.TypeOf((*MockIssuesService)(nil).CreateIssueCommentReaction), ctx, owner, repo, id, content)
+}
+
 // DeleteComment mocks base method.
 func (m *MockIssuesService) DeleteComment(ctx context.Context, owner, repo string, commentID int64) (*github.Response, error) {
 	m.ctrl.T.Helper()
@@ -153,6 +169,22 @@ func (mr *MockIssuesServiceMockRecorder) Edit(ctx, owner, repo, number, issue in
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Edit", reflect.TypeOf((*MockIssuesService)(nil).Edit), ctx, owner, repo, number, issue)
 }
 
+// EditComment mocks base method.
+func (m *MockIssuesService) EditComment(ctx context.Context, owner, repo string, commentID int64, comment *github.IssueComment) (*github.IssueComment, *github.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EditComment", ctx, owner, repo, commentID, comment)
+	ret0, _ := ret[0].(*github.IssueComment)
+	ret1, _ := ret[1].(*github.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// EditComment indicates an expected call of EditComment.
+func (mr *MockIssuesServiceMockRecorder) EditComment(ctx, owner, repo, commentID, comment interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EditComment", reflect.TypeOf((*MockIssuesService)(nil).EditComment), ctx, owner, repo, commentID, comment)
+}
+
 // Get mocks base method.
 func (m *MockIssuesService) Get(ctx context.Context, owner, repo string, number int) (*github.Issue, *github.Response, error) {
 	m.ctrl.T.Helper()
@@ -428,6 +460,22 @@ func (m *MockPullRequestsService) EXPECT() *MockPullRequestsServiceMockRecorder
 	return m.recorder
 }
 
+// Create mocks base method.
+func (m *MockPullRequestsService) Create(ctx context.Context, owner, repo string, pull *github.NewPullRequest) (*github.PullRequest, *github.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, owner, repo, pull)
+	ret0, _ := ret[0].(*github.PullRequest)
+	ret1, _ := ret[1].(*github.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockPullRequestsServiceMockRecorder) Create(ctx, owner, repo, pull interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockPullRequestsService)(nil).Create), ctx, owner, repo, pull)
+}
+
 // CreateReview mocks base method.
 func (m *MockPullRequestsService) CreateReview(ctx context.Context, owner, repo string, number int, review *github.PullRequestReviewRequest) (*github.PullRequestReview, *github.Response, error) {
 	m.ctrl.T.Helper()
@@ -476,6 +524,22 @@ func (mr *MockPullRequestsServiceMockRecorder) List(ctx, owner, repo, opts inter
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockPullRequestsService)(nil).List), ctx, owner, repo, opts)
 }
 
+// ListCommits mocks base method.
+func (m *MockPullRequestsService) ListCommits(ctx context.Context, owner, repo string, number int, opts *github.ListOptions) ([]*github.RepositoryCommit, *github.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListCommits", ctx, owner, repo, number, opts)
+	ret0, _ := ret[0].([]*github.RepositoryCommit)
+	ret1, _ := ret[1].(*github.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListCommits indicates an expected call of ListCommits.
+func (mr *MockPullRequestsServiceMockRecorder) ListCommits(ctx, owner, repo, number, opts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCommits", reflect.TypeOf((*MockPullRequestsService)(nil).ListCommits), ctx, owner, repo, number, opts)
+}
+
 // ListFiles mocks base method.
 func (m *MockPullRequestsService) ListFiles(ctx context.Context, owner, repo string, number int, opts *github.ListOptions) ([]*github.CommitFile, *github.Response, error) {
 	m.ctrl.T.Helper()
@@ -595,6 +659,22 @@ func (m *MockRepositoriesService) EXPECT() *MockRepositoriesServiceMockRecorder
 	return m.recorder
 }
 
+// CompareCommits mocks base method.
+func (m *MockRepositoriesService) CompareCommits(ctx context.Context, owner, repo, base, head string, opts *github.ListOptions) (*github.CommitsComparison, *github.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompareCommits", ctx, owner, repo, base, head, opts)
+	ret0, _ := ret[0].(*github.CommitsComparison)
+	ret1, _ := ret[1].(*github.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// CompareCommits indicates an expected call of CompareCommits.
+func (mr *MockRepositoriesServiceMockRecorder) CompareCommits(ctx, owner, repo, base, head, opts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompareCommits", reflect.TypeOf((*MockRepositoriesService)(nil).CompareCommits), ctx, owner, repo, base, head, opts)
+}
+
 // CreateStatus mocks base method.
 func (m *MockRepositoriesService) CreateStatus(ctx context.Context, owner, repo, ref string, status *github.RepoStatus) (*github.RepoStatus, *github.Response, error) {
 	m.ctrl.T.Helper()
@@ -643,6 +723,22 @@ func (mr *MockRepositoriesServiceMockRecorder) GetBranch(ctx, owner, repo, branc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBranch", reflect.TypeOf((*MockRepositoriesService)(nil).GetBranch), ctx, owner, repo, branch, followRedirects)
 }
 
+// GetBranchProtection mocks base method.
+func (m *MockRepositoriesService) GetBranchProtection(ctx context.Context, owner, repo, branch string) (*github.Protection, *github.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBranchProtection", ctx, owner, repo, branch)
+	ret0, _ := ret[0].(*github.Protection)
+	ret1, _ := ret[1].(*github.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetBranchProtection indicates an expected call of GetBranchProtection.
+func (mr *MockRepositoriesServiceMockRecorder) GetBranchProtection(ctx, owner, repo, branch interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBranchProtection", reflect.TypeOf((*MockRepositoriesService)(nil).GetBranchProtection), ctx, owner, repo, branch)
+}
+
 // GetCombinedStatus mocks base method.
 func (m *MockRepositoriesService) GetCombinedStatus(ctx context.Context, owner, repo, ref string, opts *github.ListOptions) (*github.CombinedStatus, *github.Response, error) {
 	m.ctrl.T.Helper()
@@ -659,6 +755,22 @@ func (mr *MockRepositoriesServiceMockRecorder) GetCombinedStatus(ctx, owner, rep
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCombinedStatus", reflect.TypeOf((*MockRepositoriesService)(nil).GetCombinedStatus), ctx, owner, repo, ref, opts)
 }
 
+// GetPermissionLevel mocks base method.
+func (m *MockRepositoriesService) GetPermissionLevel(ctx context.Context, owner, repo, user string) (*github.RepositoryPermissionLevel, *github.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPermissionLevel", ctx, owner, repo, user)
+	ret0, _ := ret[0].(*github.RepositoryPermissionLevel)
+	ret1, _ := ret[1].(*github.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetPermissionLevel indicates an expected call of GetPermissionLevel.
+func (mr *MockRepositoriesServiceMockRecorder) GetPermissionLevel(ctx, owner, repo, user interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPermissionLevel", reflect.TypeOf((*MockRepositoriesService)(nil).GetPermissionLevel), ctx, owner, repo, user)
+}
+
 // ListBranches mocks base method.
 func (m *MockRepositoriesService) ListBranches(ctx context.Context, owner, repo string, opts *github.BranchListOptions) ([]*github.Branch, *github.Response, error) {
 	m.ctrl.T.Helper()
@@ -730,6 +842,22 @@ func (m *MockTeamsService) EXPECT() *MockTeamsServiceMockRecorder {
 	return m.recorder
 }
 
+// GetTeamMembershipBySlug mocks base method.
+func (m *MockTeamsService) GetTeamMembershipBySlug(ctx context.Context, org, slug, user string) (*github.Membership, *github.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTeamMembershipBySlug", ctx, org, slug, user)
+	ret0, _ := ret[0].(*github.Membership)
+	ret1, _ := ret[1].(*github.Response)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetTeamMembershipBySlug indicates an expected call of GetTeamMembershipBySlug.
+func (mr *MockTeamsServiceMockRecorder) GetTeamMembershipBySlug(ctx, org, slug, user interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTeamMembershipBySlug", reflect.TypeOf((*MockTeamsService)(nil).GetTeamMembershipBySlug), ctx, org, slug, user)
+}
+
 // ListTeamMembersBySlug mocks base method.
 func (m *MockTeamsService) ListTeamMembersBySlug(ctx context.Context, org, slug string, opts *github.TeamListTeamMembersOptions) ([]*github.User, *github.Response, error) {
 	m.ctrl.T.Helper()
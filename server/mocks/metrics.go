@@ -33,6 +33,30 @@ func (m *MockMetricsProvider) EXPECT() *MockMetricsProviderMockRecorder {
 	return m.recorder
 }
 
+// IncreaseAutomationErrors mocks base method.
+func (m *MockMetricsProvider) IncreaseAutomationErrors(name, hook string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "IncreaseAutomationErrors", name, hook)
+}
+
+// IncreaseAutomationErrors indicates an expected call of IncreaseAutomationErrors.
+func (mr *MockMetricsProviderMockRecorder) IncreaseAutomationErrors(name, hook interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IncreaseAutomationErrors", reflect.TypeOf((*MockMetricsProvider)(nil).IncreaseAutomationErrors), name, hook)
+}
+
+// IncreaseCommandDenials mocks base method.
+func (m *MockMetricsProvider) IncreaseCommandDenials(name string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "IncreaseCommandDenials", name)
+}
+
+// IncreaseCommandDenials indicates an expected call of IncreaseCommandDenials.
+func (mr *MockMetricsProviderMockRecorder) IncreaseCommandDenials(name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IncreaseCommandDenials", reflect.TypeOf((*MockMetricsProvider)(nil).IncreaseCommandDenials), name)
+}
+
 // IncreaseCronTaskErrors mocks base method.
 func (m *MockMetricsProvider) IncreaseCronTaskErrors(name string) {
 	m.ctrl.T.Helper()
@@ -105,6 +129,30 @@ func (mr *MockMetricsProviderMockRecorder) IncreaseWebhookRequest(name interface
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IncreaseWebhookRequest", reflect.TypeOf((*MockMetricsProvider)(nil).IncreaseWebhookRequest), name)
 }
 
+// IncreaseWebhookSignatureValidations mocks base method.
+func (m *MockMetricsProvider) IncreaseWebhookSignatureValidations(secretID, algorithm string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "IncreaseWebhookSignatureValidations", secretID, algorithm)
+}
+
+// IncreaseWebhookSignatureValidations indicates an expected call of IncreaseWebhookSignatureValidations.
+func (mr *MockMetricsProviderMockRecorder) IncreaseWebhookSignatureValidations(secretID, algorithm interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IncreaseWebhookSignatureValidations", reflect.TypeOf((*MockMetricsProvider)(nil).IncreaseWebhookSignatureValidations), secretID, algorithm)
+}
+
+// ObserveAutomationDuration mocks base method.
+func (m *MockMetricsProvider) ObserveAutomationDuration(name, hook string, elapsed float64) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "ObserveAutomationDuration", name, hook, elapsed)
+}
+
+// ObserveAutomationDuration indicates an expected call of ObserveAutomationDuration.
+func (mr *MockMetricsProviderMockRecorder) ObserveAutomationDuration(name, hook, elapsed interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ObserveAutomationDuration", reflect.TypeOf((*MockMetricsProvider)(nil).ObserveAutomationDuration), name, hook, elapsed)
+}
+
 // ObserveCronTaskDuration mocks base method.
 func (m *MockMetricsProvider) ObserveCronTaskDuration(name string, elapsed float64) {
 	m.ctrl.T.Helper()
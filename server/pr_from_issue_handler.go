@@ -4,29 +4,26 @@
 package server
 
 import (
-	"net/http"
-
-	"github.com/mattermost/mattermost-server/v6/shared/mlog"
+	"context"
+	"fmt"
 )
 
-func (s *Server) prFromIssueHandler(event *issueEvent, w http.ResponseWriter) {
+func (s *Server) prFromIssueHandler(_ context.Context, event *issueEvent) error {
 	// Happens if the PR is new _and_ has a milestone. So sometimes the milestone info
 	// is not up to date.
 	if event.Issue.GetMilestone() == nil {
-		return
+		return nil
 	}
 
 	oldPR, err := s.Store.PullRequest().Get(event.Repo.GetOwner().GetLogin(),
 		event.Repo.GetName(),
 		event.Issue.GetNumber())
 	if err != nil {
-		mlog.Error("Error in getting PR from DB", mlog.Err(err))
-		http.Error(w, err.Error(), http.StatusInternalServerError)
-		return
+		return fmt.Errorf("error in getting PR from DB: %w", err)
 	}
 	// PR does not exist in DB.
 	if oldPR == nil {
-		return
+		return nil
 	}
 
 	// We update the milestone that we have from the issue event and merge it with the PR.
@@ -36,8 +33,8 @@ func (s *Server) prFromIssueHandler(event *issueEvent, w http.ResponseWriter) {
 
 	_, err = s.Store.PullRequest().Save(oldPR)
 	if err != nil {
-		mlog.Error("Error in saving PR to DB", mlog.Err(err))
-		http.Error(w, err.Error(), http.StatusInternalServerError)
-		return
+		return fmt.Errorf("error in saving PR to DB: %w", err)
 	}
+
+	return nil
 }
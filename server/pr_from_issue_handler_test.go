@@ -4,10 +4,8 @@
 package server
 
 import (
-	"bytes"
+	"context"
 	"encoding/json"
-	"net/http"
-	"net/http/httptest"
 	"testing"
 
 	"github.com/golang/mock/gomock"
@@ -101,19 +99,11 @@ func TestPRFromIssueHandler(t *testing.T) {
 		Store: ss,
 	}
 
-	ts := httptest.NewServer(http.HandlerFunc(s.githubEvent))
-	defer ts.Close()
-
 	b, err := json.Marshal(&event)
 	require.NoError(t, err)
 
-	req, err := http.NewRequest("POST", ts.URL, bytes.NewReader(b))
-	req.Header.Set("X-GitHub-Event", "issues")
-	require.NoError(t, err)
-	resp, err := http.DefaultClient.Do(req)
+	err = s.handleEvent(context.Background(), "issues", b)
 	require.NoError(t, err)
-	defer resp.Body.Close()
-	require.Equal(t, http.StatusOK, resp.StatusCode)
 }
 
 func TestPRFromIssueHandlerNoMilestone(t *testing.T) {
@@ -160,17 +150,9 @@ func TestPRFromIssueHandlerNoMilestone(t *testing.T) {
 		Store: ss,
 	}
 
-	ts := httptest.NewServer(http.HandlerFunc(s.githubEvent))
-	defer ts.Close()
-
 	b, err := json.Marshal(&event)
 	require.NoError(t, err)
 
-	req, err := http.NewRequest("POST", ts.URL, bytes.NewReader(b))
-	req.Header.Set("X-GitHub-Event", "issues")
-	require.NoError(t, err)
-	resp, err := http.DefaultClient.Do(req)
+	err = s.handleEvent(context.Background(), "issues", b)
 	require.NoError(t, err)
-	defer resp.Body.Close()
-	require.Equal(t, http.StatusOK, resp.StatusCode)
 }
@@ -6,9 +6,9 @@ package server
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
-	"net/http"
 	"strings"
 	"sync"
 	"time"
@@ -28,116 +28,41 @@ type pullRequestEvent struct {
 	PRNumber      int                 `json:"number"`
 }
 
-func (s *Server) pullRequestEventHandler(w http.ResponseWriter, r *http.Request) {
-	ctx, cancel := context.WithTimeout(context.Background(), defaultRequestTimeout*time.Second)
-	defer cancel()
-
-	event, err := pullRequestEventFromJSON(r.Body)
-	if err != nil {
-		mlog.Error("could not parse pr event", mlog.Err(err))
-		http.Error(w, err.Error(), http.StatusBadRequest)
-		return
-	}
-
+func (s *Server) pullRequestEventHandler(ctx context.Context, event *pullRequestEvent) error {
 	pr, err := s.GetPullRequestFromGithub(ctx, event.PullRequest, event.Action)
 	if err != nil {
-		mlog.Error("Unable to get PR from GitHub", mlog.Int("pr", event.PRNumber), mlog.Err(err))
-		http.Error(w, err.Error(), http.StatusNotFound)
-		return
+		return fmt.Errorf("unable to get PR %d from GitHub: %w", event.PRNumber, err)
 	}
 
 	switch event.Action {
 	case prEventOpened:
 		mlog.Info("PR opened", mlog.String("repo", pr.RepoName), mlog.Int("pr", pr.Number))
-
-		var claCommentNeeded bool
-		claCommentNeeded, err = s.handleCheckCLA(ctx, pr)
-		if err != nil {
-			mlog.Error("Unable to check CLA", mlog.Err(err))
-		}
-
-		if err = s.postPRWelcomeMessage(ctx, pr, claCommentNeeded); err != nil {
-			mlog.Error("Error while commenting PR welcome message", mlog.Err(err))
-		}
-
-		s.addHacktoberfestLabel(ctx, pr)
-		s.handleTranslationPR(ctx, pr)
-
-		repo, repoExist := GetRepository(s.Config.Repositories, pr.RepoOwner, pr.RepoName)
-		if repoExist {
-			if err = s.assignGreeter(ctx, pr, repo); err != nil {
-				mlog.Error("Error while assigning a greeter to the community PR", mlog.Err(err))
-			}
-
-			if err = s.assignGreetingLabels(ctx, pr, repo); err != nil {
-				mlog.Error("Error while assigning labels to the community PR", mlog.Err(err))
-			}
-		}
-
-		if pr.RepoName == serverRepoName {
-			if err = s.reviewMlog(ctx, pr, event.PullRequest.GetNodeID(), event.PullRequest.GetDiffURL()); err != nil {
-				mlog.Error("Error while reviewing mlog", mlog.Err(err))
-			}
-		}
-
-		s.setBlockStatusForPR(ctx, pr)
 	case prEventReOpened:
 		mlog.Info("PR reopened", mlog.String("repo", pr.RepoName), mlog.Int("pr", pr.Number))
-
-		if _, err = s.handleCheckCLA(ctx, pr); err != nil {
-			mlog.Error("Unable to check CLA", mlog.Err(err))
-		}
-
-		s.handleTranslationPR(ctx, pr)
-		s.setBlockStatusForPR(ctx, pr)
 	case prEventLabeled:
 		if event.Label == nil {
-			mlog.Error("Label event received, but label object was empty")
-			return
-		}
-
-		if s.isBlockPRMerge(*event.Label.Name) {
-			if err = s.unblockPRMerge(ctx, pr); err != nil {
-				mlog.Error("Unable to create the github status for for PR", mlog.Int("pr", pr.Number), mlog.Err(err))
-			}
-		}
-		if event.Label.GetName() == s.Config.AutoPRMergeLabel {
-			msg := "Will try to auto merge this PR once all tests and checks are passing. This might take up to an hour."
-			if err = s.sendGitHubComment(ctx, pr.RepoOwner, pr.RepoName, pr.Number, msg); err != nil {
-				mlog.Warn("Error while commenting", mlog.Err(err))
-			}
+			return newPermanentError(errors.New("label event received, but label object was empty"))
 		}
 	case prEventUnLabeled:
 		if event.Label == nil {
-			mlog.Error("Unlabel event received, but label object was empty")
-			return
-		}
-
-		if s.isBlockPRMerge(*event.Label.Name) {
-			if err = s.unblockPRMerge(ctx, pr); err != nil {
-				mlog.Error("Unable to create the github status for for PR", mlog.Int("pr", pr.Number), mlog.Err(err))
-			}
+			return newPermanentError(errors.New("unlabel event received, but label object was empty"))
 		}
 	case prEventSynchronize:
 		mlog.Debug("PR has a new commit", mlog.String("repo", pr.RepoName), mlog.Int("pr", pr.Number))
-
-		if _, err = s.handleCheckCLA(ctx, pr); err != nil {
-			mlog.Error("Unable to check CLA", mlog.Err(err))
-		}
-
-		s.setBlockStatusForPR(ctx, pr)
 	case prEventClosed:
-		mlog.Info("PR was closed", mlog.String("repo", *event.Repo.Name), mlog.Int("pr", event.PRNumber))
-		go s.checkIfNeedCherryPick(pr)
-		go s.CleanUpLabels(pr)
+		mlog.Info("PR was closed", mlog.String("repo", pr.RepoName), mlog.Int("pr", pr.Number))
 	}
 
+	errs := s.runPullRequestAutomations(ctx, event, pr)
+
 	changed, err := s.checkPullRequestForChanges(ctx, pr)
 	if err != nil {
 		mlog.Error("Could not check changes for PR", mlog.Err(err))
 	} else if changed {
 		mlog.Info("pr has changes", mlog.Int("pr", pr.Number))
 	}
+
+	return automationsError(errs)
 }
 
 func pullRequestEventFromJSON(data io.Reader) (*pullRequestEvent, error) {
@@ -412,13 +337,29 @@ func (s *Server) CleanOutdatedPRs() {
 	mlog.Info("Finished update the outdated prs in the mattermod database....")
 }
 
-func (s *Server) CleanUpLabels(pr *model.PullRequest) {
+// labelCleanupAutomation removes the labels configured in
+// IssueLabelsToCleanUp from closed PRs.
+type labelCleanupAutomation struct {
+	baseAutomation
+	s *Server
+}
+
+func (a *labelCleanupAutomation) Name() string {
+	return "label-cleanup"
+}
+
+func (a *labelCleanupAutomation) OnPullRequest(ctx context.Context, event *pullRequestEvent, pr *model.PullRequest) error {
+	if event.Action == prEventClosed {
+		a.s.CleanUpLabels(ctx, pr)
+	}
+	return nil
+}
+
+func (s *Server) CleanUpLabels(ctx context.Context, pr *model.PullRequest) {
 	if len(s.Config.IssueLabelsToCleanUp) == 0 {
 		return
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), defaultRequestTimeout*time.Second)
-	defer cancel()
 	labels, _, err := s.GithubClient.Issues.ListLabelsByIssue(ctx, pr.RepoOwner, pr.RepoName, pr.Number, nil)
 	if err != nil {
 		mlog.Error("Error listing the labels for closed PR", mlog.Err(err))
@@ -479,13 +420,7 @@ func (s *Server) getPRFromIssueCommentEvent(ctx context.Context, event *issueCom
 }
 
 func (s *Server) setBlockStatusForPR(ctx context.Context, pr *model.PullRequest) {
-	var err error
-	if s.isBlockPRMergeInLabels(pr.Labels) {
-		err = s.blockPRMerge(ctx, pr)
-	} else {
-		err = s.unblockPRMerge(ctx, pr)
-	}
-	if err != nil {
+	if err := s.updateMergeBlockedStatus(ctx, pr); err != nil {
 		mlog.Error("Unable to create the github status for for PR", mlog.Int("pr", pr.Number), mlog.Err(err))
 	}
 }
@@ -1,12 +1,9 @@
 package server
 
 import (
-	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
-	"net/http"
-	"net/http/httptest"
 	"reflect"
 	"testing"
 	"time"
@@ -75,19 +72,16 @@ func TestPullRequestEventHandler(t *testing.T) {
 		},
 	}
 
-	ts := httptest.NewServer(http.HandlerFunc(s.pullRequestEventHandler))
-	defer ts.Close()
-
 	t.Run("Should fail with no body", func(t *testing.T) {
-		req, err := http.NewRequest("POST", ts.URL, nil)
-		require.NoError(t, err)
-		resp, err := http.DefaultClient.Do(req)
-		require.NoError(t, err)
-		defer resp.Body.Close()
-		require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		err := s.handleEvent(context.Background(), "pull_request", nil)
+		var perr *permanentError
+		require.ErrorAs(t, err, &perr)
 	})
 
 	t.Run("Should fail on not finding the PR from GitHub", func(t *testing.T) {
+		prStoreMock.EXPECT().Get("mattertest", "mattermod", 1).
+			Times(1).Return(nil, nil)
+
 		rs.EXPECT().
 			GetCombinedStatus(gomock.AssignableToTypeOf(ctxInterface), "mattertest", "mattermod", "sha", nil).
 			Times(1).
@@ -96,15 +90,14 @@ func TestPullRequestEventHandler(t *testing.T) {
 		b, err := json.Marshal(event)
 		require.NoError(t, err)
 
-		req, err := http.NewRequest("POST", ts.URL, bytes.NewReader(b))
-		require.NoError(t, err)
-		resp, err := http.DefaultClient.Do(req)
-		require.NoError(t, err)
-		defer resp.Body.Close()
-		require.Equal(t, http.StatusNotFound, resp.StatusCode)
+		err = s.handleEvent(context.Background(), "pull_request", b)
+		require.Error(t, err)
 	})
 
 	t.Run("Should be able to get PR from GitHub (new PR)", func(t *testing.T) {
+		prStoreMock.EXPECT().Get("mattertest", "mattermod", 1).
+			Times(1).Return(nil, nil)
+
 		rs.EXPECT().
 			GetCombinedStatus(gomock.AssignableToTypeOf(ctxInterface), "mattertest", "mattermod", "sha", nil).
 			Times(1).
@@ -138,15 +131,14 @@ func TestPullRequestEventHandler(t *testing.T) {
 		b, err := json.Marshal(event)
 		require.NoError(t, err)
 
-		req, err := http.NewRequest("POST", ts.URL, bytes.NewReader(b))
+		err = s.handleEvent(context.Background(), "pull_request", b)
 		require.NoError(t, err)
-		resp, err := http.DefaultClient.Do(req)
-		require.NoError(t, err)
-		defer resp.Body.Close()
-		require.Equal(t, http.StatusOK, resp.StatusCode)
 	})
 
 	t.Run("Error when checking PR for changes", func(t *testing.T) {
+		prStoreMock.EXPECT().Get("mattertest", "mattermod", 1).
+			Times(1).Return(nil, nil)
+
 		rs.EXPECT().
 			GetCombinedStatus(gomock.AssignableToTypeOf(ctxInterface), "mattertest", "mattermod", "sha", nil).
 			Times(1).
@@ -177,41 +169,18 @@ func TestPullRequestEventHandler(t *testing.T) {
 		b, err := json.Marshal(event)
 		require.NoError(t, err)
 
-		req, err := http.NewRequest("POST", ts.URL, bytes.NewReader(b))
-		require.NoError(t, err)
-		resp, err := http.DefaultClient.Do(req)
+		err = s.handleEvent(context.Background(), "pull_request", b)
 		require.NoError(t, err)
-		defer resp.Body.Close()
-		require.Equal(t, http.StatusOK, resp.StatusCode)
 	})
 
 	t.Run("PR has changes", func(t *testing.T) {
-		rs.EXPECT().
-			GetCombinedStatus(gomock.AssignableToTypeOf(ctxInterface), "mattertest", "mattermod", "sha", nil).
-			Times(1).
-			Return(&github.CombinedStatus{
-				Statuses: []*github.RepoStatus{
-					{
-						Context: github.String("something"),
-					},
-				},
-			}, nil, nil)
-
-		cs.EXPECT().
-			ListCheckRunsForRef(gomock.AssignableToTypeOf(ctxInterface), "mattertest", "mattermod", "sha", nil).
-			Times(1).
-			Return(&github.ListCheckRunsResults{}, nil, nil)
-
 		is.EXPECT().
 			ListLabelsByIssue(gomock.AssignableToTypeOf(ctxInterface), "mattertest", "mattermod", 1, nil).
 			Times(1).
 			Return([]*github.Label{}, nil, nil)
 
-		prStoreMock.EXPECT().Save(gomock.AssignableToTypeOf(&model.PullRequest{})).
-			Times(1).Return(nil, nil)
-
 		prStoreMock.EXPECT().Get("mattertest", "mattermod", 1).
-			Times(1).Return(&model.PullRequest{
+			Times(2).Return(&model.PullRequest{
 			RepoOwner:           "mattertest",
 			RepoName:            "mattermod",
 			CreatedAt:           time.Time{},
@@ -222,44 +191,24 @@ func TestPullRequestEventHandler(t *testing.T) {
 		}, nil)
 
 		prStoreMock.EXPECT().Save(gomock.AssignableToTypeOf(&model.PullRequest{})).
-			Times(1).Return(nil, nil)
+			Times(2).Return(nil, nil)
 
 		b, err := json.Marshal(event)
 		require.NoError(t, err)
 
-		req, err := http.NewRequest("POST", ts.URL, bytes.NewReader(b))
+		err = s.handleEvent(context.Background(), "pull_request", b)
 		require.NoError(t, err)
-		resp, err := http.DefaultClient.Do(req)
-		require.NoError(t, err)
-		defer resp.Body.Close()
-		require.Equal(t, http.StatusOK, resp.StatusCode)
 	})
 
 	testPRHasChanges := func(t *testing.T, modelPR *model.PullRequest, githubPR *github.PullRequest, expectedSaveCalls int) {
 		t.Helper()
-		rs.EXPECT().
-			GetCombinedStatus(gomock.AssignableToTypeOf(ctxInterface), "mattertest", "mattermod", "sha", nil).
-			Times(1).
-			Return(&github.CombinedStatus{
-				Statuses: []*github.RepoStatus{
-					{
-						Context: github.String("something"),
-					},
-				},
-			}, nil, nil)
-
-		cs.EXPECT().
-			ListCheckRunsForRef(gomock.AssignableToTypeOf(ctxInterface), "mattertest", "mattermod", "sha", nil).
-			Times(1).
-			Return(&github.ListCheckRunsResults{}, nil, nil)
-
 		is.EXPECT().
 			ListLabelsByIssue(gomock.AssignableToTypeOf(ctxInterface), "mattertest", "mattermod", 1, nil).
 			Times(1).
 			Return([]*github.Label{{Name: NewString("old-label")}}, nil, nil)
 
 		prStoreMock.EXPECT().Get("mattertest", "mattermod", 1).
-			Times(1).Return(modelPR, nil)
+			Times(2).Return(modelPR, nil)
 
 		prStoreMock.EXPECT().Save(gomock.AssignableToTypeOf(&model.PullRequest{})).
 			Times(expectedSaveCalls).Return(nil, nil)
@@ -272,12 +221,8 @@ func TestPullRequestEventHandler(t *testing.T) {
 		b, err := json.Marshal(e)
 		require.NoError(t, err)
 
-		req, err := http.NewRequest("POST", ts.URL, bytes.NewReader(b))
-		require.NoError(t, err)
-		resp, err := http.DefaultClient.Do(req)
+		err = s.handleEvent(context.Background(), "pull_request", b)
 		require.NoError(t, err)
-		defer resp.Body.Close()
-		require.Equal(t, http.StatusOK, resp.StatusCode)
 	}
 
 	modelPR := &model.PullRequest{
@@ -489,7 +434,7 @@ func TestCleanUpLabels(t *testing.T) {
 				},
 				GithubClient: test.SetupClient(ctrl),
 			}
-			s.CleanUpLabels(pr)
+			s.CleanUpLabels(context.Background(), pr)
 		})
 	}
 }
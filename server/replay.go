@@ -0,0 +1,209 @@
+// Copyright (c) 2017-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package server
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/mattermost/mattermost-mattermod/model"
+	"github.com/mattermost/mattermost-mattermod/store"
+	"github.com/mattermost/mattermost-server/v6/shared/mlog"
+)
+
+const replayDeliveryPrefix = "replay-"
+
+var (
+	// errInvalidReplay is returned for replay requests which can't be
+	// processed as they are.
+	errInvalidReplay = errors.New("invalid replay request")
+	// errDeliveryNotFound is returned when the delivery to replay isn't stored.
+	errDeliveryNotFound = errors.New("delivery not found")
+	// errDeliveryRunning is returned when the delivery to replay is being
+	// processed. It can be replayed once it is done.
+	errDeliveryRunning = errors.New("delivery is being processed")
+)
+
+// ReplayRequest asks for a webhook delivery to be processed again. Either
+// DeliveryID of a stored delivery, or EventType and Payload must be set.
+type ReplayRequest struct {
+	DeliveryID string          `json:"delivery_id"`
+	EventType  string          `json:"event_type"`
+	Payload    json.RawMessage `json:"payload,omitempty"`
+	DryRun     bool            `json:"dry_run"`
+}
+
+func (r *ReplayRequest) hasPayload() bool {
+	return len(r.Payload) > 0 && !bytes.Equal(r.Payload, []byte("null"))
+}
+
+// ReplayResult describes a replayed delivery and the handler it is routed to.
+type ReplayResult struct {
+	DeliveryID string `json:"delivery_id"`
+	EventType  string `json:"event_type"`
+	Route      string `json:"route"`
+	Summary    string `json:"summary"`
+	DryRun     bool   `json:"dry_run"`
+}
+
+// ReplayDelivery queues a delivery to be processed again by the webhook
+// workers. A stored delivery is reset to pending, while a raw payload is
+// stored as a new delivery. With DryRun set, the payload is only parsed and
+// routed.
+func ReplayDelivery(st store.Store, req *ReplayRequest) (*ReplayResult, error) {
+	if req.DeliveryID != "" && req.hasPayload() {
+		return nil, fmt.Errorf("%w: either a delivery id or a payload must be given, not both", errInvalidReplay)
+	}
+
+	if req.DeliveryID != "" {
+		return replayStoredDelivery(st, req)
+	}
+
+	if req.EventType == "" || !req.hasPayload() {
+		return nil, fmt.Errorf("%w: an event type and a payload are required when no delivery id is given", errInvalidReplay)
+	}
+
+	event, err := routeEvent(req.EventType, req.Payload)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", errInvalidReplay, err)
+	}
+
+	result := &ReplayResult{
+		EventType: req.EventType,
+		Route:     event.route,
+		Summary:   event.summary,
+		DryRun:    req.DryRun,
+	}
+	if req.DryRun {
+		return result, nil
+	}
+
+	id, err := newReplayDeliveryID()
+	if err != nil {
+		return nil, err
+	}
+	if _, err = st.WebhookDelivery().Create(&model.WebhookDelivery{
+		DeliveryID: id,
+		EventType:  req.EventType,
+		Payload:    req.Payload,
+		State:      model.DeliveryStatePending,
+	}); err != nil {
+		return nil, err
+	}
+	result.DeliveryID = id
+
+	return result, nil
+}
+
+func replayStoredDelivery(st store.Store, req *ReplayRequest) (*ReplayResult, error) {
+	delivery, err := st.WebhookDelivery().Get(req.DeliveryID)
+	if err != nil {
+		return nil, err
+	}
+	if delivery == nil {
+		return nil, fmt.Errorf("%w: %s", errDeliveryNotFound, req.DeliveryID)
+	}
+
+	event, err := routeEvent(delivery.EventType, delivery.Payload)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ReplayResult{
+		DeliveryID: delivery.DeliveryID,
+		EventType:  delivery.EventType,
+		Route:      event.route,
+		Summary:    event.summary,
+		DryRun:     req.DryRun,
+	}
+	if req.DryRun {
+		return result, nil
+	}
+
+	// The delivery is only reset if it isn't running, a worker may have
+	// claimed it since it was read.
+	reset, err := st.WebhookDelivery().Reset(delivery.DeliveryID)
+	if err != nil {
+		return nil, err
+	}
+	if !reset {
+		return nil, fmt.Errorf("%w: %s", errDeliveryRunning, delivery.DeliveryID)
+	}
+
+	return result, nil
+}
+
+func newReplayDeliveryID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("could not generate delivery id: %w", err)
+	}
+	return replayDeliveryPrefix + hex.EncodeToString(b), nil
+}
+
+func (s *Server) replayEvent(w http.ResponseWriter, r *http.Request) {
+	var req ReplayRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "could not parse replay request", http.StatusBadRequest)
+		return
+	}
+
+	result, err := ReplayDelivery(s.Store, &req)
+	if err != nil {
+		mlog.Warn("Failed to replay webhook delivery", mlog.String("delivery", req.DeliveryID), mlog.Err(err))
+		http.Error(w, err.Error(), replayErrorStatus(err))
+		return
+	}
+
+	if !result.DryRun {
+		mlog.Info("Replaying webhook delivery", mlog.String("delivery", result.DeliveryID), mlog.String("route", result.Route))
+		s.enqueueWebhookDelivery(result.DeliveryID)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err = json.NewEncoder(w).Encode(result); err != nil {
+		mlog.Error("Failed to write replay result", mlog.Err(err))
+	}
+}
+
+// replayErrorStatus returns the HTTP status of a replay error. Errors of the
+// store, or routing a stored delivery, are internal errors.
+func replayErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, errInvalidReplay):
+		return http.StatusBadRequest
+	case errors.Is(err, errDeliveryNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, errDeliveryRunning):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// withAdminAuth only lets requests through which carry the configured
+// admin token. Admin endpoints are disabled if no token is configured.
+func (s *Server) withAdminAuth(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if s.Config.AdminToken == "" {
+			http.Error(w, "admin endpoints are disabled", http.StatusNotFound)
+			return
+		}
+
+		token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if subtle.ConstantTimeCompare([]byte(token), []byte(s.Config.AdminToken)) != 1 {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		next(w, r)
+	}
+}
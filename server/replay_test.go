@@ -0,0 +1,231 @@
+// Copyright (c) 2017-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-mattermod/model"
+	stmock "github.com/mattermost/mattermost-mattermod/store/mocks"
+)
+
+const replayTestPayload = `{"action": "opened", "number": 1, "pull_request": {}, "repository": {"full_name": "mattertest/mattermod"}}`
+
+func TestReplayDelivery(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	deliveryStoreMock := stmock.NewMockWebhookDeliveryStore(ctrl)
+	ss := stmock.NewMockStore(ctrl)
+	ss.EXPECT().
+		WebhookDelivery().
+		Return(deliveryStoreMock).
+		AnyTimes()
+
+	stored := func(state string) *model.WebhookDelivery {
+		return &model.WebhookDelivery{
+			DeliveryID: "id",
+			EventType:  "pull_request",
+			Payload:    []byte(replayTestPayload),
+			State:      state,
+			LastError:  "some-error",
+			Attempts:   5,
+		}
+	}
+
+	t.Run("Missing input", func(t *testing.T) {
+		_, err := ReplayDelivery(ss, &ReplayRequest{})
+		require.ErrorIs(t, err, errInvalidReplay)
+
+		_, err = ReplayDelivery(ss, &ReplayRequest{EventType: "pull_request"})
+		require.ErrorIs(t, err, errInvalidReplay)
+	})
+
+	t.Run("Stored delivery not found", func(t *testing.T) {
+		deliveryStoreMock.EXPECT().Get("id").Return(nil, nil)
+
+		_, err := ReplayDelivery(ss, &ReplayRequest{DeliveryID: "id"})
+		require.ErrorIs(t, err, errDeliveryNotFound)
+	})
+
+	t.Run("Stored delivery dry run", func(t *testing.T) {
+		deliveryStoreMock.EXPECT().Get("id").Return(stored(model.DeliveryStateFailed), nil)
+
+		result, err := ReplayDelivery(ss, &ReplayRequest{DeliveryID: "id", DryRun: true})
+		require.NoError(t, err)
+		assert.Equal(t, "pull_request", result.Route)
+		assert.Equal(t, "opened mattertest/mattermod#1", result.Summary)
+		assert.True(t, result.DryRun)
+	})
+
+	t.Run("Stored delivery is reset to pending", func(t *testing.T) {
+		deliveryStoreMock.EXPECT().Get("id").Return(stored(model.DeliveryStateFailed), nil)
+		deliveryStoreMock.EXPECT().Reset("id").Return(true, nil)
+
+		result, err := ReplayDelivery(ss, &ReplayRequest{DeliveryID: "id"})
+		require.NoError(t, err)
+		assert.Equal(t, "id", result.DeliveryID)
+	})
+
+	t.Run("Running delivery is not replayed", func(t *testing.T) {
+		deliveryStoreMock.EXPECT().Get("id").Return(stored(model.DeliveryStateRunning), nil)
+		deliveryStoreMock.EXPECT().Reset("id").Return(false, nil)
+
+		_, err := ReplayDelivery(ss, &ReplayRequest{DeliveryID: "id"})
+		require.ErrorIs(t, err, errDeliveryRunning)
+	})
+
+	t.Run("Payload with unknown event type", func(t *testing.T) {
+		_, err := ReplayDelivery(ss, &ReplayRequest{EventType: "deployment", Payload: []byte(`{}`)})
+		require.ErrorIs(t, err, errInvalidReplay)
+	})
+
+	t.Run("Payload dry run", func(t *testing.T) {
+		result, err := ReplayDelivery(ss, &ReplayRequest{EventType: "pull_request", Payload: []byte(replayTestPayload), DryRun: true})
+		require.NoError(t, err)
+		assert.Equal(t, "pull_request", result.Route)
+		assert.Empty(t, result.DeliveryID)
+	})
+
+	t.Run("Payload is stored as a new delivery", func(t *testing.T) {
+		deliveryStoreMock.EXPECT().
+			Create(gomock.Any()).
+			DoAndReturn(func(delivery *model.WebhookDelivery) (bool, error) {
+				assert.True(t, strings.HasPrefix(delivery.DeliveryID, replayDeliveryPrefix))
+				assert.Equal(t, "pull_request", delivery.EventType)
+				return true, nil
+			})
+
+		result, err := ReplayDelivery(ss, &ReplayRequest{EventType: "pull_request", Payload: []byte(replayTestPayload)})
+		require.NoError(t, err)
+		assert.True(t, strings.HasPrefix(result.DeliveryID, replayDeliveryPrefix))
+	})
+
+	t.Run("Store error", func(t *testing.T) {
+		deliveryStoreMock.EXPECT().Get("id").Return(nil, errors.New("some-error"))
+
+		_, err := ReplayDelivery(ss, &ReplayRequest{DeliveryID: "id"})
+		require.Error(t, err)
+	})
+}
+
+func TestReplayEndpoint(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	deliveryStoreMock := stmock.NewMockWebhookDeliveryStore(ctrl)
+	ss := stmock.NewMockStore(ctrl)
+	ss.EXPECT().
+		WebhookDelivery().
+		Return(deliveryStoreMock).
+		AnyTimes()
+
+	s := &Server{
+		Config:            &Config{AdminToken: "secret"},
+		Store:             ss,
+		webhookDeliveries: make(chan string, 1),
+	}
+
+	ts := httptest.NewServer(s.withAdminAuth(s.replayEvent))
+	defer ts.Close()
+
+	post := func(t *testing.T, token string, req *ReplayRequest) *http.Response {
+		b, err := json.Marshal(req)
+		require.NoError(t, err)
+		r, err := http.NewRequest("POST", ts.URL, bytes.NewReader(b))
+		require.NoError(t, err)
+		if token != "" {
+			r.Header.Set("Authorization", "Bearer "+token)
+		}
+		resp, err := http.DefaultClient.Do(r)
+		require.NoError(t, err)
+		return resp
+	}
+
+	t.Run("Missing token", func(t *testing.T) {
+		resp := post(t, "", &ReplayRequest{DeliveryID: "id"})
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	})
+
+	t.Run("Wrong token", func(t *testing.T) {
+		resp := post(t, "wrong", &ReplayRequest{DeliveryID: "id"})
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	})
+
+	t.Run("Invalid request", func(t *testing.T) {
+		resp := post(t, "secret", &ReplayRequest{})
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("Unknown delivery", func(t *testing.T) {
+		deliveryStoreMock.EXPECT().Get("id").Return(nil, nil)
+
+		resp := post(t, "secret", &ReplayRequest{DeliveryID: "id"})
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+
+	t.Run("Running delivery", func(t *testing.T) {
+		deliveryStoreMock.EXPECT().Get("id").Return(&model.WebhookDelivery{
+			DeliveryID: "id",
+			EventType:  "pull_request",
+			Payload:    []byte(replayTestPayload),
+			State:      model.DeliveryStateRunning,
+		}, nil)
+		deliveryStoreMock.EXPECT().Reset("id").Return(false, nil)
+
+		resp := post(t, "secret", &ReplayRequest{DeliveryID: "id"})
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusConflict, resp.StatusCode)
+	})
+
+	t.Run("Store error", func(t *testing.T) {
+		deliveryStoreMock.EXPECT().Get("id").Return(nil, errors.New("some-error"))
+
+		resp := post(t, "secret", &ReplayRequest{DeliveryID: "id"})
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+	})
+
+	t.Run("Replay is queued", func(t *testing.T) {
+		deliveryStoreMock.EXPECT().Get("id").Return(&model.WebhookDelivery{
+			DeliveryID: "id",
+			EventType:  "pull_request",
+			Payload:    []byte(replayTestPayload),
+			State:      model.DeliveryStateFailed,
+		}, nil)
+		deliveryStoreMock.EXPECT().Reset("id").Return(true, nil)
+
+		resp := post(t, "secret", &ReplayRequest{DeliveryID: "id"})
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var result ReplayResult
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+		assert.Equal(t, "id", result.DeliveryID)
+		assert.Equal(t, "id", <-s.webhookDeliveries)
+	})
+
+	t.Run("Disabled without a token", func(t *testing.T) {
+		s.Config.AdminToken = ""
+		defer func() { s.Config.AdminToken = "secret" }()
+
+		resp := post(t, "", &ReplayRequest{DeliveryID: "id"})
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+}
@@ -19,6 +19,24 @@ import (
 
 const mlogReviewCommentBody = "Gentle reminder to check our logging [principles](https://developers.mattermost.com/contribute/server/style-guide/#log-levels) before merging this change."
 
+// mlogReviewAutomation reminds community members of the logging guidelines
+// when their server PR adds error logs.
+type mlogReviewAutomation struct {
+	baseAutomation
+	s *Server
+}
+
+func (a *mlogReviewAutomation) Name() string {
+	return "mlog-review"
+}
+
+func (a *mlogReviewAutomation) OnPullRequest(ctx context.Context, event *pullRequestEvent, pr *model.PullRequest) error {
+	if event.Action != prEventOpened || pr.RepoName != serverRepoName {
+		return nil
+	}
+	return a.s.reviewMlog(ctx, pr, event.PullRequest.GetNodeID(), event.PullRequest.GetDiffURL())
+}
+
 func (s *Server) reviewMlog(ctx context.Context, pr *model.PullRequest, nodeID, diffURL string) error {
 	// Do not review this for organization members. This was in use for a while
 	// and we can assume that people in the organization should be aware of the gudieline.
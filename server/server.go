@@ -4,7 +4,6 @@
 package server
 
 import (
-	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
@@ -35,13 +34,23 @@ type Server struct {
 	Config                *Config
 	Store                 store.Store
 	GithubClient          *GithubClient
+	githubApp             *GitHubApp
 	OrgMembers            []string
 	commentLock           sync.Mutex
 	StartTime             time.Time
 	Metrics               MetricsProvider
-	cherryPickRequests    chan *cherryPickRequest
+	cherryPickWakeChan    chan struct{}
 	cherryPickStopChan    chan struct{}
-	cherryPickStoppedChan chan struct{}
+	cherryPickWorkersWG   sync.WaitGroup
+	stickyComments        map[string]*stickyComment
+	stickyCommentsLock    sync.Mutex
+	cherryPickMirrors     map[string]*sync.Mutex
+	cherryPickMirrorsLock sync.Mutex
+	mergeQueueLock        sync.Mutex
+	webhookDeliveries     chan string
+	webhookStopChan       chan struct{}
+	webhookWorkersWG      sync.WaitGroup
+	automations           []Automation
 
 	server *http.Server
 }
@@ -59,15 +68,18 @@ const (
 
 func New(config *Config, metrics MetricsProvider) (*Server, error) {
 	s := &Server{
-		Config:                config,
-		StartTime:             time.Now(),
-		Metrics:               metrics,
-		cherryPickRequests:    make(chan *cherryPickRequest, 20),
-		cherryPickStopChan:    make(chan struct{}),
-		cherryPickStoppedChan: make(chan struct{}),
+		Config:             config,
+		StartTime:          time.Now(),
+		Metrics:            metrics,
+		cherryPickStopChan: make(chan struct{}),
+		stickyComments:     map[string]*stickyComment{},
+		webhookDeliveries:  make(chan string, 100),
+		webhookStopChan:    make(chan struct{}),
 	}
+	s.cherryPickWakeChan = make(chan struct{}, s.cherryPickWorkers())
+	s.registerDefaultAutomations()
 
-	ghClient, err := NewGithubClient(s.Config.GithubAccessToken, s.Config.GitHubTokenReserve, s.Metrics)
+	ghClient, err := s.newGithubClient()
 	if err != nil {
 		return nil, err
 	}
@@ -82,6 +94,8 @@ func New(config *Config, metrics MetricsProvider) (*Server, error) {
 
 	r.HandleFunc("/healthz", s.ping).Methods(http.MethodGet)
 	r.HandleFunc("/pr_event", s.githubEvent).Methods(http.MethodPost)
+	r.HandleFunc("/admin/replay", s.withAdminAuth(s.replayEvent)).Methods(http.MethodPost)
+	r.HandleFunc("/admin/backports", s.withAdminAuth(s.listBackports)).Methods(http.MethodGet)
 	r.Use(s.withRecovery)
 	r.Use(s.withRequestDuration)
 	r.Use(s.withValidation)
@@ -98,6 +112,41 @@ func New(config *Config, metrics MetricsProvider) (*Server, error) {
 	return s, nil
 }
 
+// newGithubClient authenticates as the configured GitHub App, falling back to
+// the personal access token if there is none.
+func (s *Server) newGithubClient() (*GithubClient, error) {
+	if s.Config.GitHubApp == nil {
+		return NewGithubClient(s.Config.GithubAccessToken, s.Config.GitHubTokenReserve, s.Metrics)
+	}
+
+	app, err := NewGitHubApp(s.Config.GitHubApp, s.Config.Org, http.DefaultTransport)
+	if err != nil {
+		return nil, err
+	}
+	s.githubApp = app
+
+	if s.Config.Username == "" {
+		ctx, cancel := context.WithTimeout(context.Background(), defaultRequestTimeout*time.Second)
+		defer cancel()
+		slug, errSlug := app.Slug(ctx)
+		if errSlug != nil {
+			return nil, errSlug
+		}
+		s.Config.Username = slug + "[bot]"
+	}
+
+	return NewGithubAppClient(app, s.Config.GitHubTokenReserve, s.Metrics)
+}
+
+// githubToken returns a token for git and API operations on repositories of
+// the given owner outside of GithubClient.
+func (s *Server) githubToken(ctx context.Context, owner string) (string, error) {
+	if s.githubApp == nil {
+		return s.Config.GithubAccessTokenCherryPick, nil
+	}
+	return s.githubApp.Token(ctx, owner)
+}
+
 // Start starts a server
 func (s *Server) Start() {
 	s.RefreshMembers()
@@ -111,12 +160,14 @@ func (s *Server) Start() {
 		os.Exit(1)
 	}()
 
-	go s.listenCherryPickRequests()
+	s.startCherryPickWorkers()
+	s.startWebhookWorkers()
 }
 
 // Stop stops a server
 func (s *Server) Stop() error {
-	s.finishCherryPickRequests()
+	s.stopWebhookWorkers()
+	s.stopCherryPickWorkers()
 
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
@@ -188,52 +239,21 @@ func (s *Server) Tick() {
 		s.Metrics.ObserveCronTaskDuration("tick", elapsed)
 	}()
 
+	if err := s.refreshBuildStatuses(ctx); err != nil {
+		mlog.Error("Failed to refresh build statuses", mlog.Err(err))
+		s.Metrics.IncreaseCronTaskErrors("tick")
+	}
+
 	for _, repository := range s.Config.Repositories {
-		prListOpts := &github.PullRequestListOptions{
+		issueListOpts := &github.IssueListByRepoOptions{
 			State:       "open",
 			ListOptions: github.ListOptions{PerPage: 50},
 		}
+
 		// We sleep in between requests to remain within rate limits.
 		// While we do have a rate limiter in the HTTP transport itself, that's a general limit for the entire application.
-		// In this scenario, just during listing issues and PRs,
+		// In this scenario, just during listing issues,
 		// we need to throttle the rate a bit more.
-		for {
-			ghPullRequests, resp, err := s.GithubClient.PullRequests.List(ctx, repository.Owner, repository.Name, prListOpts)
-			if err != nil {
-				mlog.Error("Failed to get PRs", mlog.Err(err), mlog.String("repo_owner", repository.Owner), mlog.String("repo_name", repository.Name))
-				s.Metrics.IncreaseCronTaskErrors("tick")
-				continue
-			}
-			if resp.NextPage == 0 {
-				break
-			}
-			prListOpts.Page = resp.NextPage
-			time.Sleep(200 * time.Millisecond)
-
-			for _, ghPullRequest := range ghPullRequests {
-				pullRequest, errPR := s.GetPullRequestFromGithub(ctx, ghPullRequest, "")
-				if errPR != nil {
-					mlog.Error("failed to convert PR", mlog.Int("pr", *ghPullRequest.Number), mlog.Err(errPR))
-					s.Metrics.IncreaseCronTaskErrors("tick")
-					continue
-				}
-
-				changed, err2 := s.checkPullRequestForChanges(ctx, pullRequest)
-				if err2 != nil {
-					mlog.Error("Could not check changes for PR", mlog.Err(err2))
-				} else if changed {
-					mlog.Info("pr has changes", mlog.Int("pr", pullRequest.Number))
-				}
-			}
-		}
-
-		time.Sleep(time.Second)
-
-		issueListOpts := &github.IssueListByRepoOptions{
-			State:       "open",
-			ListOptions: github.ListOptions{PerPage: 50},
-		}
-
 		for {
 			issues, resp, err := s.GithubClient.Issues.ListByRepo(ctx, repository.Owner, repository.Name, issueListOpts)
 			if err != nil {
@@ -250,7 +270,7 @@ func (s *Server) Tick() {
 
 			for _, ghIssue := range issues {
 				if ghIssue.PullRequestLinks != nil {
-					// This is a PR so we've already checked it
+					// PRs are kept up to date by their events
 					continue
 				}
 
@@ -267,6 +287,8 @@ func (s *Server) Tick() {
 			}
 		}
 	}
+
+	s.runScheduledAutomations(ctx)
 }
 
 func (s *Server) ping(w http.ResponseWriter, r *http.Request) {
@@ -280,49 +302,55 @@ func (s *Server) ping(w http.ResponseWriter, r *http.Request) {
 }
 
 func (s *Server) githubEvent(w http.ResponseWriter, r *http.Request) {
-	switch event := r.Header.Get("X-GitHub-Event"); event {
-	case "ping":
+	eventType := r.Header.Get("X-GitHub-Event")
+	if eventType == "ping" {
 		pingEvent := PingEventFromJSON(r.Body)
 		if pingEvent == nil {
 			http.Error(w, "could not parse ping event", http.StatusBadRequest)
 			return
 		}
 		mlog.Info("ping event", mlog.Int64("HookID", pingEvent.GetHookID()))
-	case "issues":
-		buf, err := io.ReadAll(r.Body)
-		if err != nil {
-			mlog.Error("Failed to read body", mlog.Err(err))
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
-		}
-		err = r.Body.Close()
-		if err != nil {
-			mlog.Error("Error closing body", mlog.Err(err))
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
-		}
+		return
+	}
 
-		event, err := issueEventFromJSON(bytes.NewReader(buf))
-		if err != nil {
-			mlog.Error("Could not parse issue event", mlog.Err(err))
-			http.Error(w, err.Error(), http.StatusBadRequest)
-			return
-		}
-		r.Body = io.NopCloser(bytes.NewBuffer(buf))
-		// An issue can be both an issue or a PR. So we need to differentiate between the two.
-		if event.Issue.IsPullRequest() {
-			mlog.Info("A PR event is found from an issue. Updating DB.", mlog.String("link", event.Issue.GetPullRequestLinks().GetHTMLURL()))
-			s.prFromIssueHandler(event, w)
-		} else {
-			s.issueEventHandler(w, r)
-		}
-	case "issue_comment":
-		s.issueCommentEventHandler(w, r)
-	case "pull_request":
-		s.pullRequestEventHandler(w, r)
-	default:
+	if !isQueuedEvent(eventType) {
 		http.Error(w, "unhandled event type", http.StatusNotImplemented)
+		return
+	}
+
+	deliveryID := r.Header.Get("X-GitHub-Delivery")
+	if deliveryID == "" {
+		http.Error(w, "missing delivery id", http.StatusBadRequest)
+		return
+	}
+
+	buf, err := io.ReadAll(r.Body)
+	if err != nil {
+		mlog.Error("Failed to read body", mlog.Err(err))
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if !json.Valid(buf) {
+		http.Error(w, "could not parse event payload", http.StatusBadRequest)
+		return
+	}
+
+	if err = s.queueWebhookDelivery(deliveryID, eventType, buf); err != nil {
+		mlog.Error("Failed to queue webhook delivery", mlog.String("delivery", deliveryID), mlog.Err(err))
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// handleEvent routes a webhook payload to the handler for its event type.
+func (s *Server) handleEvent(ctx context.Context, eventType string, payload []byte) error {
+	event, err := routeEvent(eventType, payload)
+	if err != nil {
+		return newPermanentError(err)
 	}
+	return event.handle(ctx, s)
 }
 
 func messageByUserContains(comments []*github.IssueComment, username string, text string) bool {
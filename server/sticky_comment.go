@@ -0,0 +1,125 @@
+// Copyright (c) 2017-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/google/go-github/v39/github"
+	"github.com/mattermost/mattermost-mattermod/model"
+)
+
+// stickyCommentCacheSize bounds the number of sticky comments whose ID is
+// remembered. Forgotten comments are found again by their marker.
+const stickyCommentCacheSize = 1000
+
+// stickyComment is the state of a sticky comment of a PR.
+type stickyComment struct {
+	// id is the ID of the comment, or 0 if it isn't known.
+	id int64
+	// publishing is set while the comment is being published.
+	publishing bool
+	// next renders the body published once the current publishing is done.
+	next stickyCommentRenderer
+}
+
+// stickyCommentRenderer returns the body of a sticky comment, and whether the
+// comment is created if the PR doesn't have it yet.
+type stickyCommentRenderer func() (body string, create bool, err error)
+
+// publishStickyComment keeps a single comment of mattermod on the PR up to
+// date. The comment is found by the marker its body starts with, and its ID
+// is cached afterwards. Publishing the same comment concurrently is
+// coalesced: the publishing in progress renders and publishes the comment
+// again once it is done, so that the last update wins and the comment is
+// created only once.
+func (s *Server) publishStickyComment(ctx context.Context, pr *model.PullRequest, marker string, render stickyCommentRenderer) error {
+	key := fmt.Sprintf("%s/%s#%d %s", pr.RepoOwner, pr.RepoName, pr.Number, marker)
+
+	s.stickyCommentsLock.Lock()
+	comment := s.stickyComments[key]
+	if comment == nil {
+		s.evictStickyComments()
+		comment = &stickyComment{}
+		s.stickyComments[key] = comment
+	}
+	if comment.publishing {
+		comment.next = render
+		s.stickyCommentsLock.Unlock()
+		return nil
+	}
+	comment.publishing = true
+	commentID := comment.id
+	s.stickyCommentsLock.Unlock()
+
+	var errs []error
+	for render != nil {
+		body, create, err := render()
+		if err == nil {
+			commentID, err = s.writeStickyComment(ctx, pr, marker, commentID, body, create)
+		}
+		errs = append(errs, err)
+
+		s.stickyCommentsLock.Lock()
+		comment.id = commentID
+		render, comment.next = comment.next, nil
+		comment.publishing = render != nil
+		s.stickyCommentsLock.Unlock()
+	}
+	return joinErrors(errs...)
+}
+
+// evictStickyComments forgets comments which aren't being published once the
+// cache is full. The caller must hold stickyCommentsLock.
+func (s *Server) evictStickyComments() {
+	if s.stickyComments == nil {
+		s.stickyComments = map[string]*stickyComment{}
+	}
+	for key, comment := range s.stickyComments {
+		if len(s.stickyComments) < stickyCommentCacheSize {
+			return
+		}
+		if !comment.publishing {
+			delete(s.stickyComments, key)
+		}
+	}
+}
+
+// writeStickyComment edits the comment with the ID, or else the comment of
+// mattermod starting with the marker. A comment which was deleted is looked
+// up again. If the PR doesn't have the comment, it is only created when
+// create is set. Returns the ID of the comment, or 0 if there is none.
+func (s *Server) writeStickyComment(ctx context.Context, pr *model.PullRequest, marker string, commentID int64, body string, create bool) (int64, error) {
+	if commentID != 0 {
+		_, _, err := s.GithubClient.Issues.EditComment(ctx, pr.RepoOwner, pr.RepoName, commentID, &github.IssueComment{Body: &body})
+		var respErr *github.ErrorResponse
+		if !errors.As(err, &respErr) || respErr.Response == nil || respErr.Response.StatusCode != http.StatusNotFound {
+			return commentID, err
+		}
+	}
+
+	comments, err := s.getComments(ctx, pr.RepoOwner, pr.RepoName, pr.Number)
+	if err != nil {
+		return 0, err
+	}
+	for _, comment := range comments {
+		if comment.GetID() != commentID && comment.GetUser().GetLogin() == s.Config.Username && strings.HasPrefix(comment.GetBody(), marker) {
+			_, _, err = s.GithubClient.Issues.EditComment(ctx, pr.RepoOwner, pr.RepoName, comment.GetID(), &github.IssueComment{Body: &body})
+			return comment.GetID(), err
+		}
+	}
+	if !create {
+		return 0, nil
+	}
+
+	comment, _, err := s.GithubClient.Issues.CreateComment(ctx, pr.RepoOwner, pr.RepoName, pr.Number, &github.IssueComment{Body: &body})
+	if err != nil {
+		return 0, err
+	}
+	return comment.GetID(), nil
+}
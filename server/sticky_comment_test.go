@@ -0,0 +1,100 @@
+// Copyright (c) 2017-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package server
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/go-github/v39/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-mattermod/model"
+	"github.com/mattermost/mattermost-mattermod/server/mocks"
+)
+
+func TestPublishStickyComment(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctxInterface := reflect.TypeOf((*context.Context)(nil)).Elem()
+	is := mocks.NewMockIssuesService(ctrl)
+	s := &Server{
+		Config:       &Config{Username: "mattermod"},
+		GithubClient: &GithubClient{Issues: is},
+	}
+	ok := &github.Response{Response: &http.Response{StatusCode: http.StatusOK}}
+	notFound := &github.Response{Response: &http.Response{StatusCode: http.StatusNotFound}}
+
+	pr := &model.PullRequest{RepoOwner: "mattermost", RepoName: "server", Number: 12}
+	key := "mattermost/server#12 <!-- marker -->"
+	render := func(body string) stickyCommentRenderer {
+		return func() (string, bool, error) {
+			return "<!-- marker -->\n" + body, true, nil
+		}
+	}
+
+	t.Run("Deleted comments are created again", func(t *testing.T) {
+		s.stickyComments = map[string]*stickyComment{key: {id: 7}}
+		gomock.InOrder(
+			is.EXPECT().EditComment(gomock.AssignableToTypeOf(ctxInterface), "mattermost", "server", int64(7), gomock.Any()).
+				Return(nil, notFound, &github.ErrorResponse{Response: notFound.Response}),
+			is.EXPECT().ListComments(gomock.AssignableToTypeOf(ctxInterface), "mattermost", "server", 12, gomock.Any()).
+				Return([]*github.IssueComment{{
+					ID:   github.Int64(3),
+					User: &github.User{Login: github.String("someone")},
+					Body: github.String("<!-- marker -->"),
+				}}, ok, nil),
+			is.EXPECT().CreateComment(gomock.AssignableToTypeOf(ctxInterface), "mattermost", "server", 12, &github.IssueComment{Body: github.String("<!-- marker -->\nnew")}).
+				Return(&github.IssueComment{ID: github.Int64(8)}, ok, nil),
+		)
+
+		require.NoError(t, s.publishStickyComment(context.Background(), pr, "<!-- marker -->", render("new")))
+		assert.Equal(t, int64(8), s.stickyComments[key].id)
+	})
+
+	t.Run("Concurrent updates are published after the running one", func(t *testing.T) {
+		s.stickyComments = map[string]*stickyComment{key: {id: 8}}
+		var bodies []string
+		is.EXPECT().EditComment(gomock.AssignableToTypeOf(ctxInterface), "mattermost", "server", int64(8), gomock.Any()).
+			DoAndReturn(func(_ context.Context, _, _ string, _ int64, comment *github.IssueComment) (*github.IssueComment, *github.Response, error) {
+				bodies = append(bodies, comment.GetBody())
+				if len(bodies) == 1 {
+					// Updates arriving meanwhile don't write themselves.
+					require.NoError(t, s.publishStickyComment(context.Background(), pr, "<!-- marker -->", render("second")))
+					require.NoError(t, s.publishStickyComment(context.Background(), pr, "<!-- marker -->", render("third")))
+				}
+				return nil, ok, nil
+			}).
+			Times(2)
+
+		require.NoError(t, s.publishStickyComment(context.Background(), pr, "<!-- marker -->", render("first")))
+		assert.Equal(t, []string{"<!-- marker -->\nfirst", "<!-- marker -->\nthird"}, bodies)
+		assert.False(t, s.stickyComments[key].publishing)
+	})
+
+	t.Run("The cache is bounded", func(t *testing.T) {
+		s.stickyComments = map[string]*stickyComment{}
+		for i := 0; i < stickyCommentCacheSize; i++ {
+			s.stickyComments[fmt.Sprint(i)] = &stickyComment{id: int64(i)}
+		}
+		is.EXPECT().EditComment(gomock.AssignableToTypeOf(ctxInterface), "mattermost", "server", int64(9), gomock.Any()).
+			Return(nil, ok, nil)
+		is.EXPECT().ListComments(gomock.AssignableToTypeOf(ctxInterface), "mattermost", "server", 12, gomock.Any()).
+			Return([]*github.IssueComment{{
+				ID:   github.Int64(9),
+				User: &github.User{Login: github.String("mattermod")},
+				Body: github.String("<!-- marker -->"),
+			}}, ok, nil)
+
+		require.NoError(t, s.publishStickyComment(context.Background(), pr, "<!-- marker -->", render("new")))
+		assert.Len(t, s.stickyComments, stickyCommentCacheSize)
+		assert.Equal(t, int64(9), s.stickyComments[key].id)
+	})
+}
@@ -9,6 +9,25 @@ import (
 	"github.com/mattermost/mattermost-server/v6/shared/mlog"
 )
 
+// translationsAutomation notifies the translations channel about PRs of the
+// translations bot.
+type translationsAutomation struct {
+	baseAutomation
+	s *Server
+}
+
+func (a *translationsAutomation) Name() string {
+	return "translations"
+}
+
+func (a *translationsAutomation) OnPullRequest(ctx context.Context, event *pullRequestEvent, pr *model.PullRequest) error {
+	switch event.Action {
+	case prEventOpened, prEventReOpened:
+		a.s.handleTranslationPR(ctx, pr)
+	}
+	return nil
+}
+
 func (s *Server) handleTranslationPR(ctx context.Context, pr *model.PullRequest) {
 	if !s.isTranslationPr(pr) {
 		return
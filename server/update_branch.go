@@ -13,7 +13,6 @@ import (
 )
 
 const (
-	msgCommenterPermission    = "Looks like you don't have permissions to trigger this command.\n Only available for the PR submitter and org members"
 	msgOrganizationPermission = "We don't have permissions to update this PR, please contact the submitter to apply the update."
 	msgUpdatePullRequest      = "Error trying to update the PR.\nPlease do it manually."
 )
@@ -24,8 +23,6 @@ type updateError struct {
 
 func (e *updateError) Error() string {
 	switch e.source {
-	case msgCommenterPermission:
-		return commenterNoPermissions
 	case msgOrganizationPermission:
 		return "we don't have permissions"
 	case msgUpdatePullRequest:
@@ -35,7 +32,7 @@ func (e *updateError) Error() string {
 	}
 }
 
-func (s *Server) handleUpdateBranch(ctx context.Context, commenter string, pr *model.PullRequest) error {
+func (s *Server) handleUpdateBranch(ctx context.Context, pr *model.PullRequest) error {
 	var uerr *updateError
 	defer func() {
 		if uerr != nil {
@@ -45,12 +42,6 @@ func (s *Server) handleUpdateBranch(ctx context.Context, commenter string, pr *m
 		}
 	}()
 
-	// If the commenter is not the PR submitter, check if the PR submitter is an org member
-	if (commenter != pr.Username && !s.IsOrgMember(commenter)) || s.IsInBotBlockList(commenter) {
-		uerr = &updateError{source: msgCommenterPermission}
-		return uerr
-	}
-
 	repoInfo := strings.Split(pr.FullName, "/")
 	if repoInfo[0] != s.Config.Org {
 		if !pr.GetMaintainerCanModify() {
@@ -70,7 +61,8 @@ func (s *Server) handleUpdateBranch(ctx context.Context, commenter string, pr *m
 	}
 	if err != nil && !strings.Contains(err.Error(), "job scheduled on GitHub side; try again later") {
 		uerr = &updateError{source: msgUpdatePullRequest}
-		return fmt.Errorf("%s: %w", uerr, err)
+		// The failure is commented, so the command isn't retried.
+		return newPermanentError(fmt.Errorf("%s: %w", uerr, err))
 	}
 
 	return nil
@@ -46,34 +46,16 @@ func TestHandeUpdateBranch(t *testing.T) {
 	msg := new(string)
 	comment := &github.IssueComment{Body: msg}
 	is := mocks.NewMockIssuesService(ctrl)
-	is.EXPECT().CreateComment(ctx, pr.RepoOwner, pr.RepoName, pr.Number, comment).Times(5).Return(nil, nil, nil)
+	is.EXPECT().CreateComment(ctx, pr.RepoOwner, pr.RepoName, pr.Number, comment).Times(3).Return(nil, nil, nil)
 	s.GithubClient.Issues = is
 
-	t.Run("random user", func(t *testing.T) {
-		*msg = msgCommenterPermission
-
-		err := s.handleUpdateBranch(ctx, "someone", pr)
-		require.Error(t, err)
-		require.IsType(t, &updateError{}, err)
-		require.Equal(t, err.(*updateError).source, *msg)
-	})
-
-	t.Run("not org. member", func(t *testing.T) {
-		*msg = msgCommenterPermission
-
-		err := s.handleUpdateBranch(ctx, userHandle, pr)
-		require.Error(t, err)
-		require.IsType(t, &updateError{}, err)
-		require.Equal(t, err.(*updateError).source, *msg)
-	})
-
 	t.Run("app does not have permissions", func(t *testing.T) {
 		s.OrgMembers = make([]string, 1)
 		s.OrgMembers[0] = userHandle
 
 		*msg = msgOrganizationPermission
 
-		err := s.handleUpdateBranch(ctx, userHandle, pr)
+		err := s.handleUpdateBranch(ctx, pr)
 		require.Error(t, err)
 		require.IsType(t, &updateError{}, err)
 		require.Equal(t, err.(*updateError).source, *msg)
@@ -91,7 +73,7 @@ func TestHandeUpdateBranch(t *testing.T) {
 		prs.EXPECT().UpdateBranch(ctx, pr.RepoOwner, pr.RepoName, pr.Number, gomock.AssignableToTypeOf(opt)).Return(nil, nil, expectedErr)
 		s.GithubClient.PullRequests = prs
 
-		err := s.handleUpdateBranch(ctx, userHandle, pr)
+		err := s.handleUpdateBranch(ctx, pr)
 		require.Error(t, err)
 		require.True(t, errors.Is(err, expectedErr))
 	})
@@ -113,7 +95,7 @@ func TestHandeUpdateBranch(t *testing.T) {
 		prs.EXPECT().UpdateBranch(ctx, pr.RepoOwner, pr.RepoName, pr.Number, gomock.AssignableToTypeOf(opt)).Return(nil, resp, nil)
 		s.GithubClient.PullRequests = prs
 
-		err := s.handleUpdateBranch(ctx, userHandle, pr)
+		err := s.handleUpdateBranch(ctx, pr)
 		require.Error(t, err)
 		require.IsType(t, &updateError{}, err)
 		require.Equal(t, err.(*updateError).source, *msg)
@@ -131,7 +113,7 @@ func TestHandeUpdateBranch(t *testing.T) {
 		prs.EXPECT().UpdateBranch(ctx, pr.RepoOwner, pr.RepoName, pr.Number, gomock.AssignableToTypeOf(opt)).Return(nil, resp, nil)
 		s.GithubClient.PullRequests = prs
 
-		err := s.handleUpdateBranch(ctx, userHandle, pr)
+		err := s.handleUpdateBranch(ctx, pr)
 		require.Nil(t, err)
 	})
 
@@ -146,7 +128,7 @@ func TestHandeUpdateBranch(t *testing.T) {
 		prs.EXPECT().UpdateBranch(ctx, pr.RepoOwner, pr.RepoName, pr.Number, gomock.AssignableToTypeOf(opt)).Return(nil, nil, errors.New("job scheduled on GitHub side; try again later"))
 		s.GithubClient.PullRequests = prs
 
-		err := s.handleUpdateBranch(ctx, userHandle, pr)
+		err := s.handleUpdateBranch(ctx, pr)
 		require.Nil(t, err)
 	})
 }
@@ -3,10 +3,13 @@ package server
 import (
 	"bytes"
 	"crypto/hmac"
-	"crypto/sha1" //nolint:gosec // GitHub webhooks are signed using sha1 https://developer.github.com/webhooks/.
+	"crypto/sha1" //nolint:gosec // GitHub still sends a sha1 signature in X-Hub-Signature https://docs.github.com/en/developers/webhooks-and-events/webhooks/securing-your-webhooks.
+	"crypto/sha256"
 	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"hash"
 	"io"
 	"net/http"
 	"strings"
@@ -14,15 +17,24 @@ import (
 	"github.com/mattermost/mattermost-server/v6/shared/mlog"
 )
 
+const (
+	signatureAlgSHA1   = "sha1"
+	signatureAlgSHA256 = "sha256"
+
+	legacyWebhookSecretID = "default"
+)
+
 func (s *Server) withValidation(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		if r.RequestURI == "/healthz" {
+		// Admin endpoints are authenticated by withAdminAuth instead.
+		if r.RequestURI == "/healthz" || strings.HasPrefix(r.URL.Path, "/admin/") {
 			next.ServeHTTP(w, r)
 			return
 		}
-		receivedHash := strings.SplitN(r.Header.Get("X-Hub-Signature"), "=", 2)
-		if receivedHash[0] != "sha1" {
-			mlog.Error("Invalid webhook hash signature: SHA1")
+
+		receivedHash := getReceivedSignature(r.Header)
+		if receivedHash == nil {
+			mlog.Error("Invalid webhook hash signature: expected SHA256 or SHA1")
 			w.WriteHeader(http.StatusBadRequest)
 			return
 		}
@@ -36,29 +48,98 @@ func (s *Server) withValidation(next http.Handler) http.Handler {
 		r.Body.Close()
 		r.Body = io.NopCloser(bytes.NewBuffer(buf))
 
-		err = validateSignature(receivedHash, buf, s.Config.GitHubWebhookSecret)
-		if err != nil {
-			mlog.Error(err.Error())
-			w.WriteHeader(http.StatusUnauthorized)
-			return
+		for _, secret := range s.webhookSecretsFor(buf) {
+			if err = validateSignature(receivedHash, buf, secret.Secret); err == nil {
+				s.Metrics.IncreaseWebhookSignatureValidations(secret.ID, receivedHash[0])
+				next.ServeHTTP(w, r)
+				return
+			}
 		}
 
-		next.ServeHTTP(w, r)
+		mlog.Error("Webhook signature does not match any of the configured secrets", mlog.String("algorithm", receivedHash[0]))
+		w.WriteHeader(http.StatusUnauthorized)
 	})
 }
 
+// getReceivedSignature returns the algorithm and the signature sent by GitHub,
+// preferring the SHA256 signature. It returns nil if neither is present.
+func getReceivedSignature(header http.Header) []string {
+	if sig := strings.SplitN(header.Get("X-Hub-Signature-256"), "=", 2); len(sig) == 2 && sig[0] == signatureAlgSHA256 {
+		return sig
+	}
+	if sig := strings.SplitN(header.Get("X-Hub-Signature"), "=", 2); len(sig) == 2 && sig[0] == signatureAlgSHA1 {
+		return sig
+	}
+	return nil
+}
+
+// webhookSecretsFor returns the secrets a webhook request may be signed with.
+// Secrets of the repository the payload belongs to are tried first.
+func (s *Server) webhookSecretsFor(payload []byte) []*WebhookSecret {
+	var secrets []*WebhookSecret
+
+	if repo := s.webhookRepository(payload); repo != nil {
+		secrets = append(secrets, repo.WebhookSecrets...)
+	}
+	secrets = append(secrets, s.Config.GitHubWebhookSecrets...)
+	if s.Config.GitHubWebhookSecret != "" {
+		secrets = append(secrets, &WebhookSecret{ID: legacyWebhookSecretID, Secret: s.Config.GitHubWebhookSecret})
+	}
+
+	return secrets
+}
+
+// webhookRepository looks up the configured repository a webhook payload
+// belongs to. The payload is only parsed if any repository has its own secrets.
+func (s *Server) webhookRepository(payload []byte) *Repository {
+	hasRepoSecrets := false
+	for _, repo := range s.Config.Repositories {
+		if len(repo.WebhookSecrets) > 0 {
+			hasRepoSecrets = true
+			break
+		}
+	}
+	if !hasRepoSecrets {
+		return nil
+	}
+
+	var event struct {
+		Repository struct {
+			Name  string `json:"name"`
+			Owner struct {
+				Login string `json:"login"`
+			} `json:"owner"`
+		} `json:"repository"`
+	}
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return nil
+	}
+
+	repo, ok := GetRepository(s.Config.Repositories, event.Repository.Owner.Login, event.Repository.Name)
+	if !ok {
+		return nil
+	}
+	return repo
+}
+
 // validateSignature function used for validation of webhook requests based on
-// config secret.
+// config secret. The received hash is compared in constant time.
 func validateSignature(receivedHash []string, bodyBuffer []byte, secretKey string) error {
-	hash := hmac.New(sha1.New, []byte(secretKey))
-	if _, err := hash.Write(bodyBuffer); err != nil {
+	var newHash func() hash.Hash = sha1.New
+	if receivedHash[0] == signatureAlgSHA256 {
+		newHash = sha256.New
+	}
+
+	mac := hmac.New(newHash, []byte(secretKey))
+	if _, err := mac.Write(bodyBuffer); err != nil {
 		msg := fmt.Sprintf("Cannot compute the HMAC for request: %s\n", err)
 		return errors.New(msg)
 	}
 
-	expectedHash := hex.EncodeToString(hash.Sum(nil))
-	if receivedHash[1] != expectedHash {
-		msg := fmt.Sprintf("Expected Hash does not match the received hash: %s\n", expectedHash)
+	expectedHash := mac.Sum(nil)
+	received, err := hex.DecodeString(receivedHash[1])
+	if err != nil || !hmac.Equal(received, expectedHash) {
+		msg := fmt.Sprintf("Expected Hash does not match the received hash: %s\n", hex.EncodeToString(expectedHash))
 		return errors.New(msg)
 	}
 
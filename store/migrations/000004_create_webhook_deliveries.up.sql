@@ -0,0 +1,18 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS `WebhookDeliveries`
+  (
+    `DeliveryID` varchar(64) NOT NULL,
+    `EventType` varchar(64) NOT NULL,
+    `Payload` mediumblob NOT NULL,
+    `State` varchar(16) NOT NULL,
+    `LastError` text NOT NULL,
+    `Attempts` int(11) NOT NULL DEFAULT 0,
+    `NextAttemptAt` bigint(20) NOT NULL DEFAULT 0,
+    `CreatedAt` bigint(20) NOT NULL,
+    `UpdatedAt` bigint(20) NOT NULL,
+    PRIMARY KEY(`DeliveryID`),
+    KEY `idx_webhookdeliveries_state_nextattemptat` (`State`, `NextAttemptAt`)
+  ) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+COMMIT;
@@ -0,0 +1,21 @@
+BEGIN;
+
+SET @dbName = DATABASE();
+SET @tableName = "PullRequests";
+SET @indexName = "idx_pullrequests_sha";
+SET @preparedStatement = (SELECT IF(
+  (
+    SELECT COUNT(*) FROM INFORMATION_SCHEMA.STATISTICS
+    WHERE
+      (table_name = @tableName)
+      AND (table_schema = @dbName)
+      AND (index_name = @indexName)
+  ) > 0,
+  "SELECT 1",
+  CONCAT("CREATE INDEX ", @indexName, " ON ", @tableName, " (RepoOwner, RepoName, Sha);")
+));
+PREPARE createIndexIfNotExists FROM @preparedStatement;
+EXECUTE createIndexIfNotExists;
+
+DEALLOCATE PREPARE createIndexIfNotExists;
+COMMIT;
@@ -0,0 +1,14 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS `PendingMerges`
+  (
+    `RepoOwner` varchar(128) NOT NULL,
+    `RepoName` varchar(128) NOT NULL,
+    `Number` int(11) NOT NULL,
+    `MergeMethod` varchar(16) NOT NULL,
+    `RequestedBy` varchar(128) NOT NULL,
+    `CreatedAt` bigint(20) NOT NULL,
+    PRIMARY KEY(`RepoOwner`, `RepoName`, `Number`)
+  ) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+COMMIT;
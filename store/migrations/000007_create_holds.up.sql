@@ -0,0 +1,14 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS `Holds`
+  (
+    `RepoOwner` varchar(128) NOT NULL,
+    `RepoName` varchar(128) NOT NULL,
+    `Number` int(11) NOT NULL,
+    `Holder` varchar(128) NOT NULL,
+    `Reason` text NOT NULL,
+    `CreatedAt` bigint(20) NOT NULL,
+    PRIMARY KEY(`RepoOwner`, `RepoName`, `Number`, `Holder`)
+  ) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+COMMIT;
@@ -0,0 +1,26 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS `CherryPickJobs`
+  (
+    `ID` bigint(20) NOT NULL AUTO_INCREMENT,
+    `RepoOwner` varchar(128) NOT NULL,
+    `RepoName` varchar(128) NOT NULL,
+    `Number` int(11) NOT NULL,
+    `Branch` varchar(255) NOT NULL,
+    `MergeCommitSHA` varchar(48) NOT NULL,
+    `Milestone` int(11) NOT NULL DEFAULT 0,
+    `Kind` varchar(32) NOT NULL DEFAULT 'cherry-pick',
+    `State` varchar(16) NOT NULL,
+    `Attempts` int(11) NOT NULL DEFAULT 0,
+    `NextAttemptAt` bigint(20) NOT NULL DEFAULT 0,
+    `Output` mediumtext NOT NULL,
+    `Conflicts` text NOT NULL,
+    `NewPRNumber` int(11) NOT NULL DEFAULT 0,
+    `CreatedAt` bigint(20) NOT NULL,
+    `UpdatedAt` bigint(20) NOT NULL,
+    PRIMARY KEY(`ID`),
+    KEY `idx_cherrypickjobs_pr` (`RepoOwner`, `RepoName`, `Number`),
+    KEY `idx_cherrypickjobs_state` (`State`)
+  ) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+COMMIT;
@@ -0,0 +1,19 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS `Backports`
+  (
+    `RepoOwner` varchar(128) NOT NULL,
+    `RepoName` varchar(128) NOT NULL,
+    `Number` int(11) NOT NULL,
+    `Branch` varchar(255) NOT NULL,
+    `Milestone` varchar(255) NOT NULL DEFAULT '',
+    `BackportNumber` int(11) NOT NULL DEFAULT 0,
+    `State` varchar(16) NOT NULL,
+    `CreatedAt` bigint(20) NOT NULL,
+    `UpdatedAt` bigint(20) NOT NULL,
+    PRIMARY KEY(`RepoOwner`, `RepoName`, `Number`, `Branch`),
+    KEY `idx_backports_backport` (`RepoOwner`, `RepoName`, `BackportNumber`),
+    KEY `idx_backports_milestone` (`Milestone`)
+  ) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+COMMIT;
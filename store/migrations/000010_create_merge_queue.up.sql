@@ -0,0 +1,13 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS `MergeQueue`
+  (
+    `RepoOwner` varchar(128) NOT NULL,
+    `RepoName` varchar(128) NOT NULL,
+    `Number` int(11) NOT NULL,
+    `CreatedAt` bigint(20) NOT NULL,
+    PRIMARY KEY(`RepoOwner`, `RepoName`, `Number`),
+    KEY `idx_merge_queue_created_at` (`CreatedAt`)
+  ) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+COMMIT;
@@ -6,6 +6,20 @@
 // 000002_add_milestone.up.sql (1.069kB)
 // 000003_drop_spinmint_table.down.sql (332B)
 // 000003_drop_spinmint_table.up.sql (49B)
+// 000004_create_webhook_deliveries.down.sql (59B)
+// 000004_create_webhook_deliveries.up.sql (566B)
+// 000005_add_pull_requests_sha_index.down.sql (530B)
+// 000005_add_pull_requests_sha_index.up.sql (574B)
+// 000006_create_pending_merges.down.sql (55B)
+// 000006_create_pending_merges.up.sql (384B)
+// 000007_create_holds.down.sql (47B)
+// 000007_create_holds.up.sql (369B)
+// 000008_create_cherry_pick_jobs.down.sql (56B)
+// 000008_create_cherry_pick_jobs.up.sql (889B)
+// 000009_create_backports.down.sql (51B)
+// 000009_create_backports.up.sql (642B)
+// 000010_create_merge_queue.down.sql (52B)
+// 000010_create_merge_queue.up.sql (352B)
 
 package migrations
 
@@ -73,7 +87,7 @@ func (fi bindataFileInfo) Sys() interface{} {
 	return nil
 }
 
-var __000001_baseDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x72\x72\x75\xf7\xf4\xb3\xe6\xe2\x72\x09\xf2\x0f\x50\x08\x71\x74\xf2\x71\x55\xf0\x74\x53\x70\x8d\xf0\x0c\x0e\x09\x56\x48\xf0\x2c\x2e\x2e\x4d\x2d\x4e\xb0\xc6\x21\x1d\x50\x9a\x93\x13\x94\x5a\x58\x9a\x5a\x5c\x82\x5b\x51\x70\x41\x66\x5e\x6e\x66\x5e\x49\x82\x35\x17\x97\xb3\xbf\xaf\xaf\x67\x88\x35\x17\x20\x00\x00\xff\xff\x9d\x30\xa8\xa4\x76\x00\x00\x00")
+var __000001_baseDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x00\x76\x00\x89\xff\x42\x45\x47\x49\x4e\x3b\x0a\x0a\x44\x52\x4f\x50\x20\x54\x41\x42\x4c\x45\x20\x49\x46\x20\x45\x58\x49\x53\x54\x53\x20\x60\x49\x73\x73\x75\x65\x73\x60\x3b\x0a\x44\x52\x4f\x50\x20\x54\x41\x42\x4c\x45\x20\x49\x46\x20\x45\x58\x49\x53\x54\x53\x20\x60\x50\x75\x6c\x6c\x52\x65\x71\x75\x65\x73\x74\x73\x60\x3b\x0a\x44\x52\x4f\x50\x20\x54\x41\x42\x4c\x45\x20\x49\x46\x20\x45\x58\x49\x53\x54\x53\x20\x60\x53\x70\x69\x6e\x6d\x69\x6e\x74\x60\x3b\x0a\x0a\x43\x4f\x4d\x4d\x49\x54\x3b\x0a\x03\x00\x9d\x30\xa8\xa4\x76\x00\x00\x00")
 
 func _000001_baseDownSqlBytes() ([]byte, error) {
 	return bindataRead(
@@ -93,7 +107,7 @@ func _000001_baseDownSql() (*asset, error) {
 	return a, nil
 }
 
-var __000001_baseUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xd4\x56\x41\x6f\xda\x30\x18\xbd\xf3\x2b\xbc\x13\xb4\x03\x35\x69\xa9\x54\xad\x42\x22\x04\xd3\x5a\x4d\xec\x36\x71\xa6\xb5\x17\x63\xc0\xb4\xd1\x82\xe9\x12\xa7\x5b\xff\xfd\x14\x28\x24\x21\x4e\xe1\x50\x4d\x1d\x27\xf3\xf9\xf9\xe5\xfb\xec\xf7\xa4\x37\x80\x57\x08\x5f\x36\x1a\x27\xc7\x5f\xba\x86\x69\x98\xc0\x87\x14\xf4\x89\x33\x64\xf6\xb5\xe5\x59\x36\x85\x1e\xf3\x21\x65\xb6\x83\x20\xa6\xbd\x7e\x5f\x57\x06\xc7\x27\x97\x7b\x19\x3c\xe8\x07\x0e\xf5\x2b\x14\x6f\xf5\x3a\x0e\xe2\x38\x16\x45\x04\x33\x9b\x60\x0c\xed\x6c\x99\x51\x68\xca\x55\x06\x6c\xb9\xd0\x07\xa9\x9a\x5f\x14\xf7\xce\x72\x76\x8a\x5c\xc8\x1e\x08\x86\xbd\x7e\x7f\xbb\xae\x62\x73\x58\xf3\xab\x61\x7c\x33\x8c\x66\x8e\x31\xcc\x6e\xce\x17\x60\x74\x17\x40\x66\x5f\x43\xfb\x26\x9b\xb4\xf4\xbf\x0d\xca\xdb\x46\x0d\xc9\x88\x78\x10\x5d\x61\x76\x03\xef\x73\xa6\x6a\xb1\x0d\x34\x40\xa3\xe6\x1a\xfd\x3b\x87\xb9\x64\x98\xcd\xb9\x59\xb6\xc1\xb6\xd8\xc4\x84\x59\x01\x25\xec\xbb\xe5\x04\x90\x11\xcc\x1e\xa0\x47\x0a\x43\x9a\xe6\x0e\x17\x26\x14\xfa\x6f\x64\xab\xf5\x9a\x6d\x5d\x5e\x37\xd1\xe8\x74\x1a\x9d\x0e\xa0\x7c\x12\x09\x90\xa8\x38\x9d\xaa\x34\x16\x60\xbe\x8c\x81\x5a\xd5\xc6\x28\x49\x52\x91\x8c\x33\xe0\x4e\xcb\x09\x7f\x11\x33\x36\x4d\xd8\x34\x0a\x85\x54\x20\xfb\xf5\x40\xbf\x3f\x7d\xe2\x31\x9f\x2a\x11\xb3\x44\xa8\xcd\x66\x65\x62\x2d\xaa\x97\xeb\xc0\xf6\xa0\x45\x21\xa0\xd6\xc0\x81\x00\x8d\x00\x26\x14\xc0\x1f\xc8\xa7\xfe\xb6\x27\xd0\x6a\x00\x30\xf6\xc4\xf3\x92\xfc\x96\x22\x1e\x83\x17\x1e\x67\xb4\x2d\xf3\xf4\xe2\x68\x75\x00\x07\x8e\xd3\xde\x80\x30\x5f\x88\xf7\x30\x38\x5d\x4c\x32\x96\x50\xaa\x96\x69\xee\x6c\x06\x89\x88\x65\x95\x60\x08\x47\x56\xe0\x14\x70\xbe\xe2\xaa\x00\xd2\x41\x1c\x3e\x11\x51\x32\x06\x4a\xfc\x51\x59\xe1\xd6\x43\xae\xe5\xdd\x83\x1b\x78\x0f\x5a\x85\x71\xda\x79\xd7\xed\x4d\x73\x47\x8d\x23\x00\xf1\x15\xc2\xb0\x87\xa4\x5c\x0e\x07\x5b\xfa\xcc\xaf\x3e\xa4\xbd\xec\x02\x17\x93\xee\x61\xb7\x5d\x79\xc3\xc3\x34\x71\x9b\x46\x91\x27\x7e\xa5\x22\x51\x9f\x4c\x19\xa5\xce\x3e\x58\x1f\xa3\x34\x8a\xca\x98\x53\xe3\xe2\x2c\x7f\xe0\x66\xf3\xe3\x64\xe4\x89\xf9\x5e\xa5\x3d\xf1\x1c\xd2\x3d\x44\x68\x87\x88\x73\x90\x86\xd1\x2c\xc3\xa5\xc9\x01\x40\x7b\x29\xa7\x51\x9a\x84\x4b\x59\xbc\x94\x3a\xb4\x13\xca\x9f\x85\x6e\x02\xcf\xd9\x73\x95\x76\x2c\xb8\x12\x33\x4b\x8d\x81\x0a\x17\x22\x51\x7c\xf1\xbc\xe2\xac\x7e\xc0\xe5\xa1\x54\x3c\x94\x22\xb6\xb9\x74\x97\xb3\x70\xfe\x9a\x1d\x92\xaf\xab\x67\xd0\x74\xe4\x8a\xf8\x51\xcc\xde\xc5\xfc\x7f\xc6\xf4\x9f\x43\xb9\x08\xa5\xfa\x5c\xa6\xdc\x76\xb5\x36\x24\x92\x89\xe2\x72\x2a\xd0\x6c\x9f\x23\x77\x6c\x7b\x7a\x7e\xae\xb5\xca\xae\x75\xf5\xb8\x5d\x5f\x56\x00\x05\xb5\x4d\xc2\xc7\x0c\xa6\x93\x72\x59\x14\x85\x59\xfe\x85\x02\x6a\x02\x4f\x39\x26\xad\xb5\x52\xfa\x46\x9e\x2c\x8a\x39\xa3\x1a\x6d\x74\xa9\x46\x9f\x76\xaa\x67\x77\x62\x55\x25\x69\x55\x35\xa5\x0f\xaf\x75\xb1\x76\xdf\xf9\x6d\x74\xad\x4d\xb5\x1a\x06\x6d\x70\xad\x8b\xb4\xd5\xa8\x55\x48\x59\xa5\xd0\xb5\x7e\x02\x9b\xb8\x2e\xa2\x97\x8d\xbf\x01\x00\x00\xff\xff\x3e\x87\xf5\x95\xbf\x0b\x00\x00")
+var __000001_baseUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xd4\x96\x41\x6f\xc2\x36\x14\xc7\xef\xf9\x14\xde\x09\xe8\x40\x4d\x28\x95\xaa\x55\x48\x09\xc1\xb4\x51\x13\xbb\x4d\x9c\x69\xed\xc5\x18\x30\x6d\xb4\x60\xba\xc4\xe9\xd6\x6f\x3f\x25\x2d\x24\xc1\x49\xe1\x50\x4d\x1d\x27\x63\xff\xde\x3f\xef\xd9\xef\x49\xff\x09\xbc\x71\xd0\xb5\xa6\x9d\x9f\xfd\x32\xd2\x0d\xdd\x00\x01\x24\xc0\xc4\xee\x94\xda\xb7\x96\x6f\xd9\x04\xfa\x34\x80\x84\xda\xae\x03\x11\x19\x9b\x66\xd3\x36\x38\x3b\xbf\x3e\xaa\xe0\xc3\x20\x74\x49\xa0\x48\x7c\xee\xb7\x69\x60\xd7\xb5\x88\x83\x11\xb5\x31\x42\xd0\xce\x97\xb9\x44\xc3\xb6\xaa\x80\x2c\x0f\x06\x20\x93\xeb\xab\xea\xd9\x45\xa9\x4e\x1c\x0f\xd2\x27\x8c\xe0\xd8\x34\xf7\x6b\x95\x2d\xb1\xce\xaf\xba\xfe\x9b\xae\x77\x4a\x46\x37\x46\xa5\x5e\x88\x9c\x87\x10\x52\xfb\x16\xda\x77\x79\xa5\xb5\xff\x7d\x50\x3f\xd6\x5b\x44\x66\xd8\x87\xce\x0d\xa2\x77\xf0\x71\x87\x9a\xa6\xba\xd9\x07\x0d\xa0\xae\x5e\x42\xa1\x19\x3c\xb8\xd4\xc3\xd3\xbc\xce\xdd\xb2\x0f\xf6\x9b\x1d\x84\xa9\x15\x12\x4c\x7f\xb7\xdc\x10\x52\x8c\xe8\x13\xf4\x71\xa5\x48\xc3\x38\xd0\x42\x98\xc0\xe0\x53\xac\x58\x7f\xa8\x15\xcb\xcf\xc2\xb4\xc1\x40\x1b\x0c\x00\x61\x8b\x98\x83\x54\x26\xd9\x52\x66\x09\x07\xeb\x6d\x02\x64\xb1\x37\x77\xd2\x34\xe3\xe9\x3c\x07\x0f\x52\x4e\xd9\x1b\x5f\xd1\x65\x4a\x97\x71\xc4\x85\x04\xf9\x6f\x0c\x4c\x73\xf9\xc2\x12\xb6\x94\x3c\xa1\x29\x97\xbb\x43\xa5\xe2\x46\x6a\x5c\xf6\x81\xed\x43\x8b\x40\x40\xac\x89\x0b\x81\x33\x03\x08\x13\x00\xff\x70\x02\x12\xec\x73\x02\x5d\x0d\x80\xb9\xcf\x5f\xb7\xf8\x6f\xc1\x93\x39\x78\x63\x49\x2e\xdb\x35\x86\x57\xbd\x22\x00\x85\xae\xdb\xdf\x41\x88\x6d\xf8\x57\x0c\xca\x36\x8b\x5c\x25\x12\xb2\x6b\x18\x07\x87\x61\xca\x13\xa1\x0a\x4c\xe1\xcc\x0a\xdd\x0a\x17\x48\x26\x2b\x50\x13\xe2\xb2\x05\x8f\xd3\x39\x90\xfc\x1f\x99\xc7\xdc\xfb\x8e\x67\xf9\x8f\xe0\x0e\x3e\x82\x6e\xa5\x9c\x7e\x99\x75\x7f\x97\x5c\x4f\xeb\x01\x88\x6e\x1c\x04\xc7\x8e\x10\xdb\xe9\x64\x2f\x9f\x8f\x7c\x00\xc9\x38\xbf\xc0\xcd\x62\x74\xda\x6d\x2b\x6f\x78\x5a\x4f\xdc\x67\x71\xec\xf3\xbf\x32\x9e\xca\x1f\xd6\x19\xb5\xcc\xbe\xb9\x3f\x66\x59\x1c\xd7\x99\xa1\x7e\x75\x51\x3e\x70\xa7\xf3\x7d\x6d\xe4\xf3\xf5\x31\x24\x78\x61\x25\x32\x3a\xa5\xd1\x4e\x69\xce\x49\x16\xc5\xab\x9c\xcb\xd2\x13\x40\x7b\x2b\x96\x71\x96\x46\x5b\x51\xc2\x43\xbd\x8d\x76\x23\xf1\x67\x25\x9b\xd0\x77\x8f\x5c\xa5\x9d\x70\x26\xf9\xca\x92\x73\x20\xa3\x0d\x4f\x25\xdb\xbc\x16\x19\xa8\x1f\xf0\x58\x24\x24\x8b\x04\x4f\x6c\x26\xbc\xed\x2a\x5a\xbf\xe7\x41\xe2\xbd\x98\xe6\x5e\x43\x00\x4f\x9e\xf9\xea\x4b\xe6\xff\x37\x98\xc1\x6b\x24\x36\x91\x90\x3f\x6b\x28\xf7\x59\x7d\x0c\xa4\x23\x52\xc9\xc4\x92\x3b\xab\xaf\xa6\xad\x61\x6c\x87\x97\x97\x0d\x0f\xa9\x8e\x6e\x33\x77\x38\x97\x0a\x50\xe9\xb6\x45\xf4\x9c\x63\x43\xfd\x58\x53\x54\x6a\xf9\x2f\x3a\xa0\xc5\xf0\xd4\x6d\x52\xf1\x78\xf5\x6f\xec\x4d\x44\xcd\x67\xa8\xd6\xa6\xc1\xac\xb4\xb8\x1d\x35\xb6\xee\x9b\x54\xa7\x55\x46\xec\xb2\x6a\x36\xaf\x6d\xb6\xf6\x58\xfc\xde\xba\xb6\xba\xda\x06\x85\x46\xe3\xda\x66\x69\x55\xab\x55\xda\xa9\xba\xe9\x2a\x48\xcd\xc6\x9e\xe7\x90\x6b\xed\xdf\x01\x00\x3e\x87\xf5\x95\xbf\x0b\x00\x00")
 
 func _000001_baseUpSqlBytes() ([]byte, error) {
 	return bindataRead(
@@ -113,7 +127,7 @@ func _000001_baseUpSql() (*asset, error) {
 	return a, nil
 }
 
-var __000002_add_milestoneDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xec\x91\xcb\x6a\xf3\x30\x10\x85\xf7\x7a\x8a\x41\x2b\xfb\xc7\xfc\xb4\x6b\x93\x52\x45\x9e\x34\x06\x5b\x32\xb2\x42\xbb\x0b\x4e\x32\xa5\x01\xdb\x49\x6d\x19\xfa\xf8\xc5\xb7\xa6\xd7\x7d\x17\x5d\x08\xc4\xcc\xa7\xa3\x39\x73\x96\x78\x17\xab\x90\xb1\x1c\x2d\xdc\x1e\x76\xaa\xa8\x08\x16\x10\x09\x2b\x96\x22\x47\xcf\x0f\xc7\x8e\x2b\x76\x25\x4d\x4d\x9e\x75\x65\x69\xe8\xb9\xa3\xd6\xb5\x7c\x02\xf6\xa7\xb2\xab\xea\x99\x48\x8f\x25\xb5\xee\x54\x93\xea\xaa\x1d\x35\x33\x74\x6e\xe8\x5c\x34\x74\xc8\x5d\xe1\xa8\xa2\xda\xc1\x02\xbc\x1c\x13\x94\x16\xe2\x95\xc7\x00\xfa\x03\x30\x95\xa4\xde\x28\xeb\xfd\xf3\x61\x65\x74\x0a\xb1\x5a\x69\x93\x0a\x1b\x6b\xb5\xcd\xe5\x1a\x53\xf1\x5f\xea\x64\x93\xaa\x7c\x78\x73\xbf\x46\x83\xc3\x0d\xc0\x1b\xc6\xdd\xd6\xe3\x34\x97\xe1\xfd\xa9\x2f\x54\x34\x33\xed\xfe\x89\xaa\xa2\xa7\x46\xf3\x1f\x90\xd1\xd4\x9b\xce\xc5\x63\x4f\xf9\x70\x03\x57\x01\x03\x90\x5a\x49\x61\x3d\x2e\x12\x8b\x06\xac\x58\x26\x08\x3c\x78\xf7\x6d\x00\x1c\x22\xa3\xb3\xa1\x7a\x11\x09\x80\x87\xdc\xef\x15\xf8\x64\xf8\x9a\x33\xdf\x0f\x59\x66\x30\x13\x06\xa1\x28\x1d\x35\xf1\x23\xbe\x1c\x5b\xd7\x8e\x4b\xf8\xba\xc2\x90\xe1\x03\xca\x8d\xfd\x84\x87\x8c\x45\x28\x92\x44\x4b\x61\x11\xbe\x55\x9c\x53\xff\x21\x3a\x7b\x74\x25\xfd\x25\xf7\x3b\x93\x93\x3a\x4d\x63\x1b\xbe\x06\x00\x00\xff\xff\x95\xe7\xea\x19\xbe\x03\x00\x00")
+var __000002_add_milestoneDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xec\x91\x4f\x6b\xe3\x30\x10\xc5\xef\xfa\x14\x83\x4e\xf6\x62\x96\xdd\xb3\xc9\xb2\x8a\x3c\x69\x0c\xb6\x64\x64\x85\xf6\x16\x9c\x64\x4a\x03\xb2\x93\xda\x32\xf4\xe3\x97\xf8\x4f\xd3\xbf\xf7\x1e\x7a\x10\x88\x99\x9f\x9e\xe6\xcd\x5b\xe2\x4d\xaa\x62\xc6\x4a\xb4\xf0\xff\xb0\x53\x55\x4d\xb0\x80\x44\x58\xb1\x14\x25\x06\x61\x3c\x76\x7c\xb5\x73\x34\x35\x79\xd1\x3b\x67\xe8\xb1\xa7\xce\x77\x7c\x02\xf6\x27\xd7\xd7\xcd\x4c\xe4\x47\x47\x9d\x3f\x35\xa4\xfa\x7a\x47\xed\x0c\x9d\x5b\x3a\x57\x2d\x1d\x4a\x5f\x79\xaa\xa9\xf1\xb0\x80\xa0\xc4\x0c\xa5\x85\x74\x15\x30\x80\xcb\x01\x98\x4a\x52\x6f\x94\x0d\x7e\x85\xb0\x32\x3a\x87\x54\xad\xb4\xc9\x85\x4d\xb5\xda\x96\x72\x8d\xb9\xf8\x2d\x75\xb6\xc9\x55\x39\xbc\xb9\x5d\xa3\xc1\xe1\x06\x10\x0c\xe3\x6e\x9b\x71\x9a\xeb\xf0\xe1\xd4\x17\x2a\x99\x99\x6e\xff\x40\x75\x05\x8b\xd9\xfc\x1b\x64\x34\xf5\xa2\x73\xf5\x78\xa1\x42\xf8\x07\x7f\x22\x06\x20\xb5\x92\xc2\x06\x5c\x64\x16\x0d\x58\xb1\xcc\x10\x78\xf4\xea\xdb\x08\x38\x24\x46\x17\x43\xf5\x2a\x12\x01\x8f\x79\x78\x51\xe0\x93\xe1\xbf\x9c\x85\x61\xcc\x0a\x83\x85\x30\x08\x95\xf3\xd4\xa6\xf7\xf8\x74\xec\x7c\x37\x2e\xe1\xe3\x0a\x63\x86\x77\x28\x37\xf6\x1d\x1e\x33\x96\xa0\xc8\x32\x2d\x85\x45\xf8\x54\x71\x4e\xfd\x8b\xe8\xec\xd1\x3b\xfa\x49\xee\x7b\x26\x27\x75\x9e\xa7\x36\x7e\x1e\x00\x95\xe7\xea\x19\xbe\x03\x00\x00")
 
 func _000002_add_milestoneDownSqlBytes() ([]byte, error) {
 	return bindataRead(
@@ -133,7 +147,7 @@ func _000002_add_milestoneDownSql() (*asset, error) {
 	return a, nil
 }
 
-var __000002_add_milestoneUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xec\x92\xcf\x6a\xe3\x30\x10\xc6\xef\x7a\x8a\x41\x27\x6b\x31\xcb\xee\x42\x4e\x26\xcb\x2a\xf2\x64\x63\xb0\xa5\x20\x2b\x6d\x6f\xc1\x49\x54\x1a\xb0\x9d\xd4\x96\xa1\x7d\xfb\xe2\x7f\x75\x43\x9a\x07\x28\xf4\x60\xb0\x67\x7e\x33\xcc\xf7\x7d\x5e\xe0\xff\x48\x06\x84\xa4\x68\xe0\xdf\x61\x27\xb3\xc2\xc2\x1c\x42\x6e\xf8\x82\xa7\xe8\xb1\xa0\xef\xb8\x6c\x97\xdb\xa1\x49\xd7\x4d\x9e\x6b\xfb\xdc\xd8\xda\xd5\x74\x00\xf6\xa7\xbc\x29\xca\x91\x48\x8e\xb9\xad\xdd\xa9\xb4\xb2\x29\x76\xb6\xba\x84\xcc\xeb\xb9\x83\x22\x69\xc6\xc6\xb9\xb2\xe7\xac\xb2\x87\xd4\x65\xce\x16\xb6\x74\x30\x07\x2f\xc5\x18\x85\x81\x68\xe9\x11\x80\xf6\x01\x18\x4a\x42\x6d\xa4\xf1\x7e\x30\x58\x6a\x95\x40\x24\x97\x4a\x27\xdc\x44\x4a\x6e\x53\xb1\xc2\x84\xff\x14\x2a\xde\x24\x32\xed\x66\xee\x57\xa8\xb1\x7b\x03\xf0\x3a\x1d\xdb\xb2\x3f\x73\x52\xc5\x86\x3e\x97\xe1\xc8\xd4\xfb\x27\x5b\x64\x2d\xd5\xbb\x72\x81\xf4\x42\xde\xf7\x4c\xe2\x5b\x8a\xc1\x5f\xf8\xe5\x13\x00\x3a\x9c\xfb\x9b\xb6\x5f\x42\x49\xc1\x8d\x47\x79\x6c\x50\x83\xe1\x8b\x18\x81\xfa\x1f\x8e\xf0\x81\x02\x0f\xc3\xae\x38\x6d\x6c\xab\x53\xa5\xf5\xce\x07\x1a\x50\x46\x18\x0b\xc8\x5a\xe3\x9a\x6b\x84\x2c\x77\xb6\x8a\x1e\xe5\xc9\xe1\xcb\xb1\x76\x75\x6f\xcc\xb5\xad\x01\xc1\x07\x14\x1b\x73\x3d\x11\x10\x12\x22\x8f\x63\x25\xb8\x41\xb8\xb5\x77\xfc\x51\x6e\xa4\x6d\x8e\x2e\xb7\x9f\x87\x7d\xc7\xb5\x58\x71\xed\xfd\x99\xcd\xd8\x77\xea\x5f\x2d\x75\xa1\x92\x24\x32\xc1\x5b\x00\x00\x00\xff\xff\x60\xc5\x22\xd5\x2d\x04\x00\x00")
+var __000002_add_milestoneUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xec\x52\x4d\x6b\xe3\x30\x14\xbc\xeb\x57\x3c\x74\xb2\x16\xb3\xec\x2e\xe4\x64\xb2\xac\x22\xbf\x6c\x0c\xb6\x14\x64\xa5\xed\x2d\x38\x89\x4a\x03\xb6\x93\xda\x32\xb4\xff\xbe\xf8\xab\x6e\x48\xf3\x03\x0a\x3d\x18\xec\x99\xd1\xf3\x9b\x19\x2d\xf0\x7f\x24\x03\x42\x52\x34\xf0\xef\xb0\x93\x59\x61\x61\x0e\x21\x37\x7c\xc1\x53\xf4\x58\xd0\x33\x2e\xdb\xe5\x76\x20\xe9\xba\xc9\x73\x6d\x9f\x1b\x5b\xbb\x9a\x0e\x82\xfd\x29\x6f\x8a\x72\x54\x24\xc7\xdc\xd6\xee\x54\x5a\xd9\x14\x3b\x5b\x5d\x8a\xcc\xeb\xb9\xfd\x07\x8d\xa4\x19\x89\x73\x65\xcf\x59\x65\x0f\xa9\xcb\x9c\x2d\x6c\xe9\x60\x0e\x5e\x8a\x31\x0a\x03\xd1\xd2\x23\x00\xed\x03\x30\x40\x42\x6d\xa4\xf1\x7e\x30\x58\x6a\x95\x40\x24\x97\x4a\x27\xdc\x44\x4a\x6e\x53\xb1\xc2\x84\xff\x14\x2a\xde\x24\x32\xed\xce\xdc\xaf\x50\x63\xf7\x06\xe0\x75\x3e\xb6\x65\xbf\xe6\xe4\x8a\x0d\x3c\x97\xe1\xa8\xa9\xf7\x4f\xb6\xc8\x60\x3e\xa6\x72\x21\xe9\x8d\xbc\xcf\x99\xcc\xb7\x2a\x06\x7f\xe1\x97\x4f\x00\xe8\xb0\xee\x6f\xda\x7e\x09\x25\x05\x37\x1e\xe5\xb1\x41\x0d\x86\x2f\x62\x04\xea\x7f\x58\xc2\x07\x0a\x3c\x0c\x3b\x70\x9a\xd8\xa2\x13\xd2\x66\xe7\x03\x0d\x28\x23\x8c\x05\x64\xad\x71\xcd\x35\x42\x96\x3b\x5b\x45\x8f\xf2\xe4\xf0\xe5\x58\xbb\xba\x0f\xe6\x3a\xd6\x80\xe0\x03\x8a\x8d\xb9\x3e\x11\x10\x12\x22\x8f\x63\x25\xb8\x41\xb8\x35\x77\xbc\x28\x37\xda\x36\x47\x97\xdb\xcf\xcb\xbe\xe3\x5a\xac\xb8\xf6\xfe\xcc\x66\xec\xbb\xf5\xaf\xd6\xba\x50\x49\x12\x99\xe0\x6d\x00\x60\xc5\x22\xd5\x2d\x04\x00\x00")
 
 func _000002_add_milestoneUpSqlBytes() ([]byte, error) {
 	return bindataRead(
@@ -153,7 +167,7 @@ func _000002_add_milestoneUpSql() (*asset, error) {
 	return a, nil
 }
 
-var __000003_drop_spinmint_tableDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x84\xd0\xc1\x4b\xc3\x30\x14\xc7\xf1\x7b\xfe\x8a\xdf\xb1\x05\x0f\xb6\x38\x18\x8c\x1d\xd2\xee\x6d\x3e\x6c\x53\x49\x33\x70\xb7\xa4\x5b\xd4\x1e\x9a\x8d\x9a\xe9\xbf\x2f\x55\x50\xc1\xc1\xee\x9f\xef\x83\xf7\x2b\x68\xc3\x6a\x21\x44\xa9\x49\x1a\x82\x91\x45\x45\xe0\x35\x54\x63\x40\x4f\xdc\x9a\x16\xb6\x3d\xf5\x61\xe8\x43\xb4\x02\x48\x04\x00\x58\x0e\x6f\xd1\x85\xbd\xe7\x83\xc5\xbb\x1b\xf7\xaf\x6e\x4c\xb2\x7c\x9e\x7e\x75\x6a\x5b\x55\x37\xdf\x4e\xfb\xd3\xb1\xf9\x08\x7e\xfc\x65\xf9\x6c\x96\x62\x45\x6b\xb9\xad\xfe\x51\xe5\x06\x7f\x5d\xaa\xf3\xd0\x4d\x17\xfb\x10\x93\x2c\xbb\x48\xca\xd1\xbb\xe8\x0f\x32\x5a\x74\xfd\xcb\x04\xf3\xdb\x4b\xf0\x51\x73\x2d\xf5\x0e\x0f\xb4\x4b\xfe\x3e\x95\x0a\x20\x05\xa9\x0d\x2b\x5a\x72\x08\xc7\x55\xf1\x53\x97\xf7\x52\xb7\x64\x96\xe7\xf8\x3c\x1f\xba\xbb\x69\xbc\xa6\xae\xd9\x2c\x3e\x03\x00\x00\xff\xff\xf7\x0e\xa6\xce\x4c\x01\x00\x00")
+var __000003_drop_spinmint_tableDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x84\xcf\x41\x4b\xc3\x30\x18\xc6\xf1\x7b\x3e\xc5\x73\x6c\xc1\x83\x2d\x0e\x06\x65\x87\xb4\x7b\x37\x5f\x6c\x53\x49\x33\x70\xb7\xa4\x5b\xd4\x1e\x9a\x8d\x9a\xe9\xd7\x97\x29\xa8\xe0\x60\xf7\xdf\xff\x81\xa7\xa4\x35\xab\x42\x88\x4a\x93\x34\x04\x23\xcb\x9a\xc0\x2b\xa8\xd6\x80\x9e\xb8\x33\x1d\x6c\x77\x1c\xc2\x38\x84\x68\x05\x90\x08\x00\xb0\x1c\xde\xa2\x0b\x3b\xcf\x7b\x8b\x77\x37\xed\x5e\xdd\x94\x64\xf9\x3c\xfd\xea\xd4\xa6\xae\x6f\xbe\x9d\xf6\xc7\x43\xfb\x11\xfc\xf4\xcb\xf2\xd9\x2c\xc5\x92\x56\x72\x53\xff\xa3\xca\x8d\xfe\xba\x54\xa7\xb1\x3f\x2f\x0e\x21\x26\x59\x76\x91\x54\x93\x77\xd1\xef\x65\xb4\xe8\x87\x97\x33\xcc\x6f\x2f\xc1\x47\xcd\x8d\xd4\x5b\x3c\xd0\x36\xf9\x7b\x2a\x15\x40\x0a\x52\x6b\x56\xb4\xe0\x10\x0e\xcb\xf2\xa7\xae\xee\xa5\xee\xc8\x2c\x4e\xf1\x79\x3e\xf6\x77\x85\x10\x55\xdb\x34\x6c\x8a\xcf\x01\x00\xf7\x0e\xa6\xce\x4c\x01\x00\x00")
 
 func _000003_drop_spinmint_tableDownSqlBytes() ([]byte, error) {
 	return bindataRead(
@@ -173,7 +187,7 @@ func _000003_drop_spinmint_tableDownSql() (*asset, error) {
 	return a, nil
 }
 
-var __000003_drop_spinmint_tableUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x72\x72\x75\xf7\xf4\xb3\xe6\xe2\x72\x09\xf2\x0f\x50\x08\x71\x74\xf2\x71\x55\xf0\x74\x53\x70\x8d\xf0\x0c\x0e\x09\x56\x48\x08\x2e\xc8\xcc\xcb\xcd\xcc\x2b\x49\xb0\xe6\xe2\x72\xf6\xf7\xf5\xf5\x0c\xb1\x06\x04\x00\x00\xff\xff\xe6\x87\xad\xaf\x31\x00\x00\x00")
+var __000003_drop_spinmint_tableUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x00\x31\x00\xce\xff\x42\x45\x47\x49\x4e\x3b\x0a\x0a\x44\x52\x4f\x50\x20\x54\x41\x42\x4c\x45\x20\x49\x46\x20\x45\x58\x49\x53\x54\x53\x20\x60\x53\x70\x69\x6e\x6d\x69\x6e\x74\x60\x3b\x0a\x0a\x43\x4f\x4d\x4d\x49\x54\x3b\x03\x00\xe6\x87\xad\xaf\x31\x00\x00\x00")
 
 func _000003_drop_spinmint_tableUpSqlBytes() ([]byte, error) {
 	return bindataRead(
@@ -193,6 +207,286 @@ func _000003_drop_spinmint_tableUpSql() (*asset, error) {
 	return a, nil
 }
 
+var __000004_create_webhook_deliveriesDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x00\x3b\x00\xc4\xff\x42\x45\x47\x49\x4e\x3b\x0a\x0a\x44\x52\x4f\x50\x20\x54\x41\x42\x4c\x45\x20\x49\x46\x20\x45\x58\x49\x53\x54\x53\x20\x60\x57\x65\x62\x68\x6f\x6f\x6b\x44\x65\x6c\x69\x76\x65\x72\x69\x65\x73\x60\x3b\x0a\x0a\x43\x4f\x4d\x4d\x49\x54\x3b\x0a\x03\x00\x41\x78\x02\x12\x3b\x00\x00\x00")
+
+func _000004_create_webhook_deliveriesDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__000004_create_webhook_deliveriesDownSql,
+		"000004_create_webhook_deliveries.down.sql",
+	)
+}
+
+func _000004_create_webhook_deliveriesDownSql() (*asset, error) {
+	bytes, err := _000004_create_webhook_deliveriesDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "000004_create_webhook_deliveries.down.sql", size: 0, mode: os.FileMode(0644), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x1f, 0x96, 0x32, 0xb4, 0xc9, 0x3f, 0xe1, 0xd0, 0xd4, 0x8a, 0xac, 0xe6, 0xfc, 0x75, 0xc3, 0xa3, 0x34, 0xc7, 0x76, 0x1e, 0x30, 0x68, 0x9e, 0xbf, 0x48, 0x90, 0x1c, 0x67, 0xeb, 0xa7, 0xc7, 0x10}}
+	return a, nil
+}
+
+var __000004_create_webhook_deliveriesUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x84\x91\xc1\x6e\x82\x40\x10\x86\xef\xfb\x14\x73\x84\xc4\x83\x36\xc6\x34\x31\x1e\x56\x58\xed\x46\x44\x03\x6b\x5a\x4f\xee\x52\xa6\x95\x54\xc0\x2c\xa3\xc5\xb7\x6f\x2c\xc4\x1a\x9b\xda\xeb\xee\xf7\xfd\x99\xf9\x67\x2c\xa6\x32\x1c\x32\xe6\x45\x82\x2b\x01\x8a\x8f\x03\x01\x72\x02\xe1\x42\x81\x78\x91\xb1\x8a\x41\x3f\x63\xb2\x2d\xcb\x0f\x1f\x77\xd9\x11\x6d\x86\x95\x66\x00\x0e\x03\x00\xd0\xed\xe3\x49\xfa\x1a\x8e\xc6\xbe\x6e\x8d\x75\x06\x7d\xf7\xdb\x0f\x57\x41\xd0\x69\x30\x71\xc4\x82\xd4\x69\x8f\x77\xa9\xa5\x39\xed\x4a\x93\x6a\xc8\x31\xcd\x0e\x79\xb2\x2b\x93\x5b\x24\x26\x43\x57\x21\xbd\xc1\xaf\x90\xc0\x54\x24\xac\x2d\xad\x06\xc2\x9a\x6e\xbf\x39\x11\xe6\x7b\xaa\x34\x64\x05\x39\xbd\xde\x8f\x0f\xbe\x98\xf0\x55\xa0\xa0\xdb\xa2\x21\xd6\xd4\xe2\x9c\x34\x24\xd9\xfb\x59\x79\xe8\xde\x51\x3c\x8b\x86\x30\xfd\x03\x6f\xa1\xd5\x3e\xfd\x17\x5a\x46\x72\xce\xa3\x35\xcc\xc4\xda\xb9\x2e\xd9\x6d\x32\x66\x62\x0d\x3a\x4b\xeb\xcd\x67\x73\x9b\xf4\x72\x9b\x4d\x75\x6e\x68\x53\x60\x4d\xa6\x99\xdd\x90\x06\xa7\x2d\xae\x73\xbb\x95\xcb\x00\x5c\x10\xe1\x54\x86\x62\x24\x8b\xa2\xf4\xc7\x97\xa5\xbc\x27\x1e\xc5\x42\x8d\x0e\xf4\xf6\x98\x27\xfd\x21\x63\xde\x62\x3e\x97\x6a\xc8\xbe\x06\x00\xd6\x51\x85\x14\x36\x02\x00\x00")
+
+func _000004_create_webhook_deliveriesUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__000004_create_webhook_deliveriesUpSql,
+		"000004_create_webhook_deliveries.up.sql",
+	)
+}
+
+func _000004_create_webhook_deliveriesUpSql() (*asset, error) {
+	bytes, err := _000004_create_webhook_deliveriesUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "000004_create_webhook_deliveries.up.sql", size: 0, mode: os.FileMode(0644), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x97, 0xc5, 0x73, 0xc2, 0xb6, 0x75, 0xfd, 0x18, 0xad, 0x4a, 0xd7, 0xbb, 0x54, 0x76, 0xbe, 0x11, 0x5b, 0x5, 0x3d, 0x4d, 0x41, 0xc3, 0xb, 0x1e, 0x5, 0xb0, 0x47, 0xfe, 0x7, 0xe6, 0x51, 0xf6}}
+	return a, nil
+}
+
+var __000005_add_pull_requests_sha_indexDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x74\x90\x51\x6b\xbb\x30\x14\xc5\xdf\xf3\x29\x2e\x79\x32\x7f\xe4\xcf\xf6\x1c\x3a\x96\xc6\xdb\x35\x50\x13\x49\x52\xd6\x37\xb1\x33\xa3\x05\xdb\x39\xb5\xd0\x8f\x3f\xb4\xea\x36\xb6\x3d\x08\x72\xef\xef\xdc\x9c\x73\x96\xf8\xa4\x34\x27\xc4\xa1\x87\xc7\x72\xaf\x8b\x53\x80\x05\x24\xc2\x8b\xa5\x70\x18\x31\x7e\xdb\x74\xc5\xbe\x0a\xe3\x92\x66\x97\xaa\xb2\xe1\xfd\x12\xda\xae\xa5\x23\x70\x3c\x97\xe1\x3a\x01\xc7\xf2\x9a\xd7\x97\xaa\x6a\x46\x28\x6f\x0f\xc5\x04\xd6\x4d\xa8\x8b\x26\x94\xae\x2b\xba\x70\x0a\xe7\x0e\x16\x10\x39\xdc\xa0\xf4\xa0\x56\x11\x01\xe8\x3f\x80\x71\x24\xcd\x56\xfb\xe8\x1f\x83\x95\x35\x29\x28\xbd\x32\x36\x15\x5e\x19\x9d\x3b\xb9\xc6\x54\xfc\x77\x5e\x78\xe5\xbc\x92\x6e\x90\x3d\xaf\xd1\xe2\xf0\x07\x10\x0d\xae\xf3\xf3\xcd\xd5\x67\x06\x36\xee\x85\x4e\x26\xa6\x7d\x39\x84\x53\x01\x8b\xa9\x83\x6f\xc8\x90\x6d\x3e\x33\x27\xed\x19\x06\x0f\x70\x17\x13\x00\x69\xb4\x14\x3e\xa2\x89\x35\x19\x28\x9d\xe0\x0e\x68\xfc\x05\x8e\x81\x82\xd1\xc3\x6c\xf6\x11\x03\xe5\x94\xf5\x6a\x3a\xa6\xbd\xa7\x84\x31\x4e\x32\x8b\x99\xb0\x08\x65\xf3\x56\xab\xfe\x39\xf5\x8a\xd7\x63\xdb\xb5\xb7\x16\x7e\x76\xc8\x09\xee\x50\x6e\xfd\x2f\x12\x4e\x48\x82\x62\xb3\x31\x52\x78\x84\x3f\x2f\x73\x22\x4d\x9a\x2a\xcf\xc9\xc7\x00\x06\x1e\x40\x7d\x12\x02\x00\x00")
+
+func _000005_add_pull_requests_sha_indexDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__000005_add_pull_requests_sha_indexDownSql,
+		"000005_add_pull_requests_sha_index.down.sql",
+	)
+}
+
+func _000005_add_pull_requests_sha_indexDownSql() (*asset, error) {
+	bytes, err := _000005_add_pull_requests_sha_indexDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "000005_add_pull_requests_sha_index.down.sql", size: 0, mode: os.FileMode(0644), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x3, 0xf4, 0x2d, 0x9d, 0x9, 0x63, 0x75, 0x6d, 0x7, 0xbd, 0xc0, 0xcb, 0xc8, 0x96, 0xac, 0x7, 0xb9, 0xe3, 0xb6, 0x3, 0x3b, 0xe2, 0x76, 0x50, 0xc2, 0xaf, 0xd0, 0xe7, 0x39, 0xb5, 0xde, 0xe5}}
+	return a, nil
+}
+
+var __000005_add_pull_requests_sha_indexUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x7c\x90\xc1\x8e\xdb\x20\x10\x40\xef\x7c\xc5\x88\x93\xa9\xac\xaa\x3d\xa3\x54\x65\xf1\xa4\x8b\x14\x43\x04\xac\xba\x37\x8b\x8d\xa9\x12\xc9\x71\x5c\x9b\xa8\xf9\xfc\x0a\xc7\x4e\x5b\x55\xed\x01\x69\x98\x79\x03\x6f\xe6\x09\xbf\x28\xcd\x09\x71\xe8\xe1\x73\xfb\xa6\xc3\x39\xc2\x06\x2a\xe1\xc5\x93\x70\x58\x30\x7e\xaf\xa4\xf0\xd6\xc5\xa5\x48\xf7\xd7\xae\xb3\xf1\xfb\x35\x4e\x69\xa2\x0b\x70\xea\xdb\x78\x5b\x81\x53\x7b\x6b\x86\x6b\xd7\x8d\x0b\xd4\x4c\xc7\xb0\x82\xc3\x18\x87\x30\xc6\xd6\xa5\x90\xe2\x39\xf6\x09\x36\x50\x38\xdc\xa1\xf4\xa0\xb6\x05\x01\xc8\x07\x60\x49\x49\xf3\xa2\x7d\xf1\x8e\xc1\xd6\x9a\x1a\x94\xde\x1a\x5b\x0b\xaf\x8c\x6e\x9c\x7c\xc6\x5a\xbc\x77\x5e\x78\xe5\xbc\x92\x6e\x6e\xfb\xfa\x8c\x16\xe7\x08\xa0\x98\xad\x9b\xfe\x6e\xf5\x6b\x06\xb6\xd4\x85\xae\x56\x66\x3a\x1c\xe3\x39\xc0\x66\xdd\xc1\x1f\xc8\x3c\xdb\xe3\x99\xc7\xa4\x99\x61\xf0\x09\x3e\x94\x04\x80\x2e\xbe\x1f\x69\xbe\x49\xa3\xa5\xf0\x05\x95\x16\x85\x47\x50\xba\xc2\x57\xa0\xe5\x6f\xcd\x25\x50\x30\x7a\xce\x3d\xbc\x72\xae\xb0\x71\xb8\x98\x1f\x7d\x1c\x4b\xc8\x61\x96\x29\xc1\x1d\x03\xe3\x94\x11\xc6\x38\xd9\x5b\xdc\x0b\x8b\x70\x18\x63\x48\x51\x65\x1b\xf5\x4d\x5f\x12\xde\x4e\x53\x9a\xee\x7b\xfa\x7b\xcb\x9c\xe0\x2b\xca\x17\xff\xaf\x3e\x4e\x48\x85\x62\xb7\x33\x32\x0b\xff\xff\x0f\x4e\xa4\xa9\x6b\xe5\x39\xf9\x39\x00\xb4\xbd\xb3\x4d\x3e\x02\x00\x00")
+
+func _000005_add_pull_requests_sha_indexUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__000005_add_pull_requests_sha_indexUpSql,
+		"000005_add_pull_requests_sha_index.up.sql",
+	)
+}
+
+func _000005_add_pull_requests_sha_indexUpSql() (*asset, error) {
+	bytes, err := _000005_add_pull_requests_sha_indexUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "000005_add_pull_requests_sha_index.up.sql", size: 0, mode: os.FileMode(0644), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xda, 0xa8, 0xf4, 0xa, 0x67, 0x2, 0x75, 0x34, 0x11, 0x12, 0xd, 0x58, 0xba, 0xcc, 0xc5, 0xbd, 0xf9, 0x5f, 0xbc, 0x28, 0x8b, 0xec, 0x7b, 0x4d, 0xbd, 0xb0, 0x11, 0x21, 0x1, 0xce, 0xbc, 0xb1}}
+	return a, nil
+}
+
+var __000006_create_pending_mergesDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x00\x37\x00\xc8\xff\x42\x45\x47\x49\x4e\x3b\x0a\x0a\x44\x52\x4f\x50\x20\x54\x41\x42\x4c\x45\x20\x49\x46\x20\x45\x58\x49\x53\x54\x53\x20\x60\x50\x65\x6e\x64\x69\x6e\x67\x4d\x65\x72\x67\x65\x73\x60\x3b\x0a\x0a\x43\x4f\x4d\x4d\x49\x54\x3b\x0a\x03\x00\x1b\xbc\xf9\x55\x37\x00\x00\x00")
+
+func _000006_create_pending_mergesDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__000006_create_pending_mergesDownSql,
+		"000006_create_pending_merges.down.sql",
+	)
+}
+
+func _000006_create_pending_mergesDownSql() (*asset, error) {
+	bytes, err := _000006_create_pending_mergesDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "000006_create_pending_merges.down.sql", size: 0, mode: os.FileMode(0644), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x0, 0x2f, 0x19, 0x74, 0xdb, 0x6, 0x65, 0xc5, 0xb, 0x5, 0xbf, 0x92, 0x11, 0xbd, 0x1, 0xe5, 0xb5, 0x70, 0xde, 0x9b, 0xcc, 0xd9, 0xaa, 0x9a, 0x48, 0xb2, 0x19, 0x94, 0xae, 0x81, 0x83, 0x7d}}
+	return a, nil
+}
+
+var __000006_create_pending_mergesUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x7c\x8f\x31\x6f\x83\x30\x10\x85\x77\xff\x8a\x1b\x41\x62\x28\x51\x55\x45\x42\x19\x0c\xb9\xa4\x56\xc1\x44\xc6\x91\x9a\xcd\x50\xae\x84\x01\xd3\x3a\xa6\x55\xff\x7d\x95\x0c\x2d\x4a\xa4\xcc\xf7\xdd\xfb\xde\x4b\x71\x2b\x64\xc2\x58\xa6\x90\x6b\x04\xcd\xd3\x1c\x41\x6c\x40\x96\x1a\xf0\x55\x54\xba\x02\xb3\x23\xdb\xf6\xb6\x2b\xc8\x75\x74\x32\x0c\x20\x60\x00\x00\x46\xd1\xc7\x58\x7e\x5b\x72\x06\xbe\x6a\xf7\x76\xac\x5d\x10\x2f\x96\xe1\xe5\x57\xee\xf3\x3c\xfa\xc7\x64\x3d\xd0\x7d\x4a\x4e\x43\x73\x4e\xea\xad\x0f\xe2\xf8\xe6\x7c\x91\x17\xe4\x8f\x63\x3b\xcb\x79\xba\xe1\x14\x7d\x4e\x74\xf2\xd4\xa6\x3f\xf7\x7d\x99\xa3\xda\x53\xcb\xbd\x81\xa6\xef\xce\xd6\xc5\xc3\x35\xb4\x53\xa2\xe0\xea\x00\x2f\x78\x08\x66\x6b\xa3\xd9\xa6\xe8\xaf\x79\xc8\x00\x42\x40\xb9\x15\x12\x57\xc2\xda\x71\x9d\xc2\x1a\x37\x7c\x9f\x6b\xc8\x9e\xb9\xaa\x50\xaf\x26\xff\xbe\x1c\x9a\xc7\x84\xb1\xac\x2c\x0a\xa1\x13\xf6\x3b\x00\xf8\xc2\x54\x29\x80\x01\x00\x00")
+
+func _000006_create_pending_mergesUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__000006_create_pending_mergesUpSql,
+		"000006_create_pending_merges.up.sql",
+	)
+}
+
+func _000006_create_pending_mergesUpSql() (*asset, error) {
+	bytes, err := _000006_create_pending_mergesUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "000006_create_pending_merges.up.sql", size: 0, mode: os.FileMode(0644), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xd2, 0x8a, 0x73, 0xd6, 0x12, 0x39, 0x22, 0x76, 0x4d, 0x64, 0x82, 0x8f, 0xf3, 0x55, 0x59, 0xe3, 0x63, 0x3b, 0x9, 0x95, 0x1d, 0xf3, 0xae, 0xcd, 0xcc, 0xb2, 0x4b, 0x3f, 0x9e, 0x45, 0x3, 0xea}}
+	return a, nil
+}
+
+var __000007_create_holdsDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x00\x2f\x00\xd0\xff\x42\x45\x47\x49\x4e\x3b\x0a\x0a\x44\x52\x4f\x50\x20\x54\x41\x42\x4c\x45\x20\x49\x46\x20\x45\x58\x49\x53\x54\x53\x20\x60\x48\x6f\x6c\x64\x73\x60\x3b\x0a\x0a\x43\x4f\x4d\x4d\x49\x54\x3b\x0a\x03\x00\xb7\x23\x62\x2a\x2f\x00\x00\x00")
+
+func _000007_create_holdsDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__000007_create_holdsDownSql,
+		"000007_create_holds.down.sql",
+	)
+}
+
+func _000007_create_holdsDownSql() (*asset, error) {
+	bytes, err := _000007_create_holdsDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "000007_create_holds.down.sql", size: 0, mode: os.FileMode(0644), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xaf, 0xff, 0xbb, 0x64, 0xed, 0xca, 0x57, 0x8, 0x2d, 0x75, 0x9f, 0x73, 0x9b, 0xb0, 0x72, 0x26, 0x3d, 0xb9, 0xe6, 0x66, 0xc6, 0x6e, 0xa0, 0xe1, 0x96, 0x7c, 0x40, 0x64, 0x3f, 0xf4, 0x55, 0xb2}}
+	return a, nil
+}
+
+var __000007_create_holdsUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x84\x8f\xcf\x4b\xc3\x30\x1c\xc5\xef\xf9\x2b\xde\xb1\x85\x1e\xec\xf0\x30\x28\x3b\xa4\xdd\x77\x5b\xb0\x4d\x25\xcd\xc0\xdd\x92\xba\xa8\x03\x9b\x4a\x96\xa9\x7f\xbe\xd4\xc3\x1c\x13\xf4\xfc\x7e\x7c\xde\x2b\x69\x2d\x64\xc1\x58\xa5\x88\x6b\x82\xe6\x65\x4d\x10\x2b\xc8\x56\x83\x1e\x44\xa7\x3b\x98\xcd\xf8\xba\x3f\x1a\x06\x24\x0c\x00\x8c\x72\x6f\x63\xfb\xe1\x5d\x30\x78\xb7\xe1\xf1\xc5\x86\x24\x9f\xcd\xd3\xef\x8c\xdc\xd6\x75\xf6\x63\x93\x76\x70\x7f\xbb\xe4\x69\xe8\xa7\xa6\x83\x8f\x49\x9e\xff\x92\x27\xf6\xff\x20\x7b\x1c\xbd\x41\x74\x9f\xf1\x5a\xab\x82\xb3\xd1\xed\x79\x34\xe8\x0f\xcf\x13\x64\x76\x73\x5d\x70\xaf\x44\xc3\xd5\x0e\x77\xb4\x4b\x2e\xce\x65\x17\x17\xb2\xf3\xd0\xec\xbc\x29\x65\x40\x0a\x92\x6b\x21\x69\x21\xbc\x1f\x97\x25\x96\xb4\xe2\xdb\x5a\xa3\xda\x70\xd5\x91\x5e\x9c\xe2\xd3\x7c\xe8\x6f\x0b\xc6\xaa\xb6\x69\x84\x2e\xd8\xd7\x00\x3a\x1d\xc4\xc2\x71\x01\x00\x00")
+
+func _000007_create_holdsUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__000007_create_holdsUpSql,
+		"000007_create_holds.up.sql",
+	)
+}
+
+func _000007_create_holdsUpSql() (*asset, error) {
+	bytes, err := _000007_create_holdsUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "000007_create_holds.up.sql", size: 0, mode: os.FileMode(0644), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x72, 0x99, 0x93, 0xe0, 0x94, 0x2f, 0x5, 0x4e, 0xf8, 0xf5, 0xcc, 0xd5, 0x9, 0xf6, 0x2d, 0xe8, 0x58, 0x1e, 0x5e, 0xdf, 0xe4, 0x36, 0xf5, 0xd6, 0xd6, 0x66, 0x32, 0xeb, 0x78, 0x34, 0x35, 0x91}}
+	return a, nil
+}
+
+var __000008_create_cherry_pick_jobsDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x00\x38\x00\xc7\xff\x42\x45\x47\x49\x4e\x3b\x0a\x0a\x44\x52\x4f\x50\x20\x54\x41\x42\x4c\x45\x20\x49\x46\x20\x45\x58\x49\x53\x54\x53\x20\x60\x43\x68\x65\x72\x72\x79\x50\x69\x63\x6b\x4a\x6f\x62\x73\x60\x3b\x0a\x0a\x43\x4f\x4d\x4d\x49\x54\x3b\x0a\x03\x00\x8f\x50\x91\x1c\x38\x00\x00\x00")
+
+func _000008_create_cherry_pick_jobsDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__000008_create_cherry_pick_jobsDownSql,
+		"000008_create_cherry_pick_jobs.down.sql",
+	)
+}
+
+func _000008_create_cherry_pick_jobsDownSql() (*asset, error) {
+	bytes, err := _000008_create_cherry_pick_jobsDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "000008_create_cherry_pick_jobs.down.sql", size: 0, mode: os.FileMode(0644), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x41, 0xc8, 0xbf, 0x2f, 0x80, 0xb9, 0x44, 0xcf, 0x43, 0xe0, 0x8f, 0xab, 0xf3, 0xc6, 0xdb, 0x48, 0x1, 0x63, 0x4e, 0x3a, 0xe9, 0x10, 0xf2, 0x85, 0x55, 0x98, 0xc7, 0x82, 0xe, 0x64, 0x36, 0x23}}
+	return a, nil
+}
+
+var __000008_create_cherry_pick_jobsUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x8c\x92\x5f\x6f\xda\x30\x14\xc5\xdf\xf3\x29\xee\x5b\x83\xc4\xa4\xc2\xda\xa9\x12\xea\x83\x09\x6e\xeb\x41\x1c\x94\x18\x69\x3c\x91\x7f\x6e\xf1\x5a\x3b\x91\x73\xb3\xb2\x6f\x3f\xa5\x44\x24\x5a\xe8\xd8\xb3\x7f\xe7\x9e\xeb\x7b\xce\x9c\x3e\x32\x3e\x73\x1c\x2f\xa4\x44\x50\x10\x64\xbe\xa2\xc0\x1e\x80\x07\x02\xe8\x0f\x16\x89\x08\x62\x6f\x2f\xad\xfd\xbd\x56\xd9\xeb\xf7\x22\xad\x62\x07\xc0\x75\x00\x00\x62\xb6\x88\x21\x55\x2f\xca\xa0\x3b\xbd\x1e\x7d\x68\xf8\x66\xb5\x02\xb2\x11\xc1\x8e\x71\x2f\xa4\x3e\xe5\x62\x7c\x84\x43\x59\x16\xc1\xbb\x91\x36\x86\x5f\x89\xcd\xf6\x89\x75\x27\xd3\xbb\x4e\xd5\xc3\x78\xa2\xe5\xbf\x29\x5e\xeb\xb4\x99\xd4\x58\x4f\x26\x83\xe7\xb9\x4d\x4c\xb6\xef\x46\x4c\x6f\x6f\x07\x8c\x2f\xed\x8b\xf4\x0a\xad\x15\x46\x4f\xa4\x63\x6f\x86\x6e\xbe\x7a\x93\x15\x16\x46\x0e\x0d\x61\x41\x1f\xc8\x66\x25\xe0\xba\x65\x97\xca\xe4\xdd\xb0\xaf\xd3\x33\xe8\x55\xf6\x71\xd0\x2f\xa5\xca\x5e\xaf\x5a\x59\x84\x09\xf6\xff\xfc\xad\xd3\xb5\x04\x41\x94\xba\xc4\xea\x3f\x76\xe0\xf2\x80\x2d\x4e\xf0\x7c\x44\x7f\x4b\x82\x1a\xcb\x1a\x63\xd0\x32\x57\xb5\x46\x79\xc0\x13\xdb\x12\x5e\x61\x9e\xdf\x54\xd6\x2c\x70\xee\x99\xcb\xf7\x75\xf8\x59\x2c\x03\x3b\xcf\xca\x04\x65\xfe\xc9\x76\x2d\xb4\x29\xf3\x8b\xd0\x3a\x64\x3e\x09\xb7\xb0\xa4\x5b\xb7\xe9\xe3\xe8\xa8\x5d\xd2\x2d\xc4\x2a\x3f\xec\x8e\xa7\x6e\x2e\xfd\xb3\x48\xab\x5d\x69\x63\x70\x7b\x55\x1c\xf7\x0a\x37\x3e\xd5\xea\xc2\x90\xea\x98\x95\xdb\x86\x36\x72\x00\x46\x40\xf9\x23\xe3\xf4\x9e\x19\x53\x2c\xe6\xa7\xff\x7a\x4f\x24\x8c\xa8\xb8\xaf\xf1\xf9\x4e\xa7\x37\x33\xc7\xf1\x02\xdf\x67\x62\xe6\xfc\x19\x00\x3c\x42\x95\x18\x79\x03\x00\x00")
+
+func _000008_create_cherry_pick_jobsUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__000008_create_cherry_pick_jobsUpSql,
+		"000008_create_cherry_pick_jobs.up.sql",
+	)
+}
+
+func _000008_create_cherry_pick_jobsUpSql() (*asset, error) {
+	bytes, err := _000008_create_cherry_pick_jobsUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "000008_create_cherry_pick_jobs.up.sql", size: 0, mode: os.FileMode(0644), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x77, 0x14, 0x2e, 0x72, 0xc3, 0xf2, 0x1f, 0x3c, 0x45, 0xc8, 0xf7, 0x8d, 0xaa, 0x22, 0x1f, 0x33, 0x76, 0x7d, 0x4a, 0x78, 0x90, 0x27, 0x5c, 0xa9, 0x3c, 0x4e, 0x7c, 0x11, 0x1a, 0x8a, 0x1, 0x39}}
+	return a, nil
+}
+
+var __000009_create_backportsDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x00\x33\x00\xcc\xff\x42\x45\x47\x49\x4e\x3b\x0a\x0a\x44\x52\x4f\x50\x20\x54\x41\x42\x4c\x45\x20\x49\x46\x20\x45\x58\x49\x53\x54\x53\x20\x60\x42\x61\x63\x6b\x70\x6f\x72\x74\x73\x60\x3b\x0a\x0a\x43\x4f\x4d\x4d\x49\x54\x3b\x0a\x03\x00\x50\x53\x66\x72\x33\x00\x00\x00")
+
+func _000009_create_backportsDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__000009_create_backportsDownSql,
+		"000009_create_backports.down.sql",
+	)
+}
+
+func _000009_create_backportsDownSql() (*asset, error) {
+	bytes, err := _000009_create_backportsDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "000009_create_backports.down.sql", size: 0, mode: os.FileMode(0644), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x1a, 0x41, 0xa7, 0x55, 0x0, 0x9a, 0x9e, 0xe5, 0xaf, 0xae, 0x49, 0x27, 0x19, 0x9f, 0x9c, 0x15, 0xdc, 0x3d, 0xc9, 0xd4, 0xef, 0xde, 0xca, 0xe9, 0x96, 0x65, 0x89, 0xb2, 0x37, 0xe6, 0x63, 0x54}}
+	return a, nil
+}
+
+var __000009_create_backportsUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x84\x91\x41\x6f\x82\x30\x14\x80\xef\xfd\x15\xef\x26\x24\x1c\xd4\xcc\xc5\xc4\x78\x28\x58\x5d\x23\xd4\x05\x6a\x32\x4f\x6b\xd1\x6e\x92\x8d\x42\x6a\xdd\xf6\xf3\x17\x37\x04\x32\x82\xbb\x91\xf0\xbd\xef\xe5\x7d\xf5\xc9\x8a\xb2\x19\x42\x41\x4c\x30\x27\xc0\xb1\x1f\x12\xa0\x4b\x60\x1b\x0e\xe4\x89\x26\x3c\x01\xe1\xcb\xfd\x5b\x59\x18\x7b\x12\x08\xc0\x41\x00\x00\x22\x56\x65\xb1\xf9\xd4\xca\x08\xf8\x90\x66\x7f\x94\xc6\x19\x8d\xa7\xee\xcf\x1c\xdb\x86\xa1\xd7\x60\x4c\xe6\xea\x36\xc5\xce\x79\x7a\x31\x65\xda\x3a\xa3\x51\xe7\xb7\x6f\xa4\xde\x1f\x1b\xc5\x78\x32\xe9\x30\x51\xf6\xae\x4e\xb6\xd0\xaa\x07\x83\x05\x59\xe2\x6d\xc8\x61\x30\xb8\x5a\xab\xab\xfa\x96\xd7\x13\xc3\x6a\x20\xb1\xd2\xb6\x0f\xb9\x6f\xd8\x8a\x08\x8c\x92\x56\x1d\xb0\x15\x90\x66\xaf\x17\xe1\x78\xd8\x81\xb6\xe5\xe1\x5f\xe8\x31\xa6\x11\x8e\x77\xb0\x26\x3b\xa7\x95\xda\x6b\x05\xf5\xea\x6c\x5e\x5d\xc8\xfd\x5d\xb1\x26\x3b\x10\xd9\xe1\xeb\x39\xbd\x3e\x5c\xfd\x25\xe0\x86\xef\x4f\x91\x7e\x5b\xde\xc4\x76\x5a\xe5\x5d\x04\xe0\x02\x61\x2b\xca\xc8\x9c\x6a\x5d\x2c\xfc\x3a\x62\xf0\x80\xe3\x84\xf0\xf9\xd9\xbe\x4c\xf3\xf4\x6e\x86\x50\xb0\x89\x22\xca\x67\xe8\x7b\x00\x20\xe8\x12\x93\x82\x02\x00\x00")
+
+func _000009_create_backportsUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__000009_create_backportsUpSql,
+		"000009_create_backports.up.sql",
+	)
+}
+
+func _000009_create_backportsUpSql() (*asset, error) {
+	bytes, err := _000009_create_backportsUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "000009_create_backports.up.sql", size: 0, mode: os.FileMode(0644), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xa8, 0x66, 0x2e, 0x11, 0xab, 0xc3, 0xc6, 0x89, 0x10, 0x90, 0xf8, 0x9, 0xd8, 0xf7, 0x25, 0xd2, 0x30, 0xf5, 0x7c, 0x5d, 0x24, 0xa5, 0xa3, 0x8b, 0xd3, 0x88, 0x58, 0x7c, 0x0, 0xa5, 0x39, 0xe6}}
+	return a, nil
+}
+
+var __000010_create_merge_queueDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x00\x34\x00\xcb\xff\x42\x45\x47\x49\x4e\x3b\x0a\x0a\x44\x52\x4f\x50\x20\x54\x41\x42\x4c\x45\x20\x49\x46\x20\x45\x58\x49\x53\x54\x53\x20\x60\x4d\x65\x72\x67\x65\x51\x75\x65\x75\x65\x60\x3b\x0a\x0a\x43\x4f\x4d\x4d\x49\x54\x3b\x0a\x03\x00\x9b\xfe\xee\x61\x34\x00\x00\x00")
+
+func _000010_create_merge_queueDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__000010_create_merge_queueDownSql,
+		"000010_create_merge_queue.down.sql",
+	)
+}
+
+func _000010_create_merge_queueDownSql() (*asset, error) {
+	bytes, err := _000010_create_merge_queueDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "000010_create_merge_queue.down.sql", size: 0, mode: os.FileMode(0644), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x6c, 0xc0, 0x4e, 0x1d, 0xdd, 0xcc, 0xec, 0x26, 0x66, 0x78, 0x0, 0x92, 0x13, 0xde, 0x12, 0x5d, 0xa5, 0x31, 0x63, 0x9b, 0x79, 0x37, 0xa2, 0x85, 0x38, 0x64, 0x68, 0xcd, 0xbe, 0x6f, 0xe, 0x20}}
+	return a, nil
+}
+
+var __000010_create_merge_queueUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x7c\x8f\x41\x4f\x83\x40\x14\x84\xef\xfb\x2b\xe6\x08\x49\x0f\xd2\x78\x68\x42\x7a\x58\xe8\x6b\xdd\x14\x16\x85\x6d\x22\x27\x76\x69\xd7\xca\x01\xaa\x9b\x45\xfd\xf9\x06\x9b\x28\xd1\xc4\xf3\xfb\xe6\x7d\x33\x09\xed\x84\x8c\x19\x4b\x4b\xe2\x8a\xa0\x78\x92\x11\xc4\x16\xb2\x50\xa0\x47\x51\xa9\x0a\x3a\xb7\xee\x6c\x1f\x46\x3b\x5a\xcd\x80\x80\x01\x80\x2e\xed\xcb\xa5\x78\x1f\xac\xd3\x78\x33\xee\xf8\x6c\x5c\x10\x2d\x57\xe1\x57\x50\x1e\xb2\x6c\xf1\x83\x49\xd3\xdb\xff\x29\x39\xf6\xed\xf4\xa9\x1b\x7c\x10\x45\x7f\xce\xa9\xb3\xc6\xdb\x13\xf7\x1a\x6d\x77\x9e\xa0\xe5\xcd\x6f\xe8\xbe\x14\x39\x2f\x6b\xec\xa9\x0e\x66\xe5\x16\xb3\x0a\x8b\x6f\x51\x78\xcd\xec\xa9\x86\xee\x4e\x1f\x4d\x3f\x2d\x6c\x5e\xa7\x89\xcd\xf1\x2a\x6b\x8c\xd7\x08\x66\xea\x90\x01\x21\x48\xee\x84\xa4\xb5\x18\x86\xcb\x26\xc1\x86\xb6\xfc\x90\x29\xa4\x77\xbc\xac\x48\xad\x47\xff\xb4\xea\xdb\xdb\x98\xb1\xb4\xc8\x73\xa1\x62\xf6\x39\x00\xfc\x8d\x0b\x43\x60\x01\x00\x00")
+
+func _000010_create_merge_queueUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__000010_create_merge_queueUpSql,
+		"000010_create_merge_queue.up.sql",
+	)
+}
+
+func _000010_create_merge_queueUpSql() (*asset, error) {
+	bytes, err := _000010_create_merge_queueUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "000010_create_merge_queue.up.sql", size: 0, mode: os.FileMode(0644), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x23, 0x72, 0xe, 0x21, 0xd2, 0x32, 0x8f, 0x33, 0x83, 0xb, 0x3d, 0x5f, 0xb4, 0xbc, 0x3f, 0x18, 0xbb, 0xbe, 0x52, 0x8d, 0x3b, 0x52, 0xff, 0x5e, 0x28, 0x7a, 0x3a, 0x9b, 0xf5, 0xc7, 0x91, 0x2b}}
+	return a, nil
+}
+
 // Asset loads and returns the asset for the given name.
 // It returns an error if the asset could not be found or
 // could not be loaded.
@@ -284,12 +578,26 @@ func AssetNames() []string {
 
 // _bindata is a table, holding each asset generator, mapped to its name.
 var _bindata = map[string]func() (*asset, error){
-	"000001_base.down.sql":                _000001_baseDownSql,
-	"000001_base.up.sql":                  _000001_baseUpSql,
-	"000002_add_milestone.down.sql":       _000002_add_milestoneDownSql,
-	"000002_add_milestone.up.sql":         _000002_add_milestoneUpSql,
-	"000003_drop_spinmint_table.down.sql": _000003_drop_spinmint_tableDownSql,
-	"000003_drop_spinmint_table.up.sql":   _000003_drop_spinmint_tableUpSql,
+	"000001_base.down.sql":                        _000001_baseDownSql,
+	"000001_base.up.sql":                          _000001_baseUpSql,
+	"000002_add_milestone.down.sql":               _000002_add_milestoneDownSql,
+	"000002_add_milestone.up.sql":                 _000002_add_milestoneUpSql,
+	"000003_drop_spinmint_table.down.sql":         _000003_drop_spinmint_tableDownSql,
+	"000003_drop_spinmint_table.up.sql":           _000003_drop_spinmint_tableUpSql,
+	"000004_create_webhook_deliveries.down.sql":   _000004_create_webhook_deliveriesDownSql,
+	"000004_create_webhook_deliveries.up.sql":     _000004_create_webhook_deliveriesUpSql,
+	"000005_add_pull_requests_sha_index.down.sql": _000005_add_pull_requests_sha_indexDownSql,
+	"000005_add_pull_requests_sha_index.up.sql":   _000005_add_pull_requests_sha_indexUpSql,
+	"000006_create_pending_merges.down.sql":       _000006_create_pending_mergesDownSql,
+	"000006_create_pending_merges.up.sql":         _000006_create_pending_mergesUpSql,
+	"000007_create_holds.down.sql":                _000007_create_holdsDownSql,
+	"000007_create_holds.up.sql":                  _000007_create_holdsUpSql,
+	"000008_create_cherry_pick_jobs.down.sql":     _000008_create_cherry_pick_jobsDownSql,
+	"000008_create_cherry_pick_jobs.up.sql":       _000008_create_cherry_pick_jobsUpSql,
+	"000009_create_backports.down.sql":            _000009_create_backportsDownSql,
+	"000009_create_backports.up.sql":              _000009_create_backportsUpSql,
+	"000010_create_merge_queue.down.sql":          _000010_create_merge_queueDownSql,
+	"000010_create_merge_queue.up.sql":            _000010_create_merge_queueUpSql,
 }
 
 // AssetDebug is true if the assets were built with the debug flag enabled.
@@ -344,6 +652,20 @@ var _bintree = &bintree{nil, map[string]*bintree{
 	"000002_add_milestone.up.sql": {_000002_add_milestoneUpSql, map[string]*bintree{}},
 	"000003_drop_spinmint_table.down.sql": {_000003_drop_spinmint_tableDownSql, map[string]*bintree{}},
 	"000003_drop_spinmint_table.up.sql": {_000003_drop_spinmint_tableUpSql, map[string]*bintree{}},
+	"000004_create_webhook_deliveries.down.sql": {_000004_create_webhook_deliveriesDownSql, map[string]*bintree{}},
+	"000004_create_webhook_deliveries.up.sql": {_000004_create_webhook_deliveriesUpSql, map[string]*bintree{}},
+	"000005_add_pull_requests_sha_index.down.sql": {_000005_add_pull_requests_sha_indexDownSql, map[string]*bintree{}},
+	"000005_add_pull_requests_sha_index.up.sql": {_000005_add_pull_requests_sha_indexUpSql, map[string]*bintree{}},
+	"000006_create_pending_merges.down.sql": {_000006_create_pending_mergesDownSql, map[string]*bintree{}},
+	"000006_create_pending_merges.up.sql": {_000006_create_pending_mergesUpSql, map[string]*bintree{}},
+	"000007_create_holds.down.sql": {_000007_create_holdsDownSql, map[string]*bintree{}},
+	"000007_create_holds.up.sql": {_000007_create_holdsUpSql, map[string]*bintree{}},
+	"000008_create_cherry_pick_jobs.down.sql": {_000008_create_cherry_pick_jobsDownSql, map[string]*bintree{}},
+	"000008_create_cherry_pick_jobs.up.sql": {_000008_create_cherry_pick_jobsUpSql, map[string]*bintree{}},
+	"000009_create_backports.down.sql": {_000009_create_backportsDownSql, map[string]*bintree{}},
+	"000009_create_backports.up.sql": {_000009_create_backportsUpSql, map[string]*bintree{}},
+	"000010_create_merge_queue.down.sql": {_000010_create_merge_queueDownSql, map[string]*bintree{}},
+	"000010_create_merge_queue.up.sql": {_000010_create_merge_queueUpSql, map[string]*bintree{}},
 }}
 
 // RestoreAsset restores an asset under the given directory.
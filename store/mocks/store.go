@@ -7,6 +7,7 @@ package mocks
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	gomock "github.com/golang/mock/gomock"
 	model "github.com/mattermost/mattermost-mattermod/model"
@@ -36,6 +37,34 @@ func (m *MockStore) EXPECT() *MockStoreMockRecorder {
 	return m.recorder
 }
 
+// Backport mocks base method.
+func (m *MockStore) Backport() store.BackportStore {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Backport")
+	ret0, _ := ret[0].(store.BackportStore)
+	return ret0
+}
+
+// Backport indicates an expected call of Backport.
+func (mr *MockStoreMockRecorder) Backport() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Backport", reflect.TypeOf((*MockStore)(nil).Backport))
+}
+
+// CherryPickJob mocks base method.
+func (m *MockStore) CherryPickJob() store.CherryPickJobStore {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CherryPickJob")
+	ret0, _ := ret[0].(store.CherryPickJobStore)
+	return ret0
+}
+
+// CherryPickJob indicates an expected call of CherryPickJob.
+func (mr *MockStoreMockRecorder) CherryPickJob() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CherryPickJob", reflect.TypeOf((*MockStore)(nil).CherryPickJob))
+}
+
 // Close mocks base method.
 func (m *MockStore) Close() {
 	m.ctrl.T.Helper()
@@ -60,6 +89,20 @@ func (mr *MockStoreMockRecorder) DropAllTables() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DropAllTables", reflect.TypeOf((*MockStore)(nil).DropAllTables))
 }
 
+// Hold mocks base method.
+func (m *MockStore) Hold() store.HoldStore {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Hold")
+	ret0, _ := ret[0].(store.HoldStore)
+	return ret0
+}
+
+// Hold indicates an expected call of Hold.
+func (mr *MockStoreMockRecorder) Hold() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Hold", reflect.TypeOf((*MockStore)(nil).Hold))
+}
+
 // Issue mocks base method.
 func (m *MockStore) Issue() store.IssueStore {
 	m.ctrl.T.Helper()
@@ -74,6 +117,20 @@ func (mr *MockStoreMockRecorder) Issue() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Issue", reflect.TypeOf((*MockStore)(nil).Issue))
 }
 
+// MergeQueue mocks base method.
+func (m *MockStore) MergeQueue() store.MergeQueueStore {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MergeQueue")
+	ret0, _ := ret[0].(store.MergeQueueStore)
+	return ret0
+}
+
+// MergeQueue indicates an expected call of MergeQueue.
+func (mr *MockStoreMockRecorder) MergeQueue() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MergeQueue", reflect.TypeOf((*MockStore)(nil).MergeQueue))
+}
+
 // Mutex mocks base method.
 func (m *MockStore) Mutex() store.LockStore {
 	m.ctrl.T.Helper()
@@ -88,6 +145,20 @@ func (mr *MockStoreMockRecorder) Mutex() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Mutex", reflect.TypeOf((*MockStore)(nil).Mutex))
 }
 
+// PendingMerge mocks base method.
+func (m *MockStore) PendingMerge() store.PendingMergeStore {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PendingMerge")
+	ret0, _ := ret[0].(store.PendingMergeStore)
+	return ret0
+}
+
+// PendingMerge indicates an expected call of PendingMerge.
+func (mr *MockStoreMockRecorder) PendingMerge() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PendingMerge", reflect.TypeOf((*MockStore)(nil).PendingMerge))
+}
+
 // PullRequest mocks base method.
 func (m *MockStore) PullRequest() store.PullRequestStore {
 	m.ctrl.T.Helper()
@@ -102,6 +173,20 @@ func (mr *MockStoreMockRecorder) PullRequest() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PullRequest", reflect.TypeOf((*MockStore)(nil).PullRequest))
 }
 
+// WebhookDelivery mocks base method.
+func (m *MockStore) WebhookDelivery() store.WebhookDeliveryStore {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WebhookDelivery")
+	ret0, _ := ret[0].(store.WebhookDeliveryStore)
+	return ret0
+}
+
+// WebhookDelivery indicates an expected call of WebhookDelivery.
+func (mr *MockStoreMockRecorder) WebhookDelivery() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WebhookDelivery", reflect.TypeOf((*MockStore)(nil).WebhookDelivery))
+}
+
 // MockPullRequestStore is a mock of PullRequestStore interface.
 type MockPullRequestStore struct {
 	ctrl     *gomock.Controller
@@ -140,6 +225,21 @@ func (mr *MockPullRequestStoreMockRecorder) Get(repoOwner, repoName, number inte
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockPullRequestStore)(nil).Get), repoOwner, repoName, number)
 }
 
+// ListBySha mocks base method.
+func (m *MockPullRequestStore) ListBySha(repoOwner, repoName, sha string) ([]*model.PullRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListBySha", repoOwner, repoName, sha)
+	ret0, _ := ret[0].([]*model.PullRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListBySha indicates an expected call of ListBySha.
+func (mr *MockPullRequestStoreMockRecorder) ListBySha(repoOwner, repoName, sha interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListBySha", reflect.TypeOf((*MockPullRequestStore)(nil).ListBySha), repoOwner, repoName, sha)
+}
+
 // ListOpen mocks base method.
 func (m *MockPullRequestStore) ListOpen() ([]*model.PullRequest, error) {
 	m.ctrl.T.Helper()
@@ -223,6 +323,595 @@ func (mr *MockIssueStoreMockRecorder) Save(issue interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockIssueStore)(nil).Save), issue)
 }
 
+// MockWebhookDeliveryStore is a mock of WebhookDeliveryStore interface.
+type MockWebhookDeliveryStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockWebhookDeliveryStoreMockRecorder
+}
+
+// MockWebhookDeliveryStoreMockRecorder is the mock recorder for MockWebhookDeliveryStore.
+type MockWebhookDeliveryStoreMockRecorder struct {
+	mock *MockWebhookDeliveryStore
+}
+
+// NewMockWebhookDeliveryStore creates a new mock instance.
+func NewMockWebhookDeliveryStore(ctrl *gomock.Controller) *MockWebhookDeliveryStore {
+	mock := &MockWebhookDeliveryStore{ctrl: ctrl}
+	mock.recorder = &MockWebhookDeliveryStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWebhookDeliveryStore) EXPECT() *MockWebhookDeliveryStoreMockRecorder {
+	return m.recorder
+}
+
+// Claim mocks base method.
+func (m *MockWebhookDeliveryStore) Claim(deliveryID string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Claim", deliveryID)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Claim indicates an expected call of Claim.
+func (mr *MockWebhookDeliveryStoreMockRecorder) Claim(deliveryID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Claim", reflect.TypeOf((*MockWebhookDeliveryStore)(nil).Claim), deliveryID)
+}
+
+// Create mocks base method.
+func (m *MockWebhookDeliveryStore) Create(delivery *model.WebhookDelivery) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", delivery)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockWebhookDeliveryStoreMockRecorder) Create(delivery interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockWebhookDeliveryStore)(nil).Create), delivery)
+}
+
+// Get mocks base method.
+func (m *MockWebhookDeliveryStore) Get(deliveryID string) (*model.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", deliveryID)
+	ret0, _ := ret[0].(*model.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockWebhookDeliveryStoreMockRecorder) Get(deliveryID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockWebhookDeliveryStore)(nil).Get), deliveryID)
+}
+
+// ListPending mocks base method.
+func (m *MockWebhookDeliveryStore) ListPending(until time.Time, limit int) ([]*model.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPending", until, limit)
+	ret0, _ := ret[0].([]*model.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPending indicates an expected call of ListPending.
+func (mr *MockWebhookDeliveryStoreMockRecorder) ListPending(until, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPending", reflect.TypeOf((*MockWebhookDeliveryStore)(nil).ListPending), until, limit)
+}
+
+// Release mocks base method.
+func (m *MockWebhookDeliveryStore) Release(deliveryID, lastError string, nextAttemptAt int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Release", deliveryID, lastError, nextAttemptAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Release indicates an expected call of Release.
+func (mr *MockWebhookDeliveryStoreMockRecorder) Release(deliveryID, lastError, nextAttemptAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Release", reflect.TypeOf((*MockWebhookDeliveryStore)(nil).Release), deliveryID, lastError, nextAttemptAt)
+}
+
+// Reset mocks base method.
+func (m *MockWebhookDeliveryStore) Reset(deliveryID string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reset", deliveryID)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Reset indicates an expected call of Reset.
+func (mr *MockWebhookDeliveryStoreMockRecorder) Reset(deliveryID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reset", reflect.TypeOf((*MockWebhookDeliveryStore)(nil).Reset), deliveryID)
+}
+
+// ResetRunning mocks base method.
+func (m *MockWebhookDeliveryStore) ResetRunning() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResetRunning")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ResetRunning indicates an expected call of ResetRunning.
+func (mr *MockWebhookDeliveryStoreMockRecorder) ResetRunning() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetRunning", reflect.TypeOf((*MockWebhookDeliveryStore)(nil).ResetRunning))
+}
+
+// Update mocks base method.
+func (m *MockWebhookDeliveryStore) Update(delivery *model.WebhookDelivery) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", delivery)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockWebhookDeliveryStoreMockRecorder) Update(delivery interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockWebhookDeliveryStore)(nil).Update), delivery)
+}
+
+// MockPendingMergeStore is a mock of PendingMergeStore interface.
+type MockPendingMergeStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockPendingMergeStoreMockRecorder
+}
+
+// MockPendingMergeStoreMockRecorder is the mock recorder for MockPendingMergeStore.
+type MockPendingMergeStoreMockRecorder struct {
+	mock *MockPendingMergeStore
+}
+
+// NewMockPendingMergeStore creates a new mock instance.
+func NewMockPendingMergeStore(ctrl *gomock.Controller) *MockPendingMergeStore {
+	mock := &MockPendingMergeStore{ctrl: ctrl}
+	mock.recorder = &MockPendingMergeStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPendingMergeStore) EXPECT() *MockPendingMergeStoreMockRecorder {
+	return m.recorder
+}
+
+// Claim mocks base method.
+func (m *MockPendingMergeStore) Claim(merge *model.PendingMerge) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Claim", merge)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Claim indicates an expected call of Claim.
+func (mr *MockPendingMergeStoreMockRecorder) Claim(merge interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Claim", reflect.TypeOf((*MockPendingMergeStore)(nil).Claim), merge)
+}
+
+// Delete mocks base method.
+func (m *MockPendingMergeStore) Delete(repoOwner, repoName string, number int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", repoOwner, repoName, number)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockPendingMergeStoreMockRecorder) Delete(repoOwner, repoName, number interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockPendingMergeStore)(nil).Delete), repoOwner, repoName, number)
+}
+
+// Get mocks base method.
+func (m *MockPendingMergeStore) Get(repoOwner, repoName string, number int) (*model.PendingMerge, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", repoOwner, repoName, number)
+	ret0, _ := ret[0].(*model.PendingMerge)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockPendingMergeStoreMockRecorder) Get(repoOwner, repoName, number interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockPendingMergeStore)(nil).Get), repoOwner, repoName, number)
+}
+
+// List mocks base method.
+func (m *MockPendingMergeStore) List() ([]*model.PendingMerge, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List")
+	ret0, _ := ret[0].([]*model.PendingMerge)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockPendingMergeStoreMockRecorder) List() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockPendingMergeStore)(nil).List))
+}
+
+// Save mocks base method.
+func (m *MockPendingMergeStore) Save(merge *model.PendingMerge) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Save", merge)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Save indicates an expected call of Save.
+func (mr *MockPendingMergeStoreMockRecorder) Save(merge interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockPendingMergeStore)(nil).Save), merge)
+}
+
+// MockHoldStore is a mock of HoldStore interface.
+type MockHoldStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockHoldStoreMockRecorder
+}
+
+// MockHoldStoreMockRecorder is the mock recorder for MockHoldStore.
+type MockHoldStoreMockRecorder struct {
+	mock *MockHoldStore
+}
+
+// NewMockHoldStore creates a new mock instance.
+func NewMockHoldStore(ctrl *gomock.Controller) *MockHoldStore {
+	mock := &MockHoldStore{ctrl: ctrl}
+	mock.recorder = &MockHoldStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockHoldStore) EXPECT() *MockHoldStoreMockRecorder {
+	return m.recorder
+}
+
+// Delete mocks base method.
+func (m *MockHoldStore) Delete(repoOwner, repoName string, number int, holder string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", repoOwner, repoName, number, holder)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockHoldStoreMockRecorder) Delete(repoOwner, repoName, number, holder interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockHoldStore)(nil).Delete), repoOwner, repoName, number, holder)
+}
+
+// DeleteAll mocks base method.
+func (m *MockHoldStore) DeleteAll(repoOwner, repoName string, number int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAll", repoOwner, repoName, number)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteAll indicates an expected call of DeleteAll.
+func (mr *MockHoldStoreMockRecorder) DeleteAll(repoOwner, repoName, number interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAll", reflect.TypeOf((*MockHoldStore)(nil).DeleteAll), repoOwner, repoName, number)
+}
+
+// List mocks base method.
+func (m *MockHoldStore) List(repoOwner, repoName string, number int) ([]*model.Hold, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", repoOwner, repoName, number)
+	ret0, _ := ret[0].([]*model.Hold)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockHoldStoreMockRecorder) List(repoOwner, repoName, number interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockHoldStore)(nil).List), repoOwner, repoName, number)
+}
+
+// Save mocks base method.
+func (m *MockHoldStore) Save(hold *model.Hold) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Save", hold)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Save indicates an expected call of Save.
+func (mr *MockHoldStoreMockRecorder) Save(hold interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockHoldStore)(nil).Save), hold)
+}
+
+// MockCherryPickJobStore is a mock of CherryPickJobStore interface.
+type MockCherryPickJobStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockCherryPickJobStoreMockRecorder
+}
+
+// MockCherryPickJobStoreMockRecorder is the mock recorder for MockCherryPickJobStore.
+type MockCherryPickJobStoreMockRecorder struct {
+	mock *MockCherryPickJobStore
+}
+
+// NewMockCherryPickJobStore creates a new mock instance.
+func NewMockCherryPickJobStore(ctrl *gomock.Controller) *MockCherryPickJobStore {
+	mock := &MockCherryPickJobStore{ctrl: ctrl}
+	mock.recorder = &MockCherryPickJobStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCherryPickJobStore) EXPECT() *MockCherryPickJobStoreMockRecorder {
+	return m.recorder
+}
+
+// Claim mocks base method.
+func (m *MockCherryPickJobStore) Claim(id int64) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Claim", id)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Claim indicates an expected call of Claim.
+func (mr *MockCherryPickJobStoreMockRecorder) Claim(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Claim", reflect.TypeOf((*MockCherryPickJobStore)(nil).Claim), id)
+}
+
+// Create mocks base method.
+func (m *MockCherryPickJobStore) Create(job *model.CherryPickJob) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", job)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockCherryPickJobStoreMockRecorder) Create(job interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockCherryPickJobStore)(nil).Create), job)
+}
+
+// Get mocks base method.
+func (m *MockCherryPickJobStore) Get(id int64) (*model.CherryPickJob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", id)
+	ret0, _ := ret[0].(*model.CherryPickJob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockCherryPickJobStoreMockRecorder) Get(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockCherryPickJobStore)(nil).Get), id)
+}
+
+// ListByPR mocks base method.
+func (m *MockCherryPickJobStore) ListByPR(repoOwner, repoName string, number int) ([]*model.CherryPickJob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByPR", repoOwner, repoName, number)
+	ret0, _ := ret[0].([]*model.CherryPickJob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListByPR indicates an expected call of ListByPR.
+func (mr *MockCherryPickJobStoreMockRecorder) ListByPR(repoOwner, repoName, number interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByPR", reflect.TypeOf((*MockCherryPickJobStore)(nil).ListByPR), repoOwner, repoName, number)
+}
+
+// ListPending mocks base method.
+func (m *MockCherryPickJobStore) ListPending(until time.Time, limit int) ([]*model.CherryPickJob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPending", until, limit)
+	ret0, _ := ret[0].([]*model.CherryPickJob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPending indicates an expected call of ListPending.
+func (mr *MockCherryPickJobStoreMockRecorder) ListPending(until, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPending", reflect.TypeOf((*MockCherryPickJobStore)(nil).ListPending), until, limit)
+}
+
+// ResetRunning mocks base method.
+func (m *MockCherryPickJobStore) ResetRunning() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResetRunning")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ResetRunning indicates an expected call of ResetRunning.
+func (mr *MockCherryPickJobStoreMockRecorder) ResetRunning() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetRunning", reflect.TypeOf((*MockCherryPickJobStore)(nil).ResetRunning))
+}
+
+// Update mocks base method.
+func (m *MockCherryPickJobStore) Update(job *model.CherryPickJob) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", job)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockCherryPickJobStoreMockRecorder) Update(job interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockCherryPickJobStore)(nil).Update), job)
+}
+
+// MockBackportStore is a mock of BackportStore interface.
+type MockBackportStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockBackportStoreMockRecorder
+}
+
+// MockBackportStoreMockRecorder is the mock recorder for MockBackportStore.
+type MockBackportStoreMockRecorder struct {
+	mock *MockBackportStore
+}
+
+// NewMockBackportStore creates a new mock instance.
+func NewMockBackportStore(ctrl *gomock.Controller) *MockBackportStore {
+	mock := &MockBackportStore{ctrl: ctrl}
+	mock.recorder = &MockBackportStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBackportStore) EXPECT() *MockBackportStoreMockRecorder {
+	return m.recorder
+}
+
+// GetByBackport mocks base method.
+func (m *MockBackportStore) GetByBackport(repoOwner, repoName string, backportNumber int) (*model.Backport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByBackport", repoOwner, repoName, backportNumber)
+	ret0, _ := ret[0].(*model.Backport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByBackport indicates an expected call of GetByBackport.
+func (mr *MockBackportStoreMockRecorder) GetByBackport(repoOwner, repoName, backportNumber interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByBackport", reflect.TypeOf((*MockBackportStore)(nil).GetByBackport), repoOwner, repoName, backportNumber)
+}
+
+// ListByMilestone mocks base method.
+func (m *MockBackportStore) ListByMilestone(milestone string) ([]*model.Backport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByMilestone", milestone)
+	ret0, _ := ret[0].([]*model.Backport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListByMilestone indicates an expected call of ListByMilestone.
+func (mr *MockBackportStoreMockRecorder) ListByMilestone(milestone interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByMilestone", reflect.TypeOf((*MockBackportStore)(nil).ListByMilestone), milestone)
+}
+
+// ListByPR mocks base method.
+func (m *MockBackportStore) ListByPR(repoOwner, repoName string, number int) ([]*model.Backport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByPR", repoOwner, repoName, number)
+	ret0, _ := ret[0].([]*model.Backport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListByPR indicates an expected call of ListByPR.
+func (mr *MockBackportStoreMockRecorder) ListByPR(repoOwner, repoName, number interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByPR", reflect.TypeOf((*MockBackportStore)(nil).ListByPR), repoOwner, repoName, number)
+}
+
+// Save mocks base method.
+func (m *MockBackportStore) Save(backport *model.Backport) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Save", backport)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Save indicates an expected call of Save.
+func (mr *MockBackportStoreMockRecorder) Save(backport interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockBackportStore)(nil).Save), backport)
+}
+
+// MockMergeQueueStore is a mock of MergeQueueStore interface.
+type MockMergeQueueStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockMergeQueueStoreMockRecorder
+}
+
+// MockMergeQueueStoreMockRecorder is the mock recorder for MockMergeQueueStore.
+type MockMergeQueueStoreMockRecorder struct {
+	mock *MockMergeQueueStore
+}
+
+// NewMockMergeQueueStore creates a new mock instance.
+func NewMockMergeQueueStore(ctrl *gomock.Controller) *MockMergeQueueStore {
+	mock := &MockMergeQueueStore{ctrl: ctrl}
+	mock.recorder = &MockMergeQueueStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockMergeQueueStore) EXPECT() *MockMergeQueueStoreMockRecorder {
+	return m.recorder
+}
+
+// Add mocks base method.
+func (m *MockMergeQueueStore) Add(entry *model.MergeQueueEntry) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Add", entry)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Add indicates an expected call of Add.
+func (mr *MockMergeQueueStoreMockRecorder) Add(entry interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Add", reflect.TypeOf((*MockMergeQueueStore)(nil).Add), entry)
+}
+
+// Delete mocks base method.
+func (m *MockMergeQueueStore) Delete(repoOwner, repoName string, number int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", repoOwner, repoName, number)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockMergeQueueStoreMockRecorder) Delete(repoOwner, repoName, number interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockMergeQueueStore)(nil).Delete), repoOwner, repoName, number)
+}
+
+// List mocks base method.
+func (m *MockMergeQueueStore) List() ([]*model.MergeQueueEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List")
+	ret0, _ := ret[0].([]*model.MergeQueueEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockMergeQueueStoreMockRecorder) List() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockMergeQueueStore)(nil).List))
+}
+
 // MockLockStore is a mock of LockStore interface.
 type MockLockStore struct {
 	ctrl     *gomock.Controller
@@ -0,0 +1,91 @@
+// Copyright (c) 2017-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package store
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/mattermost/mattermost-mattermod/model"
+)
+
+type SQLBackportStore struct {
+	*SQLStore
+}
+
+func NewSQLBackportStore(sqlStore *SQLStore) BackportStore {
+	return &SQLBackportStore{sqlStore}
+}
+
+func (s SQLBackportStore) Save(backport *model.Backport) error {
+	now := model.GetMillis()
+	if backport.CreatedAt == 0 {
+		backport.CreatedAt = now
+	}
+	backport.UpdatedAt = now
+
+	if _, err := s.dbx.NamedExec(
+		`INSERT INTO Backports
+			(RepoOwner, RepoName, Number, Branch, Milestone, BackportNumber, State, CreatedAt, UpdatedAt)
+		VALUES
+			(:RepoOwner, :RepoName, :Number, :Branch, :Milestone, :BackportNumber, :State, :CreatedAt, :UpdatedAt)
+		ON DUPLICATE KEY UPDATE
+			Milestone = IF(VALUES(Milestone) = '', Milestone, VALUES(Milestone)),
+			BackportNumber = VALUES(BackportNumber), State = VALUES(State), UpdatedAt = VALUES(UpdatedAt)`, backport); err != nil {
+		return fmt.Errorf("could not save backport: owner=%v, name=%v, number=%v, branch=%v, err=%w", backport.RepoOwner, backport.RepoName, backport.Number, backport.Branch, err)
+	}
+	return nil
+}
+
+func (s SQLBackportStore) GetByBackport(repoOwner, repoName string, backportNumber int) (*model.Backport, error) {
+	var backport model.Backport
+	if err := s.dbx.Get(&backport,
+		`SELECT
+				*
+			FROM
+				Backports
+			WHERE
+				RepoOwner = ?
+				AND RepoName = ?
+				AND BackportNumber = ?
+			LIMIT 1`, repoOwner, repoName, backportNumber); err != nil {
+		if err != sql.ErrNoRows {
+			return nil, fmt.Errorf("could not get backport: owner=%v, name=%v, backport=%v, err=%w", repoOwner, repoName, backportNumber, err)
+		}
+		return nil, nil // row not found.
+	}
+	return &backport, nil
+}
+
+func (s SQLBackportStore) ListByPR(repoOwner, repoName string, number int) ([]*model.Backport, error) {
+	var backports []*model.Backport
+	if err := s.dbx.Select(&backports,
+		`SELECT
+				*
+			FROM
+				Backports
+			WHERE
+				RepoOwner = ?
+				AND RepoName = ?
+				AND Number = ?
+			ORDER BY CreatedAt ASC, Branch ASC`, repoOwner, repoName, number); err != nil {
+		return nil, fmt.Errorf("could not list backports: owner=%v, name=%v, number=%v, err=%w", repoOwner, repoName, number, err)
+	}
+	return backports, nil
+}
+
+func (s SQLBackportStore) ListByMilestone(milestone string) ([]*model.Backport, error) {
+	var backports []*model.Backport
+	if err := s.dbx.Select(&backports,
+		`SELECT
+				*
+			FROM
+				Backports
+			WHERE
+				Milestone = ?
+			ORDER BY RepoOwner ASC, RepoName ASC, Number ASC, Branch ASC`, milestone); err != nil {
+		return nil, fmt.Errorf("could not list backports: milestone=%v, err=%w", milestone, err)
+	}
+	return backports, nil
+}
@@ -0,0 +1,92 @@
+// Copyright (c) 2017-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package store
+
+import (
+	"testing"
+
+	"github.com/mattermost/mattermost-mattermod/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBackportStore(t *testing.T) {
+	store := getTestSQLStore(t)
+	backportStore := NewSQLBackportStore(store)
+
+	newBackport := func(number int, branch, milestone string) *model.Backport {
+		return &model.Backport{
+			RepoOwner: "owner",
+			RepoName:  "repo",
+			Number:    number,
+			Branch:    branch,
+			Milestone: milestone,
+			State:     model.BackportQueued,
+		}
+	}
+
+	t.Run("Should save and list backports of a PR", func(t *testing.T) {
+		defer cleanBackportsTable(t, store)
+		require.NoError(t, backportStore.Save(newBackport(1, "release-7.1", "v7.1")))
+		require.NoError(t, backportStore.Save(newBackport(1, "release-7.2", "v7.1")))
+		require.NoError(t, backportStore.Save(newBackport(2, "release-7.1", "v7.1")))
+
+		backports, err := backportStore.ListByPR("owner", "repo", 1)
+		require.NoError(t, err)
+		require.Len(t, backports, 2)
+		require.Equal(t, "release-7.1", backports[0].Branch)
+		require.Equal(t, "release-7.2", backports[1].Branch)
+	})
+
+	t.Run("Should replace the backport of a branch", func(t *testing.T) {
+		defer cleanBackportsTable(t, store)
+		require.NoError(t, backportStore.Save(newBackport(1, "release-7.1", "v7.1")))
+
+		backport := newBackport(1, "release-7.1", "")
+		backport.State = model.BackportOpen
+		backport.BackportNumber = 10
+		require.NoError(t, backportStore.Save(backport))
+
+		backports, err := backportStore.ListByPR("owner", "repo", 1)
+		require.NoError(t, err)
+		require.Len(t, backports, 1)
+		require.Equal(t, model.BackportOpen, backports[0].State)
+		require.Equal(t, 10, backports[0].BackportNumber)
+		require.Equal(t, "v7.1", backports[0].Milestone)
+	})
+
+	t.Run("Should get a backport by its cherry pick PR", func(t *testing.T) {
+		defer cleanBackportsTable(t, store)
+		backport := newBackport(1, "release-7.1", "v7.1")
+		backport.BackportNumber = 10
+		require.NoError(t, backportStore.Save(backport))
+
+		got, err := backportStore.GetByBackport("owner", "repo", 10)
+		require.NoError(t, err)
+		require.NotNil(t, got)
+		require.Equal(t, 1, got.Number)
+
+		got, err = backportStore.GetByBackport("owner", "repo", 11)
+		require.NoError(t, err)
+		require.Nil(t, got)
+	})
+
+	t.Run("Should list backports by milestone", func(t *testing.T) {
+		defer cleanBackportsTable(t, store)
+		require.NoError(t, backportStore.Save(newBackport(2, "release-7.1", "v7.1")))
+		require.NoError(t, backportStore.Save(newBackport(1, "release-7.1", "v7.1")))
+		require.NoError(t, backportStore.Save(newBackport(3, "release-7.2", "v7.2")))
+
+		backports, err := backportStore.ListByMilestone("v7.1")
+		require.NoError(t, err)
+		require.Len(t, backports, 2)
+		require.Equal(t, 1, backports[0].Number)
+		require.Equal(t, 2, backports[1].Number)
+	})
+}
+
+func cleanBackportsTable(t *testing.T, store *SQLStore) {
+	if _, err := store.dbx.Exec("TRUNCATE TABLE Backports;"); err != nil {
+		require.Fail(t, "Backports table cleaning failed", err.Error())
+	}
+}
@@ -0,0 +1,135 @@
+// Copyright (c) 2017-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package store
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/mattermost/mattermost-mattermod/model"
+)
+
+type SQLCherryPickJobStore struct {
+	*SQLStore
+}
+
+func NewSQLCherryPickJobStore(sqlStore *SQLStore) CherryPickJobStore {
+	return &SQLCherryPickJobStore{sqlStore}
+}
+
+func (s SQLCherryPickJobStore) Create(job *model.CherryPickJob) error {
+	now := model.GetMillis()
+	if job.State == "" {
+		job.State = model.CherryPickJobPending
+	}
+	if job.Kind == "" {
+		job.Kind = model.CherryPickJobKindCherryPick
+	}
+	job.CreatedAt = now
+	job.UpdatedAt = now
+
+	res, err := s.dbx.NamedExec(
+		`INSERT INTO CherryPickJobs
+			(Kind, RepoOwner, RepoName, Number, Branch, MergeCommitSHA, Milestone, State, Attempts, NextAttemptAt, Output, Conflicts, NewPRNumber, CreatedAt, UpdatedAt)
+		VALUES
+			(:Kind, :RepoOwner, :RepoName, :Number, :Branch, :MergeCommitSHA, :Milestone, :State, :Attempts, :NextAttemptAt, :Output, :Conflicts, :NewPRNumber, :CreatedAt, :UpdatedAt)`, job)
+	if err != nil {
+		return fmt.Errorf("could not create cherry pick job: owner=%v, name=%v, number=%v, branch=%v, err=%w", job.RepoOwner, job.RepoName, job.Number, job.Branch, err)
+	}
+	if job.ID, err = res.LastInsertId(); err != nil {
+		return fmt.Errorf("could not get the id of the cherry pick job: owner=%v, name=%v, number=%v, branch=%v, err=%w", job.RepoOwner, job.RepoName, job.Number, job.Branch, err)
+	}
+	return nil
+}
+
+func (s SQLCherryPickJobStore) Get(id int64) (*model.CherryPickJob, error) {
+	var job model.CherryPickJob
+	if err := s.dbx.Get(&job,
+		`SELECT
+				*
+			FROM
+				CherryPickJobs
+			WHERE
+				ID = ?`, id); err != nil {
+		if err != sql.ErrNoRows {
+			return nil, fmt.Errorf("could not get cherry pick job: id=%v, err=%w", id, err)
+		}
+		return nil, nil // row not found.
+	}
+	return &job, nil
+}
+
+func (s SQLCherryPickJobStore) Claim(id int64) (bool, error) {
+	res, err := s.dbx.Exec(
+		`UPDATE CherryPickJobs
+			SET State = ?, Attempts = Attempts + 1, UpdatedAt = ?
+			WHERE ID = ? AND State = ?`,
+		model.CherryPickJobRunning, model.GetMillis(), id, model.CherryPickJobPending)
+	if err != nil {
+		return false, fmt.Errorf("could not claim cherry pick job: id=%v, err=%w", id, err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("could not claim cherry pick job: id=%v, err=%w", id, err)
+	}
+	return n == 1, nil
+}
+
+func (s SQLCherryPickJobStore) Update(job *model.CherryPickJob) error {
+	job.UpdatedAt = model.GetMillis()
+	if _, err := s.dbx.NamedExec(
+		`UPDATE CherryPickJobs
+			SET State = :State, Attempts = :Attempts, NextAttemptAt = :NextAttemptAt, Output = :Output,
+				Conflicts = :Conflicts, NewPRNumber = :NewPRNumber, UpdatedAt = :UpdatedAt
+			WHERE ID = :ID`, job); err != nil {
+		return fmt.Errorf("could not update cherry pick job: id=%v, err=%w", job.ID, err)
+	}
+	return nil
+}
+
+func (s SQLCherryPickJobStore) ListPending(until time.Time, limit int) ([]*model.CherryPickJob, error) {
+	var jobs []*model.CherryPickJob
+	if err := s.dbx.Select(&jobs,
+		`SELECT
+				*
+			FROM
+				CherryPickJobs
+			WHERE
+				State = ?
+				AND NextAttemptAt <= ?
+			ORDER BY ID ASC
+			LIMIT ?`, model.CherryPickJobPending, model.GetMillisForTime(until), limit); err != nil {
+		return nil, fmt.Errorf("could not list pending cherry pick jobs: %w", err)
+	}
+	return jobs, nil
+}
+
+func (s SQLCherryPickJobStore) ListByPR(repoOwner, repoName string, number int) ([]*model.CherryPickJob, error) {
+	var jobs []*model.CherryPickJob
+	if err := s.dbx.Select(&jobs,
+		`SELECT
+				*
+			FROM
+				CherryPickJobs
+			WHERE
+				RepoOwner = ?
+				AND RepoName = ?
+				AND Number = ?
+			ORDER BY ID ASC`, repoOwner, repoName, number); err != nil {
+		return nil, fmt.Errorf("could not list cherry pick jobs: owner=%v, name=%v, number=%v, err=%w", repoOwner, repoName, number, err)
+	}
+	return jobs, nil
+}
+
+func (s SQLCherryPickJobStore) ResetRunning() error {
+	if _, err := s.dbx.Exec(
+		`UPDATE CherryPickJobs
+			SET State = ?, UpdatedAt = ?
+			WHERE State = ?`,
+		model.CherryPickJobPending, model.GetMillis(), model.CherryPickJobRunning); err != nil {
+		return fmt.Errorf("could not reset running cherry pick jobs: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,137 @@
+// Copyright (c) 2017-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package store
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mattermost/mattermost-mattermod/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCherryPickJobStore(t *testing.T) {
+	store := getTestSQLStore(t)
+	jobStore := NewSQLCherryPickJobStore(store)
+
+	newJob := func(number int, branch string) *model.CherryPickJob {
+		return &model.CherryPickJob{
+			RepoOwner:      "owner",
+			RepoName:       "repo",
+			Number:         number,
+			Branch:         branch,
+			MergeCommitSHA: "merge-sha",
+		}
+	}
+
+	t.Run("Should create and get a job", func(t *testing.T) {
+		defer cleanCherryPickJobsTable(t, store)
+		job := newJob(1, "release-7.1")
+		require.NoError(t, jobStore.Create(job))
+		require.NotZero(t, job.ID)
+
+		got, err := jobStore.Get(job.ID)
+		require.NoError(t, err)
+		require.Equal(t, model.CherryPickJobPending, got.State)
+		require.Equal(t, "release-7.1", got.Branch)
+		require.Equal(t, model.CherryPickJobKindCherryPick, got.Kind)
+		require.NotZero(t, got.CreatedAt)
+	})
+
+	t.Run("Should return empty if can't find rows with Get", func(t *testing.T) {
+		defer cleanCherryPickJobsTable(t, store)
+		job, err := jobStore.Get(1)
+		require.NoError(t, err)
+		require.Nil(t, job)
+	})
+
+	t.Run("Should claim a pending job only once", func(t *testing.T) {
+		defer cleanCherryPickJobsTable(t, store)
+		job := newJob(1, "release-7.1")
+		require.NoError(t, jobStore.Create(job))
+
+		claimed, err := jobStore.Claim(job.ID)
+		require.NoError(t, err)
+		require.True(t, claimed)
+
+		claimed, err = jobStore.Claim(job.ID)
+		require.NoError(t, err)
+		require.False(t, claimed)
+
+		got, err := jobStore.Get(job.ID)
+		require.NoError(t, err)
+		require.Equal(t, model.CherryPickJobRunning, got.State)
+		require.Equal(t, 1, got.Attempts)
+	})
+
+	t.Run("Should update a job", func(t *testing.T) {
+		defer cleanCherryPickJobsTable(t, store)
+		job := newJob(1, "release-7.1")
+		require.NoError(t, jobStore.Create(job))
+
+		job.State = model.CherryPickJobFailed
+		job.Conflicts = "app/app.go\napp/user.go"
+		require.NoError(t, jobStore.Update(job))
+
+		got, err := jobStore.Get(job.ID)
+		require.NoError(t, err)
+		require.Equal(t, model.CherryPickJobFailed, got.State)
+		require.Equal(t, "app/app.go\napp/user.go", got.Conflicts)
+
+		job.State = model.CherryPickJobSucceeded
+		job.Conflicts = ""
+		job.NewPRNumber = 456
+		require.NoError(t, jobStore.Update(job))
+
+		got, err = jobStore.Get(job.ID)
+		require.NoError(t, err)
+		require.Equal(t, model.CherryPickJobSucceeded, got.State)
+		require.Equal(t, 456, got.NewPRNumber)
+	})
+
+	t.Run("Should list pending jobs and jobs of a PR in order", func(t *testing.T) {
+		defer cleanCherryPickJobsTable(t, store)
+		first := newJob(1, "release-7.1")
+		require.NoError(t, jobStore.Create(first))
+		require.NoError(t, jobStore.Create(newJob(1, "release-7.2")))
+		require.NoError(t, jobStore.Create(newJob(2, "release-7.1")))
+		later := newJob(3, "release-7.1")
+		later.NextAttemptAt = model.GetMillisForTime(time.Now().Add(time.Hour))
+		require.NoError(t, jobStore.Create(later))
+		_, err := jobStore.Claim(first.ID)
+		require.NoError(t, err)
+
+		jobs, err := jobStore.ListPending(time.Now(), 10)
+		require.NoError(t, err)
+		require.Len(t, jobs, 2)
+		require.Equal(t, "release-7.2", jobs[0].Branch)
+
+		jobs, err = jobStore.ListByPR("owner", "repo", 1)
+		require.NoError(t, err)
+		require.Len(t, jobs, 2)
+		require.Equal(t, "release-7.1", jobs[0].Branch)
+		require.Equal(t, "release-7.2", jobs[1].Branch)
+	})
+
+	t.Run("Should reset running jobs", func(t *testing.T) {
+		defer cleanCherryPickJobsTable(t, store)
+		job := newJob(1, "release-7.1")
+		require.NoError(t, jobStore.Create(job))
+		_, err := jobStore.Claim(job.ID)
+		require.NoError(t, err)
+
+		require.NoError(t, jobStore.ResetRunning())
+
+		got, err := jobStore.Get(job.ID)
+		require.NoError(t, err)
+		require.Equal(t, model.CherryPickJobPending, got.State)
+		require.Equal(t, 1, got.Attempts)
+	})
+}
+
+func cleanCherryPickJobsTable(t *testing.T, store *SQLStore) {
+	if _, err := store.dbx.Exec("TRUNCATE TABLE CherryPickJobs;"); err != nil {
+		require.Fail(t, "CherryPickJobs table cleaning failed", err.Error())
+	}
+}
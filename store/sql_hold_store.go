@@ -0,0 +1,69 @@
+// Copyright (c) 2017-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package store
+
+import (
+	"fmt"
+
+	"github.com/mattermost/mattermost-mattermod/model"
+)
+
+type SQLHoldStore struct {
+	*SQLStore
+}
+
+func NewSQLHoldStore(sqlStore *SQLStore) HoldStore {
+	return &SQLHoldStore{sqlStore}
+}
+
+func (s SQLHoldStore) Save(hold *model.Hold) error {
+	if hold.CreatedAt == 0 {
+		hold.CreatedAt = model.GetMillis()
+	}
+	if _, err := s.dbx.NamedExec(
+		`INSERT INTO Holds
+			(RepoOwner, RepoName, Number, Holder, Reason, CreatedAt)
+		VALUES
+			(:RepoOwner, :RepoName, :Number, :Holder, :Reason, :CreatedAt)
+		ON DUPLICATE KEY UPDATE
+			Reason = :Reason, CreatedAt = :CreatedAt`, hold); err != nil {
+		return fmt.Errorf("could not save hold: owner=%v, name=%v, number=%v, holder=%v, err=%w", hold.RepoOwner, hold.RepoName, hold.Number, hold.Holder, err)
+	}
+	return nil
+}
+
+func (s SQLHoldStore) List(repoOwner, repoName string, number int) ([]*model.Hold, error) {
+	var holds []*model.Hold
+	if err := s.dbx.Select(&holds,
+		`SELECT
+				*
+			FROM
+				Holds
+			WHERE
+				RepoOwner = ?
+				AND RepoName = ?
+				AND Number = ?
+			ORDER BY CreatedAt ASC`, repoOwner, repoName, number); err != nil {
+		return nil, fmt.Errorf("could not list holds: owner=%v, name=%v, number=%v, err=%w", repoOwner, repoName, number, err)
+	}
+	return holds, nil
+}
+
+func (s SQLHoldStore) Delete(repoOwner, repoName string, number int, holder string) error {
+	if _, err := s.dbx.Exec(
+		`DELETE FROM Holds
+			WHERE RepoOwner = ? AND RepoName = ? AND Number = ? AND Holder = ?`, repoOwner, repoName, number, holder); err != nil {
+		return fmt.Errorf("could not delete hold: owner=%v, name=%v, number=%v, holder=%v, err=%w", repoOwner, repoName, number, holder, err)
+	}
+	return nil
+}
+
+func (s SQLHoldStore) DeleteAll(repoOwner, repoName string, number int) error {
+	if _, err := s.dbx.Exec(
+		`DELETE FROM Holds
+			WHERE RepoOwner = ? AND RepoName = ? AND Number = ?`, repoOwner, repoName, number); err != nil {
+		return fmt.Errorf("could not delete holds: owner=%v, name=%v, number=%v, err=%w", repoOwner, repoName, number, err)
+	}
+	return nil
+}
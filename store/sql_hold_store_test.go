@@ -0,0 +1,75 @@
+// Copyright (c) 2017-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package store
+
+import (
+	"testing"
+
+	"github.com/mattermost/mattermost-mattermod/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHoldStore(t *testing.T) {
+	store := getTestSQLStore(t)
+	holdStore := NewSQLHoldStore(store)
+
+	newHold := func(holder, reason string) *model.Hold {
+		return &model.Hold{
+			RepoOwner: "owner",
+			RepoName:  "repo",
+			Number:    1,
+			Holder:    holder,
+			Reason:    reason,
+		}
+	}
+
+	t.Run("Should save and list holds", func(t *testing.T) {
+		defer cleanHoldsTable(t, store)
+		require.NoError(t, holdStore.Save(newHold("alice", "waiting for QA")))
+		require.NoError(t, holdStore.Save(newHold("bob", "")))
+
+		holds, err := holdStore.List("owner", "repo", 1)
+		require.NoError(t, err)
+		require.Len(t, holds, 2)
+		require.Equal(t, "waiting for QA", holds[0].Reason)
+
+		holds, err = holdStore.List("owner", "repo", 2)
+		require.NoError(t, err)
+		require.Empty(t, holds)
+	})
+
+	t.Run("Should replace the hold of a holder", func(t *testing.T) {
+		defer cleanHoldsTable(t, store)
+		require.NoError(t, holdStore.Save(newHold("alice", "waiting for QA")))
+		require.NoError(t, holdStore.Save(newHold("alice", "waiting for UX")))
+
+		holds, err := holdStore.List("owner", "repo", 1)
+		require.NoError(t, err)
+		require.Len(t, holds, 1)
+		require.Equal(t, "waiting for UX", holds[0].Reason)
+	})
+
+	t.Run("Should delete holds", func(t *testing.T) {
+		defer cleanHoldsTable(t, store)
+		require.NoError(t, holdStore.Save(newHold("alice", "")))
+		require.NoError(t, holdStore.Save(newHold("bob", "")))
+		require.NoError(t, holdStore.Save(newHold("carol", "")))
+
+		require.NoError(t, holdStore.Delete("owner", "repo", 1, "alice"))
+		holds, err := holdStore.List("owner", "repo", 1)
+		require.NoError(t, err)
+		require.Len(t, holds, 2)
+
+		require.NoError(t, holdStore.DeleteAll("owner", "repo", 1))
+		holds, err = holdStore.List("owner", "repo", 1)
+		require.NoError(t, err)
+		require.Empty(t, holds)
+	})
+}
+
+func cleanHoldsTable(t *testing.T, store *SQLStore) {
+	if _, err := store.dbx.Exec("TRUNCATE TABLE Holds;"); err != nil {
+		require.Fail(t, "Holds table cleaning failed", err.Error())
+	}
+}
@@ -0,0 +1,56 @@
+// Copyright (c) 2017-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package store
+
+import (
+	"fmt"
+
+	"github.com/mattermost/mattermost-mattermod/model"
+)
+
+type SQLMergeQueueStore struct {
+	*SQLStore
+}
+
+func NewSQLMergeQueueStore(sqlStore *SQLStore) MergeQueueStore {
+	return &SQLMergeQueueStore{sqlStore}
+}
+
+func (s SQLMergeQueueStore) Add(entry *model.MergeQueueEntry) error {
+	if entry.CreatedAt == 0 {
+		entry.CreatedAt = model.GetMillis()
+	}
+	if _, err := s.dbx.NamedExec(
+		`INSERT INTO MergeQueue
+			(RepoOwner, RepoName, Number, CreatedAt)
+		VALUES
+			(:RepoOwner, :RepoName, :Number, :CreatedAt)
+		ON DUPLICATE KEY UPDATE
+			Number = Number`, entry); err != nil {
+		return fmt.Errorf("could not add to the merge queue: owner=%v, name=%v, number=%v, err=%w", entry.RepoOwner, entry.RepoName, entry.Number, err)
+	}
+	return nil
+}
+
+func (s SQLMergeQueueStore) List() ([]*model.MergeQueueEntry, error) {
+	var entries []*model.MergeQueueEntry
+	if err := s.dbx.Select(&entries,
+		`SELECT
+				*
+			FROM
+				MergeQueue
+			ORDER BY CreatedAt ASC, Number ASC`); err != nil {
+		return nil, fmt.Errorf("could not list the merge queue: %w", err)
+	}
+	return entries, nil
+}
+
+func (s SQLMergeQueueStore) Delete(repoOwner, repoName string, number int) error {
+	if _, err := s.dbx.Exec(
+		`DELETE FROM MergeQueue
+			WHERE RepoOwner = ? AND RepoName = ? AND Number = ?`, repoOwner, repoName, number); err != nil {
+		return fmt.Errorf("could not remove from the merge queue: owner=%v, name=%v, number=%v, err=%w", repoOwner, repoName, number, err)
+	}
+	return nil
+}
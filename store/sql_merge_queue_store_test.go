@@ -0,0 +1,77 @@
+// Copyright (c) 2017-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package store
+
+import (
+	"testing"
+
+	"github.com/mattermost/mattermost-mattermod/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMergeQueueStore(t *testing.T) {
+	store := getTestSQLStore(t)
+	queueStore := NewSQLMergeQueueStore(store)
+
+	newEntry := func(number int, createdAt int64) *model.MergeQueueEntry {
+		return &model.MergeQueueEntry{
+			RepoOwner: "owner",
+			RepoName:  "repo",
+			Number:    number,
+			CreatedAt: createdAt,
+		}
+	}
+
+	t.Run("Should list the entries in the order they were added", func(t *testing.T) {
+		defer cleanMergeQueueTable(t, store)
+		require.NoError(t, queueStore.Add(newEntry(2, 20)))
+		require.NoError(t, queueStore.Add(newEntry(1, 30)))
+		require.NoError(t, queueStore.Add(newEntry(3, 10)))
+
+		entries, err := queueStore.List()
+		require.NoError(t, err)
+		require.Len(t, entries, 3)
+		require.Equal(t, 3, entries[0].Number)
+		require.Equal(t, 2, entries[1].Number)
+		require.Equal(t, 1, entries[2].Number)
+	})
+
+	t.Run("Should keep the place of PRs added again", func(t *testing.T) {
+		defer cleanMergeQueueTable(t, store)
+		require.NoError(t, queueStore.Add(newEntry(1, 10)))
+		require.NoError(t, queueStore.Add(newEntry(2, 20)))
+		require.NoError(t, queueStore.Add(newEntry(1, 30)))
+
+		entries, err := queueStore.List()
+		require.NoError(t, err)
+		require.Len(t, entries, 2)
+		require.Equal(t, 1, entries[0].Number)
+		require.Equal(t, int64(10), entries[0].CreatedAt)
+	})
+
+	t.Run("Should set the time PRs are added", func(t *testing.T) {
+		defer cleanMergeQueueTable(t, store)
+		entry := newEntry(1, 0)
+		require.NoError(t, queueStore.Add(entry))
+		require.NotZero(t, entry.CreatedAt)
+	})
+
+	t.Run("Should remove a PR", func(t *testing.T) {
+		defer cleanMergeQueueTable(t, store)
+		require.NoError(t, queueStore.Add(newEntry(1, 10)))
+		require.NoError(t, queueStore.Add(newEntry(2, 20)))
+		require.NoError(t, queueStore.Delete("owner", "repo", 1))
+
+		entries, err := queueStore.List()
+		require.NoError(t, err)
+		require.Len(t, entries, 1)
+		require.Equal(t, 2, entries[0].Number)
+	})
+}
+
+func cleanMergeQueueTable(t *testing.T, store *SQLStore) {
+	if _, err := store.dbx.Exec("TRUNCATE TABLE MergeQueue;"); err != nil {
+		require.Fail(t, "MergeQueue table cleaning failed", err.Error())
+	}
+}
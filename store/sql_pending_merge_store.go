@@ -0,0 +1,91 @@
+// Copyright (c) 2017-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package store
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/mattermost/mattermost-mattermod/model"
+)
+
+type SQLPendingMergeStore struct {
+	*SQLStore
+}
+
+func NewSQLPendingMergeStore(sqlStore *SQLStore) PendingMergeStore {
+	return &SQLPendingMergeStore{sqlStore}
+}
+
+func (s SQLPendingMergeStore) Save(merge *model.PendingMerge) error {
+	if merge.CreatedAt == 0 {
+		merge.CreatedAt = model.GetMillis()
+	}
+	if _, err := s.dbx.NamedExec(
+		`INSERT INTO PendingMerges
+			(RepoOwner, RepoName, Number, MergeMethod, RequestedBy, CreatedAt)
+		VALUES
+			(:RepoOwner, :RepoName, :Number, :MergeMethod, :RequestedBy, :CreatedAt)
+		ON DUPLICATE KEY UPDATE
+			MergeMethod = :MergeMethod, RequestedBy = :RequestedBy, CreatedAt = :CreatedAt`, merge); err != nil {
+		return fmt.Errorf("could not save pending merge: owner=%v, name=%v, number=%v, err=%w", merge.RepoOwner, merge.RepoName, merge.Number, err)
+	}
+	return nil
+}
+
+func (s SQLPendingMergeStore) Get(repoOwner, repoName string, number int) (*model.PendingMerge, error) {
+	var merge model.PendingMerge
+	if err := s.dbx.Get(&merge,
+		`SELECT
+				*
+			FROM
+				PendingMerges
+			WHERE
+				RepoOwner = ?
+				AND RepoName = ?
+				AND Number = ?`, repoOwner, repoName, number); err != nil {
+		if err != sql.ErrNoRows {
+			return nil, fmt.Errorf("could not get pending merge: owner=%v, name=%v, number=%v, err=%w", repoOwner, repoName, number, err)
+		}
+		return nil, nil // row not found.
+	}
+	return &merge, nil
+}
+
+func (s SQLPendingMergeStore) List() ([]*model.PendingMerge, error) {
+	var merges []*model.PendingMerge
+	if err := s.dbx.Select(&merges,
+		`SELECT
+				*
+			FROM
+				PendingMerges
+			ORDER BY CreatedAt ASC`); err != nil {
+		return nil, fmt.Errorf("could not list pending merges: %w", err)
+	}
+	return merges, nil
+}
+
+func (s SQLPendingMergeStore) Delete(repoOwner, repoName string, number int) error {
+	if _, err := s.dbx.Exec(
+		`DELETE FROM PendingMerges
+			WHERE RepoOwner = ? AND RepoName = ? AND Number = ?`, repoOwner, repoName, number); err != nil {
+		return fmt.Errorf("could not delete pending merge: owner=%v, name=%v, number=%v, err=%w", repoOwner, repoName, number, err)
+	}
+	return nil
+}
+
+func (s SQLPendingMergeStore) Claim(merge *model.PendingMerge) (bool, error) {
+	res, err := s.dbx.Exec(
+		`DELETE FROM PendingMerges
+			WHERE RepoOwner = ? AND RepoName = ? AND Number = ? AND CreatedAt = ?`,
+		merge.RepoOwner, merge.RepoName, merge.Number, merge.CreatedAt)
+	if err != nil {
+		return false, fmt.Errorf("could not claim pending merge: owner=%v, name=%v, number=%v, err=%w", merge.RepoOwner, merge.RepoName, merge.Number, err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("could not claim pending merge: owner=%v, name=%v, number=%v, err=%w", merge.RepoOwner, merge.RepoName, merge.Number, err)
+	}
+	return n == 1, nil
+}
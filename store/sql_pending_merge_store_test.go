@@ -0,0 +1,103 @@
+// Copyright (c) 2017-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package store
+
+import (
+	"testing"
+
+	"github.com/mattermost/mattermost-mattermod/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPendingMergeStore(t *testing.T) {
+	store := getTestSQLStore(t)
+	mergeStore := NewSQLPendingMergeStore(store)
+
+	newMerge := func(number int) *model.PendingMerge {
+		return &model.PendingMerge{
+			RepoOwner:   "owner",
+			RepoName:    "repo",
+			Number:      number,
+			MergeMethod: "squash",
+			RequestedBy: "someone",
+		}
+	}
+
+	t.Run("Should save and get a pending merge", func(t *testing.T) {
+		defer cleanPendingMergesTable(t, store)
+		require.NoError(t, mergeStore.Save(newMerge(1)))
+
+		merge, err := mergeStore.Get("owner", "repo", 1)
+		require.NoError(t, err)
+		require.Equal(t, "squash", merge.MergeMethod)
+		require.NotZero(t, merge.CreatedAt)
+	})
+
+	t.Run("Should replace the pending merge of a PR", func(t *testing.T) {
+		defer cleanPendingMergesTable(t, store)
+		require.NoError(t, mergeStore.Save(newMerge(1)))
+		merge := newMerge(1)
+		merge.MergeMethod = "rebase"
+		require.NoError(t, mergeStore.Save(merge))
+
+		merges, err := mergeStore.List()
+		require.NoError(t, err)
+		require.Len(t, merges, 1)
+		require.Equal(t, "rebase", merges[0].MergeMethod)
+	})
+
+	t.Run("Should return empty if can't find rows with Get", func(t *testing.T) {
+		defer cleanPendingMergesTable(t, store)
+		merge, err := mergeStore.Get("owner", "repo", 1)
+		require.NoError(t, err)
+		require.Nil(t, merge)
+	})
+
+	t.Run("Should delete a pending merge", func(t *testing.T) {
+		defer cleanPendingMergesTable(t, store)
+		require.NoError(t, mergeStore.Save(newMerge(1)))
+		require.NoError(t, mergeStore.Save(newMerge(2)))
+		require.NoError(t, mergeStore.Delete("owner", "repo", 1))
+
+		merges, err := mergeStore.List()
+		require.NoError(t, err)
+		require.Len(t, merges, 1)
+		require.Equal(t, 2, merges[0].Number)
+	})
+
+	t.Run("Should claim a pending merge only once", func(t *testing.T) {
+		defer cleanPendingMergesTable(t, store)
+		require.NoError(t, mergeStore.Save(newMerge(1)))
+		merge, err := mergeStore.Get("owner", "repo", 1)
+		require.NoError(t, err)
+
+		claimed, err := mergeStore.Claim(merge)
+		require.NoError(t, err)
+		require.True(t, claimed)
+
+		claimed, err = mergeStore.Claim(merge)
+		require.NoError(t, err)
+		require.False(t, claimed)
+	})
+
+	t.Run("Should not claim a replaced pending merge", func(t *testing.T) {
+		defer cleanPendingMergesTable(t, store)
+		require.NoError(t, mergeStore.Save(newMerge(1)))
+		merge, err := mergeStore.Get("owner", "repo", 1)
+		require.NoError(t, err)
+		replaced := newMerge(1)
+		replaced.CreatedAt = merge.CreatedAt + 1
+		require.NoError(t, mergeStore.Save(replaced))
+
+		claimed, err := mergeStore.Claim(merge)
+		require.NoError(t, err)
+		require.False(t, claimed)
+	})
+}
+
+func cleanPendingMergesTable(t *testing.T, store *SQLStore) {
+	if _, err := store.dbx.Exec("TRUNCATE TABLE PendingMerges;"); err != nil {
+		require.Fail(t, "PendingMerges table cleaning failed", err.Error())
+	}
+}
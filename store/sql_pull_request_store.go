@@ -70,3 +70,19 @@ func (s SQLPullRequestStore) ListOpen() ([]*model.PullRequest, error) {
 	}
 	return prs, nil
 }
+
+func (s SQLPullRequestStore) ListBySha(repoOwner, repoName, sha string) ([]*model.PullRequest, error) {
+	var prs []*model.PullRequest
+	if err := s.dbx.Select(&prs,
+		`SELECT
+				*
+			FROM
+				PullRequests
+			WHERE
+				RepoOwner = ?
+				AND RepoName = ?
+				AND Sha = ?`, repoOwner, repoName, sha); err != nil {
+		return nil, fmt.Errorf("could not list PRs by sha: owner=%v, name=%v, sha=%v, err=%w", repoOwner, repoName, sha, err)
+	}
+	return prs, nil
+}
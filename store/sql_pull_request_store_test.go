@@ -57,4 +57,19 @@ func TestPullRequestStore(t *testing.T) {
 		require.NoError(t, err)
 		require.Empty(t, list)
 	})
+
+	t.Run("happy path on ListBySha", func(t *testing.T) {
+		pr.Sha = "sha"
+		_, err := prs.Save(pr)
+		require.NoError(t, err)
+
+		list, err := prs.ListBySha(pr.RepoOwner, pr.RepoName, "sha")
+		require.NoError(t, err)
+		require.Len(t, list, 1)
+		assert.Equal(t, pr.Number, list[0].Number)
+
+		list, err = prs.ListBySha(pr.RepoOwner, pr.RepoName, "other-sha")
+		require.NoError(t, err)
+		require.Empty(t, list)
+	})
 }
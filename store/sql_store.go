@@ -29,6 +29,12 @@ type SQLStore struct {
 	db            *sql.DB
 	pullRequest   PullRequestStore
 	issue         IssueStore
+	delivery      WebhookDeliveryStore
+	pendingMerge  PendingMergeStore
+	hold          HoldStore
+	cherryPickJob CherryPickJobStore
+	backport      BackportStore
+	mergeQueue    MergeQueueStore
 	lock          LockStore
 	SchemaVersion string
 }
@@ -63,6 +69,12 @@ func NewSQLStore(driverName, dataSource string) (Store, error) {
 
 	sqlStore.pullRequest = NewSQLPullRequestStore(sqlStore)
 	sqlStore.issue = NewSQLIssueStore(sqlStore)
+	sqlStore.delivery = NewSQLWebhookDeliveryStore(sqlStore)
+	sqlStore.pendingMerge = NewSQLPendingMergeStore(sqlStore)
+	sqlStore.hold = NewSQLHoldStore(sqlStore)
+	sqlStore.cherryPickJob = NewSQLCherryPickJobStore(sqlStore)
+	sqlStore.backport = NewSQLBackportStore(sqlStore)
+	sqlStore.mergeQueue = NewSQLMergeQueueStore(sqlStore)
 	var err error
 	sqlStore.lock, err = NewMutexStore("mattermod-lock-key", sqlStore.db)
 	if err != nil {
@@ -86,12 +98,36 @@ func (ss *SQLStore) Issue() IssueStore {
 	return ss.issue
 }
 
+func (ss *SQLStore) WebhookDelivery() WebhookDeliveryStore {
+	return ss.delivery
+}
+
+func (ss *SQLStore) PendingMerge() PendingMergeStore {
+	return ss.pendingMerge
+}
+
+func (ss *SQLStore) Hold() HoldStore {
+	return ss.hold
+}
+
+func (ss *SQLStore) CherryPickJob() CherryPickJobStore {
+	return ss.cherryPickJob
+}
+
+func (ss *SQLStore) Backport() BackportStore {
+	return ss.backport
+}
+
+func (ss *SQLStore) MergeQueue() MergeQueueStore {
+	return ss.mergeQueue
+}
+
 func (ss *SQLStore) Mutex() LockStore {
 	return ss.lock
 }
 
 func (ss *SQLStore) DropAllTables() {
-	tbls := []string{"Issues", "PullRequests", "Spinmint"}
+	tbls := []string{"Issues", "PullRequests", "Spinmint", "WebhookDeliveries", "PendingMerges", "Holds", "CherryPickJobs", "Backports", "MergeQueue"}
 	for _, t := range tbls {
 		_, err := ss.dbx.Exec("TRUNCATE TABLE " + t)
 		if err != nil {
@@ -0,0 +1,147 @@
+// Copyright (c) 2017-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package store
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	ms "github.com/go-sql-driver/mysql"
+	"github.com/mattermost/mattermost-mattermod/model"
+)
+
+// mysqlErrDuplicateEntry is returned by MySQL when an insert violates a unique key.
+const mysqlErrDuplicateEntry = 1062
+
+type SQLWebhookDeliveryStore struct {
+	*SQLStore
+}
+
+func NewSQLWebhookDeliveryStore(sqlStore *SQLStore) WebhookDeliveryStore {
+	return &SQLWebhookDeliveryStore{sqlStore}
+}
+
+func (s SQLWebhookDeliveryStore) Create(delivery *model.WebhookDelivery) (bool, error) {
+	now := model.GetMillis()
+	if delivery.State == "" {
+		delivery.State = model.DeliveryStatePending
+	}
+	delivery.CreatedAt = now
+	delivery.UpdatedAt = now
+
+	if _, err := s.dbx.NamedExec(
+		`INSERT INTO WebhookDeliveries
+			(DeliveryID, EventType, Payload, State, LastError, Attempts, NextAttemptAt, CreatedAt, UpdatedAt)
+		VALUES
+			(:DeliveryID, :EventType, :Payload, :State, :LastError, :Attempts, :NextAttemptAt, :CreatedAt, :UpdatedAt)`, delivery); err != nil {
+		var mysqlErr *ms.MySQLError
+		if errors.As(err, &mysqlErr) && mysqlErr.Number == mysqlErrDuplicateEntry {
+			return false, nil
+		}
+		return false, fmt.Errorf("could not insert webhook delivery: id=%v, err=%w", delivery.DeliveryID, err)
+	}
+	return true, nil
+}
+
+func (s SQLWebhookDeliveryStore) Get(deliveryID string) (*model.WebhookDelivery, error) {
+	var delivery model.WebhookDelivery
+	if err := s.dbx.Get(&delivery,
+		`SELECT
+				*
+			FROM
+				WebhookDeliveries
+			WHERE
+				DeliveryID = ?`, deliveryID); err != nil {
+		if err != sql.ErrNoRows {
+			return nil, fmt.Errorf("could not get webhook delivery: id=%v, err=%w", deliveryID, err)
+		}
+		return nil, nil // row not found.
+	}
+	return &delivery, nil
+}
+
+func (s SQLWebhookDeliveryStore) Claim(deliveryID string) (bool, error) {
+	res, err := s.dbx.Exec(
+		`UPDATE WebhookDeliveries
+			SET State = ?, Attempts = Attempts + 1, UpdatedAt = ?
+			WHERE DeliveryID = ? AND State = ?`,
+		model.DeliveryStateRunning, model.GetMillis(), deliveryID, model.DeliveryStatePending)
+	if err != nil {
+		return false, fmt.Errorf("could not claim webhook delivery: id=%v, err=%w", deliveryID, err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("could not claim webhook delivery: id=%v, err=%w", deliveryID, err)
+	}
+	return n == 1, nil
+}
+
+func (s SQLWebhookDeliveryStore) Update(delivery *model.WebhookDelivery) error {
+	delivery.UpdatedAt = model.GetMillis()
+	if _, err := s.dbx.NamedExec(
+		`UPDATE WebhookDeliveries
+			SET State = :State, LastError = :LastError, Attempts = :Attempts,
+				NextAttemptAt = :NextAttemptAt, UpdatedAt = :UpdatedAt
+			WHERE DeliveryID = :DeliveryID`, delivery); err != nil {
+		return fmt.Errorf("could not update webhook delivery: id=%v, err=%w", delivery.DeliveryID, err)
+	}
+	return nil
+}
+
+func (s SQLWebhookDeliveryStore) Release(deliveryID, lastError string, nextAttemptAt int64) error {
+	if _, err := s.dbx.Exec(
+		`UPDATE WebhookDeliveries
+			SET State = ?, LastError = ?, NextAttemptAt = ?, UpdatedAt = ?
+			WHERE DeliveryID = ? AND State = ?`,
+		model.DeliveryStatePending, lastError, nextAttemptAt, model.GetMillis(), deliveryID, model.DeliveryStateRunning); err != nil {
+		return fmt.Errorf("could not release webhook delivery: id=%v, err=%w", deliveryID, err)
+	}
+	return nil
+}
+
+func (s SQLWebhookDeliveryStore) Reset(deliveryID string) (bool, error) {
+	res, err := s.dbx.Exec(
+		`UPDATE WebhookDeliveries
+			SET State = ?, LastError = '', Attempts = 0, NextAttemptAt = 0, UpdatedAt = ?
+			WHERE DeliveryID = ? AND State != ?`,
+		model.DeliveryStatePending, model.GetMillis(), deliveryID, model.DeliveryStateRunning)
+	if err != nil {
+		return false, fmt.Errorf("could not reset webhook delivery: id=%v, err=%w", deliveryID, err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("could not reset webhook delivery: id=%v, err=%w", deliveryID, err)
+	}
+	return n == 1, nil
+}
+
+func (s SQLWebhookDeliveryStore) ListPending(until time.Time, limit int) ([]*model.WebhookDelivery, error) {
+	var deliveries []*model.WebhookDelivery
+	if err := s.dbx.Select(&deliveries,
+		`SELECT
+				*
+			FROM
+				WebhookDeliveries
+			WHERE
+				State = ?
+				AND NextAttemptAt <= ?
+			ORDER BY CreatedAt ASC
+			LIMIT ?`, model.DeliveryStatePending, model.GetMillisForTime(until), limit); err != nil {
+		return nil, fmt.Errorf("could not list pending webhook deliveries: %w", err)
+	}
+	return deliveries, nil
+}
+
+func (s SQLWebhookDeliveryStore) ResetRunning() error {
+	if _, err := s.dbx.Exec(
+		`UPDATE WebhookDeliveries
+			SET State = ?, UpdatedAt = ?
+			WHERE State = ?`,
+		model.DeliveryStatePending, model.GetMillis(), model.DeliveryStateRunning); err != nil {
+		return fmt.Errorf("could not reset running webhook deliveries: %w", err)
+	}
+	return nil
+}
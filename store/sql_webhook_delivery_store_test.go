@@ -0,0 +1,159 @@
+// Copyright (c) 2017-present Mattermost, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package store
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mattermost/mattermost-mattermod/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhookDeliveryStore(t *testing.T) {
+	store := getTestSQLStore(t)
+	deliveryStore := NewSQLWebhookDeliveryStore(store)
+
+	newDelivery := func() *model.WebhookDelivery {
+		return &model.WebhookDelivery{
+			DeliveryID: "delivery-id",
+			EventType:  "pull_request",
+			Payload:    []byte(`{"action":"opened"}`),
+		}
+	}
+
+	t.Run("Should create a new delivery", func(t *testing.T) {
+		defer cleanWebhookDeliveriesTable(t, store)
+		created, err := deliveryStore.Create(newDelivery())
+		require.NoError(t, err)
+		require.True(t, created)
+
+		delivery, err := deliveryStore.Get("delivery-id")
+		require.NoError(t, err)
+		require.Equal(t, model.DeliveryStatePending, delivery.State)
+		require.Equal(t, `{"action":"opened"}`, string(delivery.Payload))
+	})
+
+	t.Run("Should ignore a duplicate delivery", func(t *testing.T) {
+		defer cleanWebhookDeliveriesTable(t, store)
+		created, err := deliveryStore.Create(newDelivery())
+		require.NoError(t, err)
+		require.True(t, created)
+
+		created, err = deliveryStore.Create(newDelivery())
+		require.NoError(t, err)
+		require.False(t, created)
+	})
+
+	t.Run("Should return empty if can't find rows with Get", func(t *testing.T) {
+		defer cleanWebhookDeliveriesTable(t, store)
+		delivery, err := deliveryStore.Get("delivery-id")
+		require.NoError(t, err)
+		require.Nil(t, delivery)
+	})
+
+	t.Run("Should claim a pending delivery only once", func(t *testing.T) {
+		defer cleanWebhookDeliveriesTable(t, store)
+		_, err := deliveryStore.Create(newDelivery())
+		require.NoError(t, err)
+
+		claimed, err := deliveryStore.Claim("delivery-id")
+		require.NoError(t, err)
+		require.True(t, claimed)
+
+		claimed, err = deliveryStore.Claim("delivery-id")
+		require.NoError(t, err)
+		require.False(t, claimed)
+
+		delivery, err := deliveryStore.Get("delivery-id")
+		require.NoError(t, err)
+		require.Equal(t, model.DeliveryStateRunning, delivery.State)
+		require.Equal(t, 1, delivery.Attempts)
+	})
+
+	t.Run("Should list pending deliveries which are due", func(t *testing.T) {
+		defer cleanWebhookDeliveriesTable(t, store)
+		_, err := deliveryStore.Create(newDelivery())
+		require.NoError(t, err)
+
+		later := newDelivery()
+		later.DeliveryID = "later-id"
+		later.NextAttemptAt = model.GetMillisForTime(time.Now().Add(time.Hour))
+		_, err = deliveryStore.Create(later)
+		require.NoError(t, err)
+
+		deliveries, err := deliveryStore.ListPending(time.Now(), 10)
+		require.NoError(t, err)
+		require.Len(t, deliveries, 1)
+		require.Equal(t, "delivery-id", deliveries[0].DeliveryID)
+	})
+
+	t.Run("Should update and reset running deliveries", func(t *testing.T) {
+		defer cleanWebhookDeliveriesTable(t, store)
+		_, err := deliveryStore.Create(newDelivery())
+		require.NoError(t, err)
+		_, err = deliveryStore.Claim("delivery-id")
+		require.NoError(t, err)
+
+		require.NoError(t, deliveryStore.ResetRunning())
+		delivery, err := deliveryStore.Get("delivery-id")
+		require.NoError(t, err)
+		require.Equal(t, model.DeliveryStatePending, delivery.State)
+
+		delivery.State = model.DeliveryStateFailed
+		delivery.LastError = "some-error"
+		require.NoError(t, deliveryStore.Update(delivery))
+
+		updated, err := deliveryStore.Get("delivery-id")
+		require.NoError(t, err)
+		require.Equal(t, model.DeliveryStateFailed, updated.State)
+		require.Equal(t, "some-error", updated.LastError)
+	})
+
+	t.Run("Should reset deliveries which aren't running", func(t *testing.T) {
+		defer cleanWebhookDeliveriesTable(t, store)
+		_, err := deliveryStore.Create(newDelivery())
+		require.NoError(t, err)
+		_, err = deliveryStore.Claim("delivery-id")
+		require.NoError(t, err)
+
+		reset, err := deliveryStore.Reset("delivery-id")
+		require.NoError(t, err)
+		require.False(t, reset)
+
+		require.NoError(t, deliveryStore.Release("delivery-id", "some-error", 42))
+		reset, err = deliveryStore.Reset("delivery-id")
+		require.NoError(t, err)
+		require.True(t, reset)
+
+		delivery, err := deliveryStore.Get("delivery-id")
+		require.NoError(t, err)
+		require.Equal(t, model.DeliveryStatePending, delivery.State)
+		require.Empty(t, delivery.LastError)
+		require.Zero(t, delivery.NextAttemptAt)
+		require.Zero(t, delivery.Attempts)
+	})
+
+	t.Run("Should release running deliveries", func(t *testing.T) {
+		defer cleanWebhookDeliveriesTable(t, store)
+		_, err := deliveryStore.Create(newDelivery())
+		require.NoError(t, err)
+		_, err = deliveryStore.Claim("delivery-id")
+		require.NoError(t, err)
+
+		require.NoError(t, deliveryStore.Release("delivery-id", "some-error", 42))
+		delivery, err := deliveryStore.Get("delivery-id")
+		require.NoError(t, err)
+		require.Equal(t, model.DeliveryStatePending, delivery.State)
+		require.Equal(t, "some-error", delivery.LastError)
+		require.Equal(t, int64(42), delivery.NextAttemptAt)
+		require.Equal(t, 1, delivery.Attempts)
+	})
+}
+
+func cleanWebhookDeliveriesTable(t *testing.T, store *SQLStore) {
+	if _, err := store.dbx.Exec("TRUNCATE TABLE WebhookDeliveries;"); err != nil {
+		require.Fail(t, "WebhookDeliveries table cleaning failed", err.Error())
+	}
+}
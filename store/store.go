@@ -5,6 +5,7 @@ package store
 
 import (
 	"context"
+	"time"
 
 	"github.com/mattermost/mattermost-mattermod/model"
 )
@@ -12,6 +13,12 @@ import (
 type Store interface {
 	PullRequest() PullRequestStore
 	Issue() IssueStore
+	WebhookDelivery() WebhookDeliveryStore
+	PendingMerge() PendingMergeStore
+	Hold() HoldStore
+	CherryPickJob() CherryPickJobStore
+	Backport() BackportStore
+	MergeQueue() MergeQueueStore
 	Close()
 	DropAllTables()
 	Mutex() LockStore
@@ -21,6 +28,8 @@ type PullRequestStore interface {
 	Save(pr *model.PullRequest) (*model.PullRequest, error)
 	Get(repoOwner, repoName string, number int) (*model.PullRequest, error)
 	ListOpen() ([]*model.PullRequest, error)
+	// ListBySha returns the PRs of a repository whose head is at the given commit.
+	ListBySha(repoOwner, repoName, sha string) ([]*model.PullRequest, error)
 }
 
 type IssueStore interface {
@@ -28,6 +37,95 @@ type IssueStore interface {
 	Get(repoOwner, repoName string, number int) (*model.Issue, error)
 }
 
+// WebhookDeliveryStore persists incoming GitHub webhook deliveries so they
+// can be acknowledged immediately and processed asynchronously.
+type WebhookDeliveryStore interface {
+	// Create stores a new delivery. It returns false without an error if a
+	// delivery with the same ID already exists.
+	Create(delivery *model.WebhookDelivery) (bool, error)
+	Get(deliveryID string) (*model.WebhookDelivery, error)
+	// Claim marks a pending delivery as running and increases its attempts.
+	// It returns false if the delivery was not pending anymore.
+	Claim(deliveryID string) (bool, error)
+	Update(delivery *model.WebhookDelivery) error
+	// Release moves a running delivery back to pending, to be attempted
+	// again at nextAttemptAt, without having to load it first.
+	Release(deliveryID, lastError string, nextAttemptAt int64) error
+	// Reset moves a delivery back to pending, with its attempts and last
+	// error cleared. It returns false if the delivery is running.
+	Reset(deliveryID string) (bool, error)
+	// ListPending returns pending deliveries due for an attempt until the given time.
+	ListPending(until time.Time, limit int) ([]*model.WebhookDelivery, error)
+	// ResetRunning moves deliveries left running by a previous process back to pending.
+	ResetRunning() error
+}
+
+// PendingMergeStore persists merges requested with the /merge command until
+// the PR is ready to be merged.
+type PendingMergeStore interface {
+	// Save stores the pending merge, replacing any previous one of the PR.
+	Save(merge *model.PendingMerge) error
+	Get(repoOwner, repoName string, number int) (*model.PendingMerge, error)
+	List() ([]*model.PendingMerge, error)
+	Delete(repoOwner, repoName string, number int) error
+	// Claim deletes the pending merge unless it was replaced or deleted in
+	// the meantime. It returns false if the merge was not pending anymore.
+	Claim(merge *model.PendingMerge) (bool, error)
+}
+
+// HoldStore persists the holds placed on PRs with the /hold command.
+type HoldStore interface {
+	// Save stores the hold, replacing any previous hold of the holder on the PR.
+	Save(hold *model.Hold) error
+	List(repoOwner, repoName string, number int) ([]*model.Hold, error)
+	Delete(repoOwner, repoName string, number int, holder string) error
+	DeleteAll(repoOwner, repoName string, number int) error
+}
+
+// CherryPickJobStore persists the cherry pick jobs, so that they are resumed
+// after a restart.
+type CherryPickJobStore interface {
+	// Create stores a new job and sets its ID.
+	Create(job *model.CherryPickJob) error
+	Get(id int64) (*model.CherryPickJob, error)
+	// Claim marks a pending job as running and increases its attempts. It
+	// returns false if the job was not pending anymore.
+	Claim(id int64) (bool, error)
+	Update(job *model.CherryPickJob) error
+	// ListPending returns the pending jobs due for an attempt until the
+	// given time, oldest first.
+	ListPending(until time.Time, limit int) ([]*model.CherryPickJob, error)
+	// ListByPR returns all jobs of a PR in the order they were created.
+	ListByPR(repoOwner, repoName string, number int) ([]*model.CherryPickJob, error)
+	// ResetRunning moves jobs left running by a previous process back to pending.
+	ResetRunning() error
+}
+
+// BackportStore persists which PRs were cherry picked onto which branches,
+// and what became of the cherry pick PRs.
+type BackportStore interface {
+	// Save stores the backport of the PR onto its branch, replacing any
+	// previous one. An empty milestone keeps the stored one.
+	Save(backport *model.Backport) error
+	// GetByBackport returns the backport whose cherry pick PR has the given
+	// number, or nil.
+	GetByBackport(repoOwner, repoName string, backportNumber int) (*model.Backport, error)
+	ListByPR(repoOwner, repoName string, number int) ([]*model.Backport, error)
+	// ListByMilestone returns the backports of the PRs in a milestone of any
+	// repository, grouped by PR.
+	ListByMilestone(milestone string) ([]*model.Backport, error)
+}
+
+// MergeQueueStore persists the order in which PRs joined the merge queue.
+type MergeQueueStore interface {
+	// Add appends the PR to the queue. PRs which are queued already keep
+	// their place.
+	Add(entry *model.MergeQueueEntry) error
+	// List returns the queued PRs of all repositories, first in first.
+	List() ([]*model.MergeQueueEntry, error)
+	Delete(repoOwner, repoName string, number int) error
+}
+
 type LockStore interface {
 	Lock(ctx context.Context) error
 	Unlock() error